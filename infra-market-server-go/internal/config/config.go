@@ -9,10 +9,14 @@ import (
 
 // Config 应用配置
 type Config struct {
-	Server   ServerConfig   `toml:"server"`
-	Database DatabaseConfig `toml:"database"`
-	JWT      JWTConfig      `toml:"jwt"`
-	Redis    RedisConfig    `toml:"redis"`
+	Server    ServerConfig    `toml:"server"`
+	Database  DatabaseConfig  `toml:"database"`
+	JWT       JWTConfig       `toml:"jwt"`
+	Redis     RedisConfig     `toml:"redis"`
+	Redaction RedactionConfig `toml:"redaction"`
+	Replay    ReplayConfig    `toml:"replay"`
+	Response  ResponseConfig  `toml:"response"`
+	Security  SecurityConfig  `toml:"security"`
 }
 
 // ServerConfig 服务器配置
@@ -39,6 +43,45 @@ type RedisConfig struct {
 	DB       int    `toml:"db"`
 }
 
+// RedactionConfig 执行记录脱敏配置
+type RedactionConfig struct {
+	Headers           []string `toml:"headers"`             // 需要脱敏的请求头/响应头名称（不区分大小写）
+	RequestBodyPaths  []string `toml:"request_body_paths"`  // 请求体中需要脱敏的JSONPath
+	ResponseBodyPaths []string `toml:"response_body_paths"` // 响应体中需要脱敏的JSONPath
+}
+
+// DefaultRedactionHeaders 未配置时默认脱敏的请求头
+var DefaultRedactionHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// ReplayConfig 执行记录重放对比配置
+type ReplayConfig struct {
+	IgnoreHeaders   []string `toml:"ignore_headers"`    // 对比时忽略的响应头名称（不区分大小写）
+	IgnoreBodyPaths []string `toml:"ignore_body_paths"` // 对比时忽略的响应体JSONPath
+}
+
+// DefaultReplayIgnoreHeaders 未配置时对比忽略的响应头
+var DefaultReplayIgnoreHeaders = []string{"Date", "Expires", "Last-Modified", "Age", "Set-Cookie", "X-Request-Id", "X-Trace-Id", "Traceparent"}
+
+// ResponseConfig 接口响应体保存配置
+type ResponseConfig struct {
+	MaxBodySize         int64  `toml:"max_body_size"`          // 读取到内存并保存到执行记录的最大响应体字节数，超出部分截断
+	BlobDir             string `toml:"blob_dir"`               // 完整响应体的本地保存目录，为空时不保存
+	MaxBlobSize         int64  `toml:"max_blob_size"`          // 保存到本地目录的最大响应体字节数
+	ImagePreviewMaxSize int64  `toml:"image_preview_max_size"` // 生成内联预览的图片最大字节数，负数表示不生成
+}
+
+// SecurityConfig 敏感信息加密配置
+type SecurityConfig struct {
+	SecretKey string `toml:"secret_key"` // 加密保存认证密钥、客户端私钥、代理密码等敏感信息的密钥，修改后已保存的密钥需要重新填写
+}
+
+// 响应体保存默认配置
+const (
+	DefaultResponseMaxBodySize         int64 = 1 << 20   // 1MB
+	DefaultResponseMaxBlobSize         int64 = 100 << 20 // 100MB
+	DefaultResponseImagePreviewMaxSize int64 = 64 << 10  // 64KB
+)
+
 // Load 从配置文件加载配置
 func Load(configPath string) (*Config, error) {
 	// 读取配置文件
@@ -52,6 +95,21 @@ func Load(configPath string) (*Config, error) {
 	if err := toml.Unmarshal(data, cfg); err != nil {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
+	if len(cfg.Redaction.Headers) == 0 {
+		cfg.Redaction.Headers = DefaultRedactionHeaders
+	}
+	if len(cfg.Replay.IgnoreHeaders) == 0 {
+		cfg.Replay.IgnoreHeaders = DefaultReplayIgnoreHeaders
+	}
+	if cfg.Response.MaxBodySize <= 0 {
+		cfg.Response.MaxBodySize = DefaultResponseMaxBodySize
+	}
+	if cfg.Response.MaxBlobSize <= 0 {
+		cfg.Response.MaxBlobSize = DefaultResponseMaxBlobSize
+	}
+	if cfg.Response.ImagePreviewMaxSize == 0 {
+		cfg.Response.ImagePreviewMaxSize = DefaultResponseImagePreviewMaxSize
+	}
 
 	return cfg, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/bucketheadv/infra-market/internal/middleware"
 	"github.com/bucketheadv/infra-market/internal/repository"
 	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/bucketheadv/infra-market/internal/util"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"go.uber.org/dig"
@@ -55,6 +56,7 @@ func (c *Container) mustProvide(providers ...any) error {
 // 依赖关系：
 //   - Repository 依赖 *gorm.DB (已在 NewContainer 中注册)
 //   - TokenService 依赖 *redis.Client (通过 ProvideRedisClient 提供，依赖 *config.Config)
+//   - 保存敏感配置的 Service 依赖 *util.SecretCipher (通过 ProvideSecretCipher 提供，依赖 *config.Config)
 //   - Service 依赖 Repository 和 *gorm.DB (dig 自动注入)
 //   - Controller 依赖 Service (dig 自动注入)
 func (c *Container) registerDependencies() error {
@@ -64,6 +66,12 @@ func (c *Container) registerDependencies() error {
 		return err
 	}
 
+	// 注册敏感信息加密器提供者
+	// 依赖: *config.Config -> *util.SecretCipher
+	if err := c.mustProvide(c.ProvideSecretCipher); err != nil {
+		return err
+	}
+
 	// 注册 Repository 层
 	// 所有 Repository 构造函数接收 *gorm.DB 参数，dig 会自动注入
 	repositories := []any{
@@ -73,7 +81,18 @@ func (c *Container) registerDependencies() error {
 		repository.NewUserRoleRepository,
 		repository.NewRolePermissionRepository,
 		repository.NewApiInterfaceRepository,
+		repository.NewApiInterfaceVersionRepository,
+		repository.NewApiInterfaceGroupRepository,
+		repository.NewApiInterfaceTagRepository,
+		repository.NewApiInterfaceTagRelationRepository,
+		repository.NewApiInterfaceAclRepository,
 		repository.NewApiInterfaceExecutionRecordRepository,
+		repository.NewApiEnvironmentRepository,
+		repository.NewApiWorkflowRepository,
+		repository.NewApiScheduleRepository,
+		repository.NewApiBatchExecutionRepository,
+		repository.NewApiBatchRowResultRepository,
+		repository.NewApiAuthProfileRepository,
 		repository.NewActivityRepository,
 		repository.NewActivityTemplateRepository,
 		repository.NewActivityComponentRepository,
@@ -86,12 +105,24 @@ func (c *Container) registerDependencies() error {
 	// Service 构造函数接收 Repository 和 *gorm.DB 参数，dig 会自动注入
 	services := []any{
 		service.NewTokenService,
+		service.NewSecretRedactor,
+		service.NewResponseDiffer,
+		service.NewResponseBodyStore,
 		service.NewAuthService,
 		service.NewUserService,
 		service.NewRoleService,
 		service.NewPermissionService,
 		service.NewApiInterfaceService,
+		service.NewApiInterfaceGroupService,
+		service.NewApiInterfaceAclService,
 		service.NewApiInterfaceExecutionRecordService,
+		service.NewApiEnvironmentService,
+		service.NewApiWorkflowService,
+		service.NewApiScheduleService,
+		service.NewApiBatchExecutionService,
+		service.NewApiAuthProfileService,
+		service.NewApiTransportService,
+		service.NewApiInterfaceImportService,
 		service.NewDashboardService,
 		service.NewActivityService,
 		service.NewActivityTemplateService,
@@ -109,7 +140,15 @@ func (c *Container) registerDependencies() error {
 		controller.NewRoleController,
 		controller.NewPermissionController,
 		controller.NewApiInterfaceController,
+		controller.NewApiInterfaceGroupController,
+		controller.NewApiInterfaceAclController,
 		controller.NewApiInterfaceExecutionRecordController,
+		controller.NewApiEnvironmentController,
+		controller.NewApiWorkflowController,
+		controller.NewApiScheduleController,
+		controller.NewApiBatchExecutionController,
+		controller.NewApiAuthProfileController,
+		controller.NewApiInterfaceImportController,
 		controller.NewDashboardController,
 		controller.NewActivityController,
 		controller.NewActivityTemplateController,
@@ -131,6 +170,36 @@ func (c *Container) ProvideRedisClient(cfg *config.Config) *redis.Client {
 	})
 }
 
+// ProvideSecretCipher 提供敏感信息加密器，未配置密钥时启动失败
+func (c *Container) ProvideSecretCipher(cfg *config.Config) (*util.SecretCipher, error) {
+	return util.NewSecretCipher(cfg.Security.SecretKey)
+}
+
+// Migrate 执行启动时的数据迁移：为已有接口补充负责人
+func (c *Container) Migrate() error {
+	var migrateErr error
+	err := c.Invoke(func(aclService *service.ApiInterfaceAclService) {
+		migrateErr = aclService.BackfillOwners()
+	})
+	if err != nil {
+		return err
+	}
+	return migrateErr
+}
+
+// StartScheduler 启动接口定时调度器、批量执行中断检测与响应体文件清理
+func (c *Container) StartScheduler() error {
+	return c.Invoke(func(
+		scheduleService *service.ApiScheduleService,
+		batchService *service.ApiBatchExecutionService,
+		recordService *service.ApiInterfaceExecutionRecordService,
+	) {
+		scheduleService.Start()
+		batchService.StartRecovery()
+		recordService.StartBlobCleanup()
+	})
+}
+
 // Invoke 调用函数并自动注入依赖
 func (c *Container) Invoke(fn any) error {
 	return c.container.Invoke(fn)
@@ -145,7 +214,15 @@ func (c *Container) SetupRouter() (*gin.Engine, error) {
 		roleController *controller.RoleController,
 		permissionController *controller.PermissionController,
 		apiInterfaceController *controller.ApiInterfaceController,
+		apiInterfaceGroupController *controller.ApiInterfaceGroupController,
+		apiInterfaceAclController *controller.ApiInterfaceAclController,
 		apiInterfaceExecutionRecordController *controller.ApiInterfaceExecutionRecordController,
+		apiEnvironmentController *controller.ApiEnvironmentController,
+		apiWorkflowController *controller.ApiWorkflowController,
+		apiScheduleController *controller.ApiScheduleController,
+		apiBatchExecutionController *controller.ApiBatchExecutionController,
+		apiAuthProfileController *controller.ApiAuthProfileController,
+		apiInterfaceImportController *controller.ApiInterfaceImportController,
 		dashboardController *controller.DashboardController,
 		activityController *controller.ActivityController,
 		activityTemplateController *controller.ActivityTemplateController,
@@ -228,14 +305,122 @@ func (c *Container) SetupRouter() (*gin.Engine, error) {
 				interfaces.DELETE("/:id", apiInterfaceController.Delete)
 				interfaces.PUT("/:id/status", apiInterfaceController.UpdateStatus)
 				interfaces.POST("/:id/copy", apiInterfaceController.Copy)
+				interfaces.GET("/:id/acl", apiInterfaceAclController.List)
+				interfaces.PUT("/:id/acl", apiInterfaceAclController.Save)
 				interfaces.POST("/execute", apiInterfaceController.Execute)
+				interfaces.POST("/snippet", apiInterfaceController.Snippet)
+			}
+
+			// 接口版本管理
+			versions := api.Group("/interface/version")
+			{
+				versions.GET("/list", apiInterfaceController.VersionList)
+				versions.GET("/diff", apiInterfaceController.VersionDiff)
+				versions.GET("/:id", apiInterfaceController.VersionDetail)
+				versions.POST("/:id/restore", apiInterfaceController.RestoreVersion)
+			}
+
+			// 接口分组管理
+			groups := api.Group("/interface/group")
+			{
+				groups.GET("/tree", apiInterfaceGroupController.Tree)
+				groups.GET("/:id", apiInterfaceGroupController.Detail)
+				groups.POST("", apiInterfaceGroupController.Create)
+				groups.PUT("/:id", apiInterfaceGroupController.Update)
+				groups.DELETE("/:id", apiInterfaceGroupController.Delete)
+				groups.POST("/move", apiInterfaceGroupController.MoveInterfaces)
+			}
+
+			// 接口标签管理
+			tags := api.Group("/interface/tag")
+			{
+				tags.GET("/all", apiInterfaceGroupController.TagList)
+				tags.DELETE("/:id", apiInterfaceGroupController.DeleteTag)
+			}
+
+			// 执行环境管理
+			environments := api.Group("/interface/environment")
+			{
+				environments.GET("/list", apiEnvironmentController.List)
+				environments.GET("/all", apiEnvironmentController.GetAll)
+				environments.GET("/:id", apiEnvironmentController.Detail)
+				environments.POST("", apiEnvironmentController.Create)
+				environments.PUT("/:id", apiEnvironmentController.Update)
+				environments.DELETE("/:id", apiEnvironmentController.Delete)
+				environments.PUT("/:id/status", apiEnvironmentController.UpdateStatus)
+			}
+
+			// 接口工作流管理
+			workflows := api.Group("/interface/workflow")
+			{
+				workflows.GET("/list", apiWorkflowController.List)
+				workflows.GET("/:id", apiWorkflowController.Detail)
+				workflows.POST("", apiWorkflowController.Create)
+				workflows.PUT("/:id", apiWorkflowController.Update)
+				workflows.DELETE("/:id", apiWorkflowController.Delete)
+				workflows.PUT("/:id/status", apiWorkflowController.UpdateStatus)
+				workflows.POST("/:id/run", apiWorkflowController.Run)
+				workflows.GET("/run/list", apiWorkflowController.RunList)
+				workflows.GET("/run/:id", apiWorkflowController.RunDetail)
+			}
+
+			// 接口定时计划管理
+			schedules := api.Group("/interface/schedule")
+			{
+				schedules.GET("/list", apiScheduleController.List)
+				schedules.GET("/:id", apiScheduleController.Detail)
+				schedules.POST("", apiScheduleController.Create)
+				schedules.PUT("/:id", apiScheduleController.Update)
+				schedules.DELETE("/:id", apiScheduleController.Delete)
+				schedules.PUT("/:id/pause", apiScheduleController.Pause)
+				schedules.PUT("/:id/resume", apiScheduleController.Resume)
+				schedules.POST("/:id/run", apiScheduleController.RunNow)
+			}
+
+			// 接口批量执行
+			batches := api.Group("/interface/batch")
+			{
+				batches.POST("/execute", apiBatchExecutionController.Execute)
+				batches.GET("/list", apiBatchExecutionController.List)
+				batches.GET("/:id", apiBatchExecutionController.Detail)
+				batches.GET("/:id/download", apiBatchExecutionController.Download)
+			}
+
+			// 接口认证配置管理
+			authProfiles := api.Group("/interface/auth/profile")
+			{
+				authProfiles.GET("/list", apiAuthProfileController.List)
+				authProfiles.GET("/all", apiAuthProfileController.GetAll)
+				authProfiles.GET("/:id", apiAuthProfileController.Detail)
+				authProfiles.POST("", apiAuthProfileController.Create)
+				authProfiles.PUT("/:id", apiAuthProfileController.Update)
+				authProfiles.DELETE("/:id", apiAuthProfileController.Delete)
+				authProfiles.PUT("/:id/status", apiAuthProfileController.UpdateStatus)
+			}
+
+			// 接口导入
+			imports := api.Group("/interface/import")
+			{
+				imports.POST("/openapi", apiInterfaceImportController.ImportOpenAPI)
+				imports.POST("/curl", apiInterfaceImportController.ImportCurl)
+				imports.POST("/postman", apiInterfaceImportController.ImportPostman)
+			}
+
+			// 接口导出
+			exports := api.Group("/interface/export")
+			{
+				exports.POST("/postman", apiInterfaceImportController.ExportPostman)
 			}
 
 			// 执行记录管理
 			executionRecords := api.Group("/interface/execution/record")
 			{
 				executionRecords.POST("/list", apiInterfaceExecutionRecordController.List)
+				executionRecords.POST("/export/har", apiInterfaceExecutionRecordController.ExportHar)
 				executionRecords.GET("/:id", apiInterfaceExecutionRecordController.Detail)
+				executionRecords.GET("/:id/snippet", apiInterfaceExecutionRecordController.Snippet)
+				executionRecords.POST("/:id/replay", apiInterfaceExecutionRecordController.Replay)
+				executionRecords.GET("/:id/response-body", apiInterfaceExecutionRecordController.DownloadResponseBody)
 				executionRecords.GET("/executor/:executorId", apiInterfaceExecutionRecordController.GetByExecutorID)
 				executionRecords.GET("/stats/:interfaceId", apiInterfaceExecutionRecordController.GetExecutionStats)
 				executionRecords.GET("/count", apiInterfaceExecutionRecordController.GetExecutionCount)
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiAuthProfileController struct {
+	authProfileService *service.ApiAuthProfileService
+}
+
+func NewApiAuthProfileController(authProfileService *service.ApiAuthProfileService) *ApiAuthProfileController {
+	return &ApiAuthProfileController{authProfileService: authProfileService}
+}
+
+// List 获取认证配置列表
+func (c *ApiAuthProfileController) List(ctx *gin.Context) {
+	var query dto.ApiAuthProfileQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	result := c.authProfileService.FindPage(query)
+	ctx.JSON(200, result)
+}
+
+// GetAll 获取所有启用的认证配置
+func (c *ApiAuthProfileController) GetAll(ctx *gin.Context) {
+	result := c.authProfileService.FindAllEnabled()
+	ctx.JSON(200, result)
+}
+
+// Detail 获取认证配置详情
+func (c *ApiAuthProfileController) Detail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的认证配置ID", 400))
+		return
+	}
+
+	result := c.authProfileService.FindByID(uriParam.ID)
+	ctx.JSON(200, result)
+}
+
+// Create 创建认证配置
+func (c *ApiAuthProfileController) Create(ctx *gin.Context) {
+	var form dto.ApiAuthProfileFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.authProfileService.Save(form, uid)
+	ctx.JSON(200, result)
+}
+
+// Update 更新认证配置
+func (c *ApiAuthProfileController) Update(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的认证配置ID", 400))
+		return
+	}
+
+	var form dto.ApiAuthProfileFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.authProfileService.Update(uriParam.ID, form, uid)
+	ctx.JSON(200, result)
+}
+
+// Delete 删除认证配置
+func (c *ApiAuthProfileController) Delete(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的认证配置ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.authProfileService.Delete(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// UpdateStatus 更新认证配置状态
+func (c *ApiAuthProfileController) UpdateStatus(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的认证配置ID", 400))
+		return
+	}
+
+	var query dto.StatusQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.authProfileService.UpdateStatus(uriParam.ID, *query.Status, uid)
+	ctx.JSON(200, result)
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiBatchExecutionController struct {
+	batchService *service.ApiBatchExecutionService
+}
+
+func NewApiBatchExecutionController(batchService *service.ApiBatchExecutionService) *ApiBatchExecutionController {
+	return &ApiBatchExecutionController{batchService: batchService}
+}
+
+// Execute 批量执行接口
+func (c *ApiBatchExecutionController) Execute(ctx *gin.Context) {
+	uid, ok := middleware.GetUIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(401, dto.Error[any]("未登录", 401))
+		return
+	}
+
+	req, err := bindBatchExecuteRequest(ctx)
+	if err != nil {
+		ctx.JSON(400, dto.Error[any](err.Error(), 400))
+		return
+	}
+
+	result := c.batchService.Execute(req, uid, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+	ctx.JSON(200, result)
+}
+
+// List 获取批量执行列表
+func (c *ApiBatchExecutionController) List(ctx *gin.Context) {
+	var query dto.ApiBatchExecutionQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.batchService.FindPage(query, uid)
+	ctx.JSON(200, result)
+}
+
+// Detail 获取批量执行汇总
+func (c *ApiBatchExecutionController) Detail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的批次ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.batchService.FindByID(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// Download 下载批量执行结果CSV
+func (c *ApiBatchExecutionController) Download(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的批次ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.batchService.ExportCSV(uriParam.ID, uid)
+	if result.Code != 200 {
+		ctx.JSON(200, result)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=batch-%d.csv", uriParam.ID))
+	ctx.Data(200, "text/csv; charset=utf-8", result.Data)
+}
+
+// bindBatchExecuteRequest 绑定批量执行请求
+// 支持 JSON 请求体；multipart/form-data 请求时 request 字段为 JSON，file 字段为 CSV 或 JSON 数据集
+func bindBatchExecuteRequest(ctx *gin.Context) (dto.ApiBatchExecuteRequestDto, error) {
+	var req dto.ApiBatchExecuteRequestDto
+	if ctx.ContentType() != "multipart/form-data" {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return req, errors.New("参数校验失败")
+		}
+		return req, nil
+	}
+
+	if value := ctx.PostForm("request"); value != "" {
+		if err := json.Unmarshal([]byte(value), &req); err != nil {
+			return req, errors.New("参数校验失败")
+		}
+	}
+	if req.InterfaceID == nil {
+		return req, errors.New("接口ID不能为空")
+	}
+	if req.Concurrency != nil && (*req.Concurrency < 1 || *req.Concurrency > 20) {
+		return req, errors.New("并发数必须在1到20之间")
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return req, errors.New("请上传数据集文件")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return req, err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return req, err
+	}
+
+	rows, err := service.ParseBatchDataset(fileHeader.Filename, content)
+	if err != nil {
+		return req, err
+	}
+	req.Rows = rows
+	req.FileName = &fileHeader.Filename
+	return req, nil
+}
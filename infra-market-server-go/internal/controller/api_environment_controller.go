@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiEnvironmentController struct {
+	environmentService *service.ApiEnvironmentService
+}
+
+func NewApiEnvironmentController(environmentService *service.ApiEnvironmentService) *ApiEnvironmentController {
+	return &ApiEnvironmentController{environmentService: environmentService}
+}
+
+// List 获取环境列表
+func (c *ApiEnvironmentController) List(ctx *gin.Context) {
+	var query dto.ApiEnvironmentQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	result := c.environmentService.FindPage(query)
+	ctx.JSON(200, result)
+}
+
+// GetAll 获取所有启用的环境
+func (c *ApiEnvironmentController) GetAll(ctx *gin.Context) {
+	result := c.environmentService.FindAllEnabled()
+	ctx.JSON(200, result)
+}
+
+// Detail 获取环境详情
+func (c *ApiEnvironmentController) Detail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的环境ID", 400))
+		return
+	}
+
+	result := c.environmentService.FindByID(uriParam.ID)
+	ctx.JSON(200, result)
+}
+
+// Create 创建环境
+func (c *ApiEnvironmentController) Create(ctx *gin.Context) {
+	var form dto.ApiEnvironmentFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.environmentService.Save(form, uid)
+	ctx.JSON(200, result)
+}
+
+// Update 更新环境
+func (c *ApiEnvironmentController) Update(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的环境ID", 400))
+		return
+	}
+
+	var form dto.ApiEnvironmentFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.environmentService.Update(uriParam.ID, form, uid)
+	ctx.JSON(200, result)
+}
+
+// Delete 删除环境
+func (c *ApiEnvironmentController) Delete(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的环境ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.environmentService.Delete(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// UpdateStatus 更新环境状态
+func (c *ApiEnvironmentController) UpdateStatus(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的环境ID", 400))
+		return
+	}
+
+	var query dto.StatusQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.environmentService.UpdateStatus(uriParam.ID, *query.Status, uid)
+	ctx.JSON(200, result)
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiInterfaceAclController struct {
+	aclService *service.ApiInterfaceAclService
+}
+
+func NewApiInterfaceAclController(aclService *service.ApiInterfaceAclService) *ApiInterfaceAclController {
+	return &ApiInterfaceAclController{aclService: aclService}
+}
+
+// List 获取接口的负责人与授权
+func (c *ApiInterfaceAclController) List(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的接口ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.aclService.List(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// Save 保存接口的负责人与授权
+func (c *ApiInterfaceAclController) Save(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的接口ID", 400))
+		return
+	}
+
+	var form dto.ApiInterfaceAclFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.aclService.Save(uriParam.ID, form, uid)
+	ctx.JSON(200, result)
+}
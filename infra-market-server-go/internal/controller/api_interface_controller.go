@@ -1,6 +1,10 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+
 	"github.com/bucketheadv/infra-market/internal/dto"
 	"github.com/bucketheadv/infra-market/internal/middleware"
 	"github.com/bucketheadv/infra-market/internal/service"
@@ -23,7 +27,8 @@ func (c *ApiInterfaceController) List(ctx *gin.Context) {
 		return
 	}
 
-	result := c.apiInterfaceService.FindPage(query)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.FindPage(query, uid)
 	ctx.JSON(200, result)
 }
 
@@ -35,7 +40,8 @@ func (c *ApiInterfaceController) Detail(ctx *gin.Context) {
 		return
 	}
 
-	result := c.apiInterfaceService.FindByID(uriParam.ID)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.FindByID(uriParam.ID, uid)
 	ctx.JSON(200, result)
 }
 
@@ -47,7 +53,8 @@ func (c *ApiInterfaceController) Create(ctx *gin.Context) {
 		return
 	}
 
-	result := c.apiInterfaceService.Save(form)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.Save(form, uid)
 	ctx.JSON(200, result)
 }
 
@@ -65,7 +72,8 @@ func (c *ApiInterfaceController) Update(ctx *gin.Context) {
 		return
 	}
 
-	result := c.apiInterfaceService.Update(uriParam.ID, form)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.Update(uriParam.ID, form, uid)
 	ctx.JSON(200, result)
 }
 
@@ -77,7 +85,8 @@ func (c *ApiInterfaceController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	result := c.apiInterfaceService.Delete(uriParam.ID)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.Delete(uriParam.ID, uid)
 	ctx.JSON(200, result)
 }
 
@@ -95,7 +104,8 @@ func (c *ApiInterfaceController) UpdateStatus(ctx *gin.Context) {
 		return
 	}
 
-	result := c.apiInterfaceService.UpdateStatus(uriParam.ID, *query.Status)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.UpdateStatus(uriParam.ID, *query.Status, uid)
 	ctx.JSON(200, result)
 }
 
@@ -107,7 +117,73 @@ func (c *ApiInterfaceController) Copy(ctx *gin.Context) {
 		return
 	}
 
-	result := c.apiInterfaceService.Copy(uriParam.ID)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.Copy(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// VersionList 获取接口的版本列表
+func (c *ApiInterfaceController) VersionList(ctx *gin.Context) {
+	var query dto.ApiInterfaceVersionQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.ListVersions(query, uid)
+	ctx.JSON(200, result)
+}
+
+// VersionDetail 获取接口版本详情
+func (c *ApiInterfaceController) VersionDetail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的版本ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.FindVersion(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// VersionDiff 对比接口的两个版本
+func (c *ApiInterfaceController) VersionDiff(ctx *gin.Context) {
+	var query dto.ApiInterfaceVersionDiffQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.DiffVersions(query.FromID, query.ToID, uid)
+	ctx.JSON(200, result)
+}
+
+// RestoreVersion 将接口恢复为指定版本
+func (c *ApiInterfaceController) RestoreVersion(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的版本ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.RestoreVersion(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// Snippet 根据接口与参数取值生成代码片段
+func (c *ApiInterfaceController) Snippet(ctx *gin.Context) {
+	var req dto.ApiExecuteRequestDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.BuildSnippet(req, uid)
 	ctx.JSON(200, result)
 }
 
@@ -129,7 +205,8 @@ func (c *ApiInterfaceController) GetMostUsed(ctx *gin.Context) {
 		limit = *query.Limit
 	}
 
-	result := c.apiInterfaceService.FindMostUsedInterfaces(days, limit)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.apiInterfaceService.FindMostUsedInterfaces(days, limit, uid)
 	ctx.JSON(200, result)
 }
 
@@ -141,8 +218,8 @@ func (c *ApiInterfaceController) Execute(ctx *gin.Context) {
 		return
 	}
 
-	var req dto.ApiExecuteRequestDto
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	req, err := bindExecuteRequest(ctx)
+	if err != nil {
 		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
 		return
 	}
@@ -153,3 +230,47 @@ func (c *ApiInterfaceController) Execute(ctx *gin.Context) {
 	result := c.apiInterfaceService.Execute(req, uid, clientIP, userAgent)
 	ctx.JSON(200, result)
 }
+
+// bindExecuteRequest 绑定接口执行请求
+// 支持 JSON 请求体；multipart/form-data 请求时 request 字段为 JSON，其余文件字段按参数名作为上传文件
+func bindExecuteRequest(ctx *gin.Context) (dto.ApiExecuteRequestDto, error) {
+	var req dto.ApiExecuteRequestDto
+	if ctx.ContentType() != "multipart/form-data" {
+		err := ctx.ShouldBindJSON(&req)
+		return req, err
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return req, err
+	}
+	if values := form.Value["request"]; len(values) > 0 {
+		if err := json.Unmarshal([]byte(values[0]), &req); err != nil {
+			return req, err
+		}
+	}
+	if req.InterfaceID == nil {
+		return req, errors.New("接口ID不能为空")
+	}
+
+	for paramName, fileHeaders := range form.File {
+		for _, fileHeader := range fileHeaders {
+			file, err := fileHeader.Open()
+			if err != nil {
+				return req, err
+			}
+			content, err := io.ReadAll(file)
+			_ = file.Close()
+			if err != nil {
+				return req, err
+			}
+			req.Files = append(req.Files, dto.ApiUploadFileDto{
+				ParamName:   paramName,
+				FileName:    fileHeader.Filename,
+				ContentType: fileHeader.Header.Get("Content-Type"),
+				Content:     content,
+			})
+		}
+	}
+	return req, nil
+}
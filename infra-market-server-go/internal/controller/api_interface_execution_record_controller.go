@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"fmt"
+
 	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
 	"github.com/bucketheadv/infra-market/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -22,7 +25,8 @@ func (c *ApiInterfaceExecutionRecordController) List(ctx *gin.Context) {
 		return
 	}
 
-	result := c.service.FindPage(query)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.FindPage(query, uid)
 	ctx.JSON(200, result)
 }
 
@@ -34,10 +38,102 @@ func (c *ApiInterfaceExecutionRecordController) Detail(ctx *gin.Context) {
 		return
 	}
 
-	result := c.service.GetByID(uriParam.ID)
+	var query dto.ApiInterfaceExecutionRecordDetailQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.GetByID(uriParam.ID, uid, query.Unmasked != nil && *query.Unmasked)
+	ctx.JSON(200, result)
+}
+
+// Snippet 将执行记录的请求生成代码片段
+func (c *ApiInterfaceExecutionRecordController) Snippet(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的记录ID", 400))
+		return
+	}
+
+	var query dto.ApiInterfaceExecutionRecordDetailQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.GetSnippet(uriParam.ID, uid, query.Unmasked != nil && *query.Unmasked)
+	ctx.JSON(200, result)
+}
+
+// DownloadResponseBody 下载执行记录的响应体
+func (c *ApiInterfaceExecutionRecordController) DownloadResponseBody(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的记录ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.DownloadResponseBody(uriParam.ID, uid)
+	if result.Code != 200 {
+		ctx.JSON(200, result)
+		return
+	}
+	defer result.Data.Reader.Close()
+
+	ctx.DataFromReader(200, result.Data.Size, result.Data.ContentType, result.Data.Reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%s", result.Data.FileName),
+	})
+}
+
+// Replay 重放执行记录并对比响应
+func (c *ApiInterfaceExecutionRecordController) Replay(ctx *gin.Context) {
+	uid, ok := middleware.GetUIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(401, dto.Error[any]("未登录", 401))
+		return
+	}
+
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的记录ID", 400))
+		return
+	}
+
+	var req dto.ApiExecutionReplayRequestDto
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+			return
+		}
+	}
+
+	result := c.service.Replay(uriParam.ID, req, uid, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
 	ctx.JSON(200, result)
 }
 
+// ExportHar 将筛选出的执行记录导出为 HAR 文件
+func (c *ApiInterfaceExecutionRecordController) ExportHar(ctx *gin.Context) {
+	var query dto.ApiInterfaceExecutionRecordQueryDto
+	if err := ctx.ShouldBindJSON(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.ExportHar(query, uid)
+	if result.Code != 200 {
+		ctx.JSON(200, result)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=execution-records.har")
+	ctx.Data(200, "application/json; charset=utf-8", result.Data)
+}
+
 // GetByExecutorID 根据执行人ID查询
 func (c *ApiInterfaceExecutionRecordController) GetByExecutorID(ctx *gin.Context) {
 	var uriParam dto.ExecutorIDUriParam
@@ -57,7 +153,8 @@ func (c *ApiInterfaceExecutionRecordController) GetByExecutorID(ctx *gin.Context
 		limit = *query.Limit
 	}
 
-	result := c.service.FindByExecutorID(uriParam.ExecutorID, limit)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.FindByExecutorID(uriParam.ExecutorID, limit, uid)
 	ctx.JSON(200, result)
 }
 
@@ -69,7 +166,8 @@ func (c *ApiInterfaceExecutionRecordController) GetExecutionStats(ctx *gin.Conte
 		return
 	}
 
-	result := c.service.GetExecutionStats(uriParam.InterfaceID)
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.GetExecutionStats(uriParam.InterfaceID, uid)
 	ctx.JSON(200, result)
 }
 
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiInterfaceGroupController struct {
+	groupService *service.ApiInterfaceGroupService
+}
+
+func NewApiInterfaceGroupController(groupService *service.ApiInterfaceGroupService) *ApiInterfaceGroupController {
+	return &ApiInterfaceGroupController{groupService: groupService}
+}
+
+// Tree 获取分组树
+func (c *ApiInterfaceGroupController) Tree(ctx *gin.Context) {
+	result := c.groupService.Tree()
+	ctx.JSON(200, result)
+}
+
+// Detail 获取分组详情
+func (c *ApiInterfaceGroupController) Detail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的分组ID", 400))
+		return
+	}
+
+	result := c.groupService.FindByID(uriParam.ID)
+	ctx.JSON(200, result)
+}
+
+// Create 创建分组
+func (c *ApiInterfaceGroupController) Create(ctx *gin.Context) {
+	var form dto.ApiInterfaceGroupFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.groupService.Save(form, uid)
+	ctx.JSON(200, result)
+}
+
+// Update 更新分组
+func (c *ApiInterfaceGroupController) Update(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的分组ID", 400))
+		return
+	}
+
+	var form dto.ApiInterfaceGroupFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.groupService.Update(uriParam.ID, form, uid)
+	ctx.JSON(200, result)
+}
+
+// Delete 删除分组，需要指定子分组与接口的处理策略
+func (c *ApiInterfaceGroupController) Delete(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的分组ID", 400))
+		return
+	}
+
+	var query dto.ApiInterfaceGroupDeleteQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("请选择删除策略", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.groupService.Delete(uriParam.ID, *query.Strategy, uid)
+	ctx.JSON(200, result)
+}
+
+// MoveInterfaces 批量移动接口到指定分组
+func (c *ApiInterfaceGroupController) MoveInterfaces(ctx *gin.Context) {
+	var form dto.ApiInterfaceMoveDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.groupService.MoveInterfaces(form, uid)
+	ctx.JSON(200, result)
+}
+
+// TagList 获取全部标签
+func (c *ApiInterfaceGroupController) TagList(ctx *gin.Context) {
+	result := c.groupService.ListTags()
+	ctx.JSON(200, result)
+}
+
+// DeleteTag 删除标签
+func (c *ApiInterfaceGroupController) DeleteTag(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的标签ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.groupService.DeleteTag(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
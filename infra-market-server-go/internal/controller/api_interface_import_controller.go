@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiInterfaceImportController struct {
+	service *service.ApiInterfaceImportService
+}
+
+func NewApiInterfaceImportController(service *service.ApiInterfaceImportService) *ApiInterfaceImportController {
+	return &ApiInterfaceImportController{service: service}
+}
+
+// ImportOpenAPI 从 OpenAPI 3 / Swagger 2 规范导入接口
+func (c *ApiInterfaceImportController) ImportOpenAPI(ctx *gin.Context) {
+	req, err := bindImportRequest(ctx)
+	if err != nil {
+		ctx.JSON(400, dto.Error[any](err.Error(), 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.ImportOpenAPI(req, uid)
+	ctx.JSON(200, result)
+}
+
+// ImportCurl 将 curl 命令解析为接口表单草稿
+func (c *ApiInterfaceImportController) ImportCurl(ctx *gin.Context) {
+	var req dto.ApiCurlImportRequestDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	result := c.service.ParseCurl(req)
+	ctx.JSON(200, result)
+}
+
+// ImportPostman 从 Postman Collection v2.1 导入接口
+func (c *ApiInterfaceImportController) ImportPostman(ctx *gin.Context) {
+	req, err := bindImportRequest(ctx)
+	if err != nil {
+		ctx.JSON(400, dto.Error[any](err.Error(), 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.ImportPostman(req, uid)
+	ctx.JSON(200, result)
+}
+
+// ExportPostman 将选中的接口导出为 Postman Collection v2.1 文件
+func (c *ApiInterfaceImportController) ExportPostman(ctx *gin.Context) {
+	var req dto.ApiPostmanExportRequestDto
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.service.ExportPostman(req, uid)
+	if result.Code != 200 {
+		ctx.JSON(200, result)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=collection.postman_collection.json")
+	ctx.Data(200, "application/json; charset=utf-8", result.Data)
+}
+
+// bindImportRequest 绑定导入请求
+// 支持 JSON 请求体；multipart/form-data 请求时 request 字段为 JSON，file 字段为待导入的文件
+func bindImportRequest(ctx *gin.Context) (dto.ApiInterfaceImportRequestDto, error) {
+	var req dto.ApiInterfaceImportRequestDto
+	if ctx.ContentType() != "multipart/form-data" {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return req, errors.New("参数校验失败")
+		}
+		return req, nil
+	}
+
+	if value := ctx.PostForm("request"); value != "" {
+		if err := json.Unmarshal([]byte(value), &req); err != nil {
+			return req, errors.New("参数校验失败")
+		}
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return req, errors.New("请上传导入文件")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return req, err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return req, err
+	}
+	text := string(content)
+	req.Content = &text
+	return req, nil
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiScheduleController struct {
+	scheduleService *service.ApiScheduleService
+}
+
+func NewApiScheduleController(scheduleService *service.ApiScheduleService) *ApiScheduleController {
+	return &ApiScheduleController{scheduleService: scheduleService}
+}
+
+// List 获取定时计划列表
+func (c *ApiScheduleController) List(ctx *gin.Context) {
+	var query dto.ApiScheduleQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.FindPage(query, uid)
+	ctx.JSON(200, result)
+}
+
+// Detail 获取定时计划详情
+func (c *ApiScheduleController) Detail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的计划ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.FindByID(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// Create 创建定时计划
+func (c *ApiScheduleController) Create(ctx *gin.Context) {
+	var form dto.ApiScheduleFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.Save(form, uid)
+	ctx.JSON(200, result)
+}
+
+// Update 更新定时计划
+func (c *ApiScheduleController) Update(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的计划ID", 400))
+		return
+	}
+
+	var form dto.ApiScheduleFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.Update(uriParam.ID, form, uid)
+	ctx.JSON(200, result)
+}
+
+// Delete 删除定时计划
+func (c *ApiScheduleController) Delete(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的计划ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.Delete(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// Pause 暂停定时计划
+func (c *ApiScheduleController) Pause(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的计划ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.Pause(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// Resume 恢复定时计划
+func (c *ApiScheduleController) Resume(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的计划ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.Resume(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
+
+// RunNow 立即执行定时计划
+func (c *ApiScheduleController) RunNow(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的计划ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.scheduleService.RunNow(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
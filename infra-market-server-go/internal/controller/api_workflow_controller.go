@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/middleware"
+	"github.com/bucketheadv/infra-market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ApiWorkflowController struct {
+	workflowService *service.ApiWorkflowService
+}
+
+func NewApiWorkflowController(workflowService *service.ApiWorkflowService) *ApiWorkflowController {
+	return &ApiWorkflowController{workflowService: workflowService}
+}
+
+// List 获取工作流列表
+func (c *ApiWorkflowController) List(ctx *gin.Context) {
+	var query dto.ApiWorkflowQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	result := c.workflowService.FindPage(query)
+	ctx.JSON(200, result)
+}
+
+// Detail 获取工作流详情
+func (c *ApiWorkflowController) Detail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的工作流ID", 400))
+		return
+	}
+
+	result := c.workflowService.FindByID(uriParam.ID)
+	ctx.JSON(200, result)
+}
+
+// Create 创建工作流
+func (c *ApiWorkflowController) Create(ctx *gin.Context) {
+	var form dto.ApiWorkflowFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	result := c.workflowService.Save(form)
+	ctx.JSON(200, result)
+}
+
+// Update 更新工作流
+func (c *ApiWorkflowController) Update(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的工作流ID", 400))
+		return
+	}
+
+	var form dto.ApiWorkflowFormDto
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	result := c.workflowService.Update(uriParam.ID, form)
+	ctx.JSON(200, result)
+}
+
+// Delete 删除工作流
+func (c *ApiWorkflowController) Delete(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的工作流ID", 400))
+		return
+	}
+
+	result := c.workflowService.Delete(uriParam.ID)
+	ctx.JSON(200, result)
+}
+
+// UpdateStatus 更新工作流状态
+func (c *ApiWorkflowController) UpdateStatus(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的工作流ID", 400))
+		return
+	}
+
+	var query dto.StatusQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	result := c.workflowService.UpdateStatus(uriParam.ID, *query.Status)
+	ctx.JSON(200, result)
+}
+
+// Run 执行工作流
+func (c *ApiWorkflowController) Run(ctx *gin.Context) {
+	uid, ok := middleware.GetUIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(401, dto.Error[any]("未登录", 401))
+		return
+	}
+
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的工作流ID", 400))
+		return
+	}
+
+	var req dto.ApiWorkflowRunRequestDto
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+			return
+		}
+	}
+
+	result := c.workflowService.Run(uriParam.ID, req, uid, ctx.ClientIP(), ctx.GetHeader("User-Agent"))
+	ctx.JSON(200, result)
+}
+
+// RunList 获取工作流执行记录列表
+func (c *ApiWorkflowController) RunList(ctx *gin.Context) {
+	var query dto.ApiWorkflowRunQueryDto
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(400, dto.Error[any]("参数校验失败", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.workflowService.FindRunPage(query, uid)
+	ctx.JSON(200, result)
+}
+
+// RunDetail 获取工作流执行记录详情
+func (c *ApiWorkflowController) RunDetail(ctx *gin.Context) {
+	var uriParam dto.IDUriParam
+	if err := ctx.ShouldBindUri(&uriParam); err != nil {
+		ctx.JSON(400, dto.Error[any]("无效的执行记录ID", 400))
+		return
+	}
+
+	uid, _ := middleware.GetUIDFromContext(ctx)
+	result := c.workflowService.FindRunByID(uriParam.ID, uid)
+	ctx.JSON(200, result)
+}
@@ -33,6 +33,18 @@ func InitDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
 // 		&entity.UserRole{},
 // 		&entity.RolePermission{},
 // 		&entity.ApiInterface{},
+// 		&entity.ApiInterfaceVersion{},
+// 		&entity.ApiInterfaceGroup{},
+// 		&entity.ApiInterfaceTag{},
+// 		&entity.ApiInterfaceTagRelation{},
+// 		&entity.ApiInterfaceAcl{},
 // 		&entity.ApiInterfaceExecutionRecord{},
+// 		&entity.ApiEnvironment{},
+// 		&entity.ApiWorkflow{},
+// 		&entity.ApiWorkflowRun{},
+// 		&entity.ApiSchedule{},
+// 		&entity.ApiBatchExecution{},
+// 		&entity.ApiBatchRowResult{},
+// 		&entity.ApiAuthProfile{},
 // 	)
 // }
@@ -0,0 +1,52 @@
+package dto
+
+// ApiAuthProfileDto 接口认证配置DTO，不返回密钥明文
+type ApiAuthProfileDto struct {
+	ID          uint64                  `json:"id"`
+	Name        string                  `json:"name"`
+	Type        string                  `json:"type"`
+	Config      ApiAuthProfileConfigDto `json:"config"`
+	HasSecret   bool                    `json:"hasSecret"`
+	Description *string                 `json:"description"`
+	Status      int                     `json:"status"`
+	CreateTime  string                  `json:"createTime"`
+	UpdateTime  string                  `json:"updateTime"`
+}
+
+// ApiAuthProfileFormDto 接口认证配置创建/更新表单
+// Secret 为密码、Token、API Key、HMAC 密钥或 OAuth2 Client Secret；更新时为空表示保留原密钥
+type ApiAuthProfileFormDto struct {
+	Name        string                  `json:"name" binding:"required,min=1,max=100"`
+	Type        string                  `json:"type" binding:"required"`
+	Config      ApiAuthProfileConfigDto `json:"config"`
+	Secret      *string                 `json:"secret"`
+	Description *string                 `json:"description" binding:"omitempty,max=500"`
+	Status      *int                    `json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+// ApiAuthProfileConfigDto 认证配置的非敏感参数，按类型使用其中的字段
+type ApiAuthProfileConfigDto struct {
+	// BASIC
+	Username string `json:"username,omitempty"`
+	// API_KEY：In 为 HEADER 或 QUERY，KeyName 为请求头名或参数名
+	In      string `json:"in,omitempty"`
+	KeyName string `json:"keyName,omitempty"`
+	// HMAC：Algorithm 为 SHA256（默认）、SHA1 或 SHA512
+	Algorithm       string `json:"algorithm,omitempty"`
+	AccessKey       string `json:"accessKey,omitempty"`
+	AccessKeyHeader string `json:"accessKeyHeader,omitempty"`
+	SignatureHeader string `json:"signatureHeader,omitempty"`
+	TimestampHeader string `json:"timestampHeader,omitempty"`
+	// OAUTH2_CLIENT_CREDENTIALS
+	TokenURL string `json:"tokenUrl,omitempty"`
+	ClientID string `json:"clientId,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+}
+
+// ApiAuthProfileQueryDto 接口认证配置查询DTO
+type ApiAuthProfileQueryDto struct {
+	Name   *string `form:"name"`
+	Type   *string `form:"type"`
+	Status *int    `form:"status" binding:"omitempty,oneof=0 1"`
+	Pagination
+}
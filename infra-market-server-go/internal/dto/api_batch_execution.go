@@ -0,0 +1,56 @@
+package dto
+
+// ApiBatchExecuteRequestDto 接口批量执行请求DTO
+// Rows 为数据集，每行的列名对应接口参数名；未定义为接口参数的列作为模板变量使用
+type ApiBatchExecuteRequestDto struct {
+	InterfaceID   *uint64          `json:"interfaceId" binding:"required"`
+	EnvironmentID *uint64          `json:"environmentId"`
+	Rows          []map[string]any `json:"rows"`
+	Concurrency   *int             `json:"concurrency" binding:"omitempty,min=1,max=20"`
+	StopOnError   bool             `json:"stopOnError"`
+	Remark        *string          `json:"remark"`
+	FileName      *string          `json:"-"`
+}
+
+// ApiBatchExecutionDto 接口批量执行汇总DTO
+type ApiBatchExecutionDto struct {
+	ID            uint64                 `json:"id"`
+	InterfaceID   uint64                 `json:"interfaceId"`
+	EnvironmentID *uint64                `json:"environmentId"`
+	ExecutorID    uint64                 `json:"executorId"`
+	ExecutorName  string                 `json:"executorName"`
+	FileName      *string                `json:"fileName"`
+	Concurrency   int                    `json:"concurrency"`
+	StopOnError   bool                   `json:"stopOnError"`
+	Status        string                 `json:"status"`
+	TotalRows     int                    `json:"totalRows"`
+	FinishedRows  int                    `json:"finishedRows"`
+	SuccessRows   int                    `json:"successRows"`
+	FailedRows    int                    `json:"failedRows"`
+	Progress      float64                `json:"progress"`
+	FailedResults []ApiBatchRowResultDto `json:"failedResults,omitempty"`
+	CreateTime    string                 `json:"createTime"`
+	FinishTime    *string                `json:"finishTime"`
+}
+
+// ApiBatchRowResultDto 批量执行单行结果DTO
+type ApiBatchRowResultDto struct {
+	RowIndex     int            `json:"rowIndex"`
+	Row          map[string]any `json:"row"`
+	RecordID     *uint64        `json:"recordId"`
+	Status       int            `json:"status"`
+	Success      bool           `json:"success"`
+	Skipped      bool           `json:"skipped"`
+	ResponseTime int64          `json:"responseTime"`
+	Error        *string        `json:"error"`
+}
+
+// ApiBatchExecutionQueryDto 接口批量执行查询DTO
+type ApiBatchExecutionQueryDto struct {
+	InterfaceID *uint64 `form:"interfaceId"`
+	Status      *string `form:"status"`
+	// ViewerUID 与 ViewerRoleIDs 为查询人及其角色，只返回查询人可见接口的批次，为空时不过滤，由服务层填充
+	ViewerUID     *uint64  `form:"-"`
+	ViewerRoleIDs []uint64 `form:"-"`
+	Pagination
+}
@@ -0,0 +1,38 @@
+package dto
+
+// ApiEnvironmentDto 接口执行环境DTO
+type ApiEnvironmentDto struct {
+	ID            uint64            `json:"id"`
+	Name          string            `json:"name"`
+	Code          string            `json:"code"`
+	BaseURL       string            `json:"baseUrl"`
+	Variables     map[string]string `json:"variables"`
+	AuthProfileID *uint64           `json:"authProfileId"`
+	Description   *string           `json:"description"`
+	Sort          int               `json:"sort"`
+	Status        int               `json:"status"`
+	Transport     *ApiTransportDto  `json:"transport"`
+	CreateTime    string            `json:"createTime"`
+	UpdateTime    string            `json:"updateTime"`
+}
+
+// ApiEnvironmentFormDto 接口执行环境创建/更新表单
+type ApiEnvironmentFormDto struct {
+	Name          string            `json:"name" binding:"required,min=1,max=100"`
+	Code          string            `json:"code" binding:"required,min=1,max=50"`
+	BaseURL       string            `json:"baseUrl" binding:"required,url,max=500"`
+	Variables     map[string]string `json:"variables"`
+	AuthProfileID *uint64           `json:"authProfileId"`
+	Description   *string           `json:"description" binding:"omitempty,max=500"`
+	Sort          *int              `json:"sort"`
+	Status        *int              `json:"status" binding:"omitempty,oneof=0 1"`
+	// Transport 为空表示保持原传输层配置，传入空对象表示清除
+	Transport *ApiTransportFormDto `json:"transport"`
+}
+
+// ApiEnvironmentQueryDto 接口执行环境查询DTO
+type ApiEnvironmentQueryDto struct {
+	Name   *string `form:"name"`
+	Status *int    `form:"status" binding:"omitempty,oneof=0 1"`
+	Pagination
+}
@@ -2,45 +2,92 @@ package dto
 
 // ApiInterfaceDto 接口信息DTO
 type ApiInterfaceDto struct {
-	ID           *uint64       `json:"id"`
-	Name         *string       `json:"name"`
-	Method       *string       `json:"method"`
-	URL          *string       `json:"url"`
-	Description  *string       `json:"description"`
-	Status       *int          `json:"status"`
-	PostType     *string       `json:"postType"`
-	Environment  *string       `json:"environment"`
-	Timeout      *int64        `json:"timeout"`
-	ValuePath    *string       `json:"valuePath"`
-	URLParams    []ApiParamDto `json:"urlParams"`
-	HeaderParams []ApiParamDto `json:"headerParams"`
-	BodyParams   []ApiParamDto `json:"bodyParams"`
-	CreateTime   *string       `json:"createTime"`
-	UpdateTime   *string       `json:"updateTime"`
+	ID              *uint64           `json:"id"`
+	Name            *string           `json:"name"`
+	Method          *string           `json:"method"`
+	URL             *string           `json:"url"`
+	Description     *string           `json:"description"`
+	Status          *int              `json:"status"`
+	PostType        *string           `json:"postType"`
+	BodyMode        *string           `json:"bodyMode"`
+	RawBody         *string           `json:"rawBody"`
+	RawDataType     *string           `json:"rawDataType"`
+	Environment     *string           `json:"environment"`
+	EnvironmentID   *uint64           `json:"environmentId"`
+	AuthProfileID   *uint64           `json:"authProfileId"`
+	Timeout         *int64            `json:"timeout"`
+	FollowRedirects *bool             `json:"followRedirects"`
+	MaxRedirects    *int              `json:"maxRedirects"`
+	HttpVersion     *string           `json:"httpVersion"`
+	ValuePath       *string           `json:"valuePath"`
+	URLParams       []ApiParamDto     `json:"urlParams"`
+	PathParams      []ApiParamDto     `json:"pathParams"`
+	HeaderParams    []ApiParamDto     `json:"headerParams"`
+	BodyParams      []ApiParamDto     `json:"bodyParams"`
+	Assertions      []ApiAssertionDto `json:"assertions"`
+	Transport       *ApiTransportDto  `json:"transport"`
+	// RetryPolicy 重试策略，为空表示不重试
+	RetryPolicy *ApiRetryPolicyDto `json:"retryPolicy"`
+	Version     *int               `json:"version"`
+	GroupID     *uint64            `json:"groupId"`
+	Tags        []string           `json:"tags"`
+	// Owners 负责人姓名，为空表示接口未设置负责人与授权，所有人都可以访问
+	Owners []string `json:"owners"`
+	// Permission 当前用户对接口的权限，见 enums.InterfacePermission
+	Permission *string `json:"permission"`
+	CreateTime *string `json:"createTime"`
+	UpdateTime *string `json:"updateTime"`
 }
 
 // ApiInterfaceFormDto 接口创建/更新表单
 type ApiInterfaceFormDto struct {
-	ID           *uint64       `json:"id"`
-	Name         *string       `json:"name" binding:"required"`
-	Method       *string       `json:"method" binding:"required"`
-	URL          *string       `json:"url" binding:"required"`
-	Description  *string       `json:"description"`
-	PostType     *string       `json:"postType"`
-	Environment  *string       `json:"environment"`
-	Timeout      *int64        `json:"timeout"`
-	ValuePath    *string       `json:"valuePath"`
-	URLParams    []ApiParamDto `json:"urlParams"`
-	HeaderParams []ApiParamDto `json:"headerParams"`
-	BodyParams   []ApiParamDto `json:"bodyParams"`
+	ID              *uint64           `json:"id"`
+	Name            *string           `json:"name" binding:"required"`
+	Method          *string           `json:"method" binding:"required"`
+	URL             *string           `json:"url" binding:"required"`
+	Description     *string           `json:"description"`
+	PostType        *string           `json:"postType"`
+	BodyMode        *string           `json:"bodyMode"`
+	RawBody         *string           `json:"rawBody"`
+	RawDataType     *string           `json:"rawDataType"`
+	Environment     *string           `json:"environment"`
+	EnvironmentID   *uint64           `json:"environmentId"`
+	AuthProfileID   *uint64           `json:"authProfileId"`
+	Timeout         *int64            `json:"timeout"`
+	FollowRedirects *bool             `json:"followRedirects"`
+	MaxRedirects    *int              `json:"maxRedirects"`
+	HttpVersion     *string           `json:"httpVersion"`
+	ValuePath       *string           `json:"valuePath"`
+	URLParams       []ApiParamDto     `json:"urlParams"`
+	PathParams      []ApiParamDto     `json:"pathParams"`
+	HeaderParams    []ApiParamDto     `json:"headerParams"`
+	BodyParams      []ApiParamDto     `json:"bodyParams"`
+	Assertions      []ApiAssertionDto `json:"assertions"`
+	// RetryPolicy 重试策略，为空表示不重试
+	RetryPolicy *ApiRetryPolicyDto `json:"retryPolicy"`
+	// Transport 为空表示保持原传输层配置，传入空对象表示清除
+	Transport *ApiTransportFormDto `json:"transport"`
+	// GroupID 所属分组，更新时为空表示保持原分组，移出分组请使用批量移动
+	GroupID *uint64 `json:"groupId"`
+	// Tags 标签名称，不存在的标签自动创建；更新时为空表示保持原标签，传入空数组表示清除
+	Tags []string `json:"tags"`
 }
 
 // ApiInterfaceQueryDto 接口查询DTO
 type ApiInterfaceQueryDto struct {
-	Name        *string `form:"name"`
-	Method      *string `form:"method"`
-	Status      *int    `form:"status"`
-	Environment *string `form:"environment"`
+	Name          *string `form:"name"`
+	Method        *string `form:"method"`
+	Status        *int    `form:"status"`
+	Environment   *string `form:"environment"`
+	EnvironmentID *uint64 `form:"environmentId"`
+	// GroupID 分组，包含子分组中的接口；为0时查询未分组的接口
+	GroupID *uint64 `form:"groupId"`
+	TagID   *uint64 `form:"tagId"`
+	// GroupIDs 由 GroupID 展开的分组及全部子分组，由服务层填充
+	GroupIDs []uint64 `form:"-"`
+	// ViewerUID 与 ViewerRoleIDs 为查询人及其角色，只返回查询人可见的接口，为空时不过滤，由服务层填充
+	ViewerUID     *uint64  `form:"-"`
+	ViewerRoleIDs []uint64 `form:"-"`
 	Pagination
 }
 
@@ -73,21 +120,145 @@ type SelectOptionDto struct {
 
 // ApiExecuteRequestDto 接口执行请求DTO
 type ApiExecuteRequestDto struct {
-	InterfaceID *uint64           `json:"interfaceId" binding:"required"`
-	Headers     map[string]string `json:"headers"`
-	URLParams   map[string]any    `json:"urlParams"`
-	BodyParams  map[string]any    `json:"bodyParams"`
-	Timeout     *int64            `json:"timeout"`
-	Remark      *string           `json:"remark"`
+	InterfaceID   *uint64            `json:"interfaceId" binding:"required"`
+	EnvironmentID *uint64            `json:"environmentId"`
+	Headers       map[string]string  `json:"headers"`
+	URLParams     map[string]any     `json:"urlParams"`
+	PathParams    map[string]any     `json:"pathParams"`
+	BodyParams    map[string]any     `json:"bodyParams"`
+	RawBody       *string            `json:"rawBody"`
+	Variables     map[string]string  `json:"variables"`
+	Files         []ApiUploadFileDto `json:"files"`
+	Timeout       *int64             `json:"timeout"`
+	Remark        *string            `json:"remark"`
+	// WorkflowRunID 由工作流执行时设置，用于关联工作流执行记录
+	WorkflowRunID *uint64 `json:"-"`
+	// ScheduleID 由定时计划执行时设置，用于关联定时计划
+	ScheduleID *uint64 `json:"-"`
+	// BatchID 由批量执行时设置，用于关联批量执行批次
+	BatchID *uint64 `json:"-"`
+	// ReplayOfID 由重放执行记录时设置，用于关联原执行记录
+	ReplayOfID *uint64 `json:"-"`
+}
+
+// ApiUploadFileDto 接口执行上传文件DTO
+// JSON 请求中 Content 为 base64 编码；multipart 请求中由上传的文件填充
+type ApiUploadFileDto struct {
+	ParamName   string `json:"paramName"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Content     []byte `json:"content"`
+}
+
+// ApiUploadFileMetaDto 上传文件元信息DTO，执行记录中只保存元信息
+type ApiUploadFileMetaDto struct {
+	ParamName   string `json:"paramName"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
 }
 
 // ApiExecuteResponseDto 接口执行响应DTO
 type ApiExecuteResponseDto struct {
-	Status         int               `json:"status"`
-	Headers        map[string]string `json:"headers"`
-	Body           *string           `json:"body"`
-	ExtractedValue *string           `json:"extractedValue"`
-	ResponseTime   int64             `json:"responseTime"`
-	Success        bool              `json:"success"`
-	Error          *string           `json:"error"`
+	RecordID         *uint64                 `json:"recordId"`
+	Status           int                     `json:"status"`
+	Headers          map[string]string       `json:"headers"`
+	Body             *string                 `json:"body"`
+	BodyInfo         *ApiResponseBodyInfoDto `json:"bodyInfo"`
+	Protocol         *string                 `json:"protocol"`
+	RedirectChain    []ApiRedirectHopDto     `json:"redirectChain"`
+	Attempts         []ApiExecuteAttemptDto  `json:"attempts"`     // 配置了重试策略时每次请求的结果
+	ParamErrors      []ApiParamErrorDto      `json:"paramErrors"`  // 参数校验失败时的全部错误
+	ParamSources     []ApiParamSourceDto     `json:"paramSources"` // 各参数取值的来源：调用方传入、默认值或固定值
+	ExtractedValue   *string                 `json:"extractedValue"`
+	AssertionResults []ApiAssertionResultDto `json:"assertionResults"`
+	ResponseTime     int64                   `json:"responseTime"`
+	Success          bool                    `json:"success"`
+	Error            *string                 `json:"error"`
+}
+
+// ApiResponseBodyInfoDto 响应体元信息DTO
+// 二进制响应不保存 Body；超出保存上限的文本响应 Body 为截断后的内容
+type ApiResponseBodyInfoDto struct {
+	ContentType string  `json:"contentType"`
+	Size        int64   `json:"size"`       // 读取到的字节数，Complete 为 false 时小于实际大小
+	Complete    bool    `json:"complete"`   // 是否读取了完整响应体
+	Binary      bool    `json:"binary"`     // 是否为二进制内容
+	Truncated   bool    `json:"truncated"`  // Body 是否被截断
+	SHA256      string  `json:"sha256"`     // 读取到的内容的 SHA-256
+	BlobStored  bool    `json:"blobStored"` // 是否已保存到本地目录，可通过执行记录下载
+	Preview     *string `json:"preview"`    // 小图片的 data URI 内联预览
+	BlobKey     string  `json:"-"`          // 本地目录中的文件名，仅用于保存执行记录
+}
+
+// ApiInterfaceSnippetDto 接口请求代码片段DTO
+type ApiInterfaceSnippetDto struct {
+	Curl       string `json:"curl"`
+	Go         string `json:"go"`
+	Python     string `json:"python"`
+	JavaScript string `json:"javascript"`
+}
+
+// ApiParamErrorDto 参数校验错误
+type ApiParamErrorDto struct {
+	Name      string `json:"name"`
+	ParamType string `json:"paramType"`
+	DataType  string `json:"dataType"` // 期望的数据类型
+	Message   string `json:"message"`
+}
+
+// ApiParamSourceDto 执行时参数取值的来源，Source 见 enums.ParamSource
+type ApiParamSourceDto struct {
+	Name      string `json:"name"`
+	ParamType string `json:"paramType"`
+	Source    string `json:"source"`
+}
+
+// ApiRetryPolicyDto 接口重试策略
+// MaxAttempts 含首次请求；退避时间从 InitialBackoff 开始按指数增长并加入随机抖动，不超过 MaxBackoff（毫秒）；
+// 响应包含 Retry-After 时按其等待，超过 MaxBackoff 时不再重试
+type ApiRetryPolicyDto struct {
+	MaxAttempts         int   `json:"maxAttempts"`
+	RetryOnStatus       []int `json:"retryOnStatus"`
+	RetryOnNetworkError bool  `json:"retryOnNetworkError"`
+	InitialBackoff      int64 `json:"initialBackoff"`
+	MaxBackoff          int64 `json:"maxBackoff"`
+}
+
+// ApiExecuteAttemptDto 重试时单次请求的结果
+type ApiExecuteAttemptDto struct {
+	Attempt      int     `json:"attempt"`
+	Status       int     `json:"status"`       // 未收到响应时为0
+	ResponseTime int64   `json:"responseTime"` // 毫秒
+	Delay        int64   `json:"delay"`        // 本次请求前的等待时间（毫秒）
+	Error        *string `json:"error"`
+}
+
+// ApiRedirectHopDto 重定向链中的一跳
+type ApiRedirectHopDto struct {
+	URL      string `json:"url"`
+	Status   int    `json:"status"`
+	Location string `json:"location"`
+}
+
+// ApiAssertionDto 响应断言DTO
+// Type 为 STATUS_CODE 时 Expected 为状态码或区间（如 200-299）；JSON_PATH、HEADER 的 Target 为JSONPath或响应头名称；
+// RESPONSE_TIME 的 Expected 为最大响应时间（毫秒）
+type ApiAssertionDto struct {
+	Type        *string `json:"type"`
+	Target      *string `json:"target"`
+	Operator    *string `json:"operator"`
+	Expected    *string `json:"expected"`
+	Description *string `json:"description"`
+}
+
+// ApiAssertionResultDto 响应断言结果DTO
+type ApiAssertionResultDto struct {
+	Type     *string `json:"type"`
+	Target   *string `json:"target"`
+	Operator *string `json:"operator"`
+	Expected *string `json:"expected"`
+	Actual   *string `json:"actual"`
+	Passed   bool    `json:"passed"`
+	Message  *string `json:"message"`
 }
@@ -0,0 +1,24 @@
+package dto
+
+// ApiInterfaceAclDto 接口授权DTO
+type ApiInterfaceAclDto struct {
+	// SubjectType 授权对象类型，见 enums.AclSubjectType
+	SubjectType string  `json:"subjectType"`
+	SubjectID   uint64  `json:"subjectId"`
+	SubjectName *string `json:"subjectName"`
+	// Permission 权限，见 enums.InterfacePermission
+	Permission string `json:"permission"`
+	CreateTime string `json:"createTime"`
+}
+
+// ApiInterfaceAclFormDto 接口授权表单，整体替换接口的授权；传入空列表表示取消限制，所有人都可以访问
+type ApiInterfaceAclFormDto struct {
+	Entries []ApiInterfaceAclEntryDto `json:"entries" binding:"dive"`
+}
+
+// ApiInterfaceAclEntryDto 接口授权项，负责人（OWNER）只能授权给用户
+type ApiInterfaceAclEntryDto struct {
+	SubjectType *string `json:"subjectType" binding:"required"`
+	SubjectID   *uint64 `json:"subjectId" binding:"required"`
+	Permission  *string `json:"permission" binding:"required"`
+}
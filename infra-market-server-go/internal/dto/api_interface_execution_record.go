@@ -1,42 +1,111 @@
 package dto
 
+import "io"
+
 // ApiInterfaceExecutionRecordDto 执行记录DTO
 type ApiInterfaceExecutionRecordDto struct {
-	ID              *uint64 `json:"id"`
-	InterfaceID     *uint64 `json:"interfaceId"`
-	InterfaceName   *string `json:"interfaceName"`
-	ExecutorID      *uint64 `json:"executorId"`
-	ExecutorName    *string `json:"executorName"`
-	RequestParams   *string `json:"requestParams"`
-	RequestHeaders  *string `json:"requestHeaders"`
-	RequestBody     *string `json:"requestBody"`
-	ResponseStatus  *int    `json:"responseStatus"`
-	ResponseHeaders *string `json:"responseHeaders"`
-	ResponseBody    *string `json:"responseBody"`
-	ExecutionTime   *int64  `json:"executionTime"`
-	Success         *bool   `json:"success"`
-	ErrorMessage    *string `json:"errorMessage"`
-	Remark          *string `json:"remark"`
-	ClientIP        *string `json:"clientIp"`
-	UserAgent       *string `json:"userAgent"`
-	CreateTime      *string `json:"createTime"`
-	UpdateTime      *string `json:"updateTime"`
+	ID                *uint64 `json:"id"`
+	InterfaceID       *uint64 `json:"interfaceId"`
+	InterfaceName     *string `json:"interfaceName"`
+	InterfaceVersion  *int    `json:"interfaceVersion"`
+	EnvironmentID     *uint64 `json:"environmentId"`
+	WorkflowRunID     *uint64 `json:"workflowRunId"`
+	ScheduleID        *uint64 `json:"scheduleId"`
+	BatchID           *uint64 `json:"batchId"`
+	ReplayOfID        *uint64 `json:"replayOfId"`
+	ExecutorID        *uint64 `json:"executorId"`
+	ExecutorName      *string `json:"executorName"`
+	RequestParams     *string `json:"requestParams"`
+	RequestPathParams *string `json:"requestPathParams"`
+	RequestHeaders    *string `json:"requestHeaders"`
+	RequestBody       *string `json:"requestBody"`
+	RequestFiles      *string `json:"requestFiles"`
+	ResponseStatus    *int    `json:"responseStatus"`
+	ResponseHeaders   *string `json:"responseHeaders"`
+	ResponseBody      *string `json:"responseBody"`
+	ResponseBodyInfo  *string `json:"responseBodyInfo"`
+	RedirectChain     *string `json:"redirectChain"`
+	Attempts          *string `json:"attempts"`
+	ParamSources      *string `json:"paramSources"`
+	AssertionResults  *string `json:"assertionResults"`
+	ExecutionTime     *int64  `json:"executionTime"`
+	Success           *bool   `json:"success"`
+	ErrorMessage      *string `json:"errorMessage"`
+	Remark            *string `json:"remark"`
+	ClientIP          *string `json:"clientIp"`
+	UserAgent         *string `json:"userAgent"`
+	CreateTime        *string `json:"createTime"`
+	UpdateTime        *string `json:"updateTime"`
+	Masked            bool    `json:"masked"` // 敏感信息是否已脱敏
 }
 
 // ApiInterfaceExecutionRecordQueryDto 执行记录查询DTO
 type ApiInterfaceExecutionRecordQueryDto struct {
-	InterfaceID      *uint64 `form:"interfaceId"`
-	Keyword          *string `form:"keyword"`
-	ExecutorID       *uint64 `form:"executorId"`
-	ExecutorName     *string `form:"executorName"`
-	Success          *bool   `form:"success"`
-	StartTime        *int64  `form:"startTime"`
-	EndTime          *int64  `form:"endTime"`
-	MinExecutionTime *int64  `form:"minExecutionTime"`
-	MaxExecutionTime *int64  `form:"maxExecutionTime"`
+	IDs              []uint64 `form:"ids"`
+	InterfaceID      *uint64  `form:"interfaceId"`
+	WorkflowRunID    *uint64  `form:"workflowRunId"`
+	ScheduleID       *uint64  `form:"scheduleId"`
+	BatchID          *uint64  `form:"batchId"`
+	ReplayOfID       *uint64  `form:"replayOfId"`
+	Keyword          *string  `form:"keyword"`
+	ExecutorID       *uint64  `form:"executorId"`
+	ExecutorName     *string  `form:"executorName"`
+	Success          *bool    `form:"success"`
+	StartTime        *int64   `form:"startTime"`
+	EndTime          *int64   `form:"endTime"`
+	MinExecutionTime *int64   `form:"minExecutionTime"`
+	MaxExecutionTime *int64   `form:"maxExecutionTime"`
+	Unmasked         *bool    `form:"unmasked"` // 查看未脱敏内容，需要对应权限
+	// ViewerUID 与 ViewerRoleIDs 为查询人及其角色，只返回查询人可见接口的执行记录，为空时不过滤，由服务层填充
+	ViewerUID     *uint64  `form:"-"`
+	ViewerRoleIDs []uint64 `form:"-"`
 	Pagination
 }
 
+// ApiInterfaceExecutionRecordDetailQueryDto 执行记录详情查询DTO
+type ApiInterfaceExecutionRecordDetailQueryDto struct {
+	Unmasked *bool `form:"unmasked"` // 查看未脱敏内容，需要对应权限
+}
+
+// ApiExecutionReplayRequestDto 执行记录重放请求DTO
+type ApiExecutionReplayRequestDto struct {
+	EnvironmentID *uint64  `json:"environmentId"` // 覆盖执行环境，默认使用原记录的环境
+	IgnoreHeaders []string `json:"ignoreHeaders"` // 对比时额外忽略的响应头
+	IgnorePaths   []string `json:"ignorePaths"`   // 对比时额外忽略的响应体JSONPath
+	Remark        *string  `json:"remark"`
+}
+
+// ApiExecutionReplayResultDto 执行记录重放结果DTO
+type ApiExecutionReplayResultDto struct {
+	OriginalRecordID uint64                `json:"originalRecordId"`
+	Response         ApiExecuteResponseDto `json:"response"`
+	Diff             *ApiResponseDiffDto   `json:"diff"` // 未生成新执行记录时为空
+}
+
+// ApiResponseDiffDto 响应对比结果DTO，敏感内容按脱敏后的值对比
+type ApiResponseDiffDto struct {
+	Identical bool             `json:"identical"`
+	Status    *ApiDiffItemDto  `json:"status"`
+	Headers   []ApiDiffItemDto `json:"headers"`
+	Body      []ApiDiffItemDto `json:"body"`
+}
+
+// ApiDiffItemDto 单项差异DTO
+type ApiDiffItemDto struct {
+	Path     string `json:"path"` // 响应头为名称，响应体为JSONPath
+	Type     string `json:"type"` // ADDED、REMOVED、CHANGED
+	Original any    `json:"original"`
+	Current  any    `json:"current"`
+}
+
+// ApiResponseBodyDownloadDto 执行记录响应体下载DTO
+type ApiResponseBodyDownloadDto struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Reader      io.ReadCloser
+}
+
 // ApiInterfaceExecutionRecordLimitQueryDto 执行记录数量限制查询DTO
 type ApiInterfaceExecutionRecordLimitQueryDto struct {
 	Limit *int `form:"limit" binding:"omitempty,min=1"`
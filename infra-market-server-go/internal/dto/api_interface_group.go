@@ -0,0 +1,45 @@
+package dto
+
+// ApiInterfaceGroupDto 接口分组DTO，分组树中 Children 为子分组
+type ApiInterfaceGroupDto struct {
+	ID          uint64  `json:"id"`
+	Name        string  `json:"name"`
+	ParentID    *uint64 `json:"parentId"`
+	Description *string `json:"description"`
+	Sort        int     `json:"sort"`
+	OwnerRoleID *uint64 `json:"ownerRoleId"`
+	// OwnerRoleName 所属团队名称，未设置时为继承自上级分组的团队
+	OwnerRoleName  *string                `json:"ownerRoleName"`
+	InterfaceCount int64                  `json:"interfaceCount"` // 直接包含的接口数量
+	TotalCount     int64                  `json:"totalCount"`     // 包含子分组在内的接口数量
+	Children       []ApiInterfaceGroupDto `json:"children"`
+	CreateTime     string                 `json:"createTime"`
+	UpdateTime     string                 `json:"updateTime"`
+}
+
+// ApiInterfaceGroupFormDto 接口分组创建/更新表单，修改 ParentID 即移动分组
+type ApiInterfaceGroupFormDto struct {
+	Name        *string `json:"name" binding:"required,max=100"`
+	ParentID    *uint64 `json:"parentId"`
+	Description *string `json:"description"`
+	Sort        *int    `json:"sort"`
+	OwnerRoleID *uint64 `json:"ownerRoleId"`
+}
+
+// ApiInterfaceGroupDeleteQueryDto 删除接口分组查询DTO，Strategy 见 enums.GroupDeleteStrategy
+type ApiInterfaceGroupDeleteQueryDto struct {
+	Strategy *string `form:"strategy" binding:"required"`
+}
+
+// ApiInterfaceMoveDto 批量移动接口DTO，GroupID 为空表示移出分组
+type ApiInterfaceMoveDto struct {
+	InterfaceIDs []uint64 `json:"interfaceIds" binding:"required,min=1"`
+	GroupID      *uint64  `json:"groupId"`
+}
+
+// ApiInterfaceTagDto 接口标签DTO
+type ApiInterfaceTagDto struct {
+	ID             uint64 `json:"id"`
+	Name           string `json:"name"`
+	InterfaceCount int64  `json:"interfaceCount"`
+}
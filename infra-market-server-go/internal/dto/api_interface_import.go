@@ -0,0 +1,47 @@
+package dto
+
+// ApiInterfaceImportRequestDto 接口导入请求DTO
+// Content 与 SourceURL 二选一；multipart 上传时 Content 由上传的文件填充
+type ApiInterfaceImportRequestDto struct {
+	Content          *string `json:"content"`          // 规范文件内容（JSON 或 YAML）
+	SourceURL        *string `json:"sourceUrl"`        // 规范文件地址，未提供 Content 时从该地址下载
+	BaseURL          *string `json:"baseUrl"`          // 覆盖规范中声明的服务地址
+	EnvironmentID    *uint64 `json:"environmentId"`    // 导入接口的默认环境，设置后接口URL只保存路径
+	ConflictStrategy *string `json:"conflictStrategy"` // SKIP、OVERWRITE、RENAME，默认 SKIP
+	DryRun           *bool   `json:"dryRun"`           // 仅预览，不写入数据库
+}
+
+// ApiCurlImportRequestDto curl 命令导入请求DTO
+type ApiCurlImportRequestDto struct {
+	Command *string `json:"command" binding:"required"`
+}
+
+// ApiPostmanExportRequestDto Postman Collection 导出请求DTO
+type ApiPostmanExportRequestDto struct {
+	InterfaceIDs []uint64 `json:"interfaceIds" binding:"required,min=1"`
+	Name         *string  `json:"name"` // 集合名称，默认“接口集合”
+}
+
+// ApiInterfaceImportResultDto 接口导入结果DTO
+type ApiInterfaceImportResultDto struct {
+	DryRun      bool                        `json:"dryRun"`
+	Total       int                         `json:"total"`
+	Created     int                         `json:"created"`
+	Overwritten int                         `json:"overwritten"`
+	Renamed     int                         `json:"renamed"`
+	Skipped     int                         `json:"skipped"`
+	Failed      int                         `json:"failed"`
+	Items       []ApiInterfaceImportItemDto `json:"items"`
+}
+
+// ApiInterfaceImportItemDto 单个接口导入结果DTO
+type ApiInterfaceImportItemDto struct {
+	Name        string              `json:"name"`
+	Method      string              `json:"method"`
+	URL         string              `json:"url"`
+	Action      string              `json:"action"`
+	ExistingID  *uint64             `json:"existingId"`
+	InterfaceID *uint64             `json:"interfaceId"`
+	Error       *string             `json:"error"`
+	Interface   ApiInterfaceFormDto `json:"interface"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+// ApiInterfaceVersionQueryDto 接口版本查询DTO
+type ApiInterfaceVersionQueryDto struct {
+	InterfaceID *uint64 `form:"interfaceId" binding:"required"`
+	Pagination
+}
+
+// ApiInterfaceVersionDto 接口版本DTO，Interface 为该版本的接口定义，仅详情返回
+type ApiInterfaceVersionDto struct {
+	ID           uint64           `json:"id"`
+	InterfaceID  uint64           `json:"interfaceId"`
+	Version      int              `json:"version"`
+	Action       string           `json:"action"`
+	RestoredFrom *int             `json:"restoredFrom"`
+	EditorID     *uint64          `json:"editorId"`
+	EditorName   *string          `json:"editorName"`
+	CreateTime   string           `json:"createTime"`
+	Interface    *ApiInterfaceDto `json:"interface,omitempty"`
+}
+
+// ApiInterfaceVersionDiffQueryDto 接口版本对比查询DTO
+type ApiInterfaceVersionDiffQueryDto struct {
+	FromID uint64 `form:"fromId" binding:"required,min=1"`
+	ToID   uint64 `form:"toId" binding:"required,min=1"`
+}
+
+// ApiInterfaceVersionDiffDto 接口版本对比结果DTO
+// Fields 为接口字段的差异，路径为JSONPath，JSON格式的配置逐字段对比；Params 为逐个参数的差异
+type ApiInterfaceVersionDiffDto struct {
+	From      ApiInterfaceVersionDto `json:"from"`
+	To        ApiInterfaceVersionDto `json:"to"`
+	Identical bool                   `json:"identical"`
+	Fields    []ApiDiffItemDto       `json:"fields"`
+	Params    []ApiParamDiffDto      `json:"params"`
+}
+
+// ApiParamDiffDto 单个参数的差异DTO，参数按参数类型与名称匹配
+type ApiParamDiffDto struct {
+	Name      string           `json:"name"`
+	ParamType string           `json:"paramType"`
+	Type      string           `json:"type"`   // ADDED、REMOVED、CHANGED
+	Fields    []ApiDiffItemDto `json:"fields"` // 逐字段的差异，新增或删除的参数列出全部字段
+}
@@ -0,0 +1,55 @@
+package dto
+
+// ApiScheduleDto 接口定时执行计划DTO
+type ApiScheduleDto struct {
+	ID             uint64               `json:"id"`
+	Name           string               `json:"name"`
+	InterfaceID    uint64               `json:"interfaceId"`
+	CronExpression string               `json:"cronExpression"`
+	TimeZone       string               `json:"timeZone"`
+	Params         ApiScheduleParamsDto `json:"params"`
+	Description    *string              `json:"description"`
+	Status         int                  `json:"status"`
+	NextFireTime   *string              `json:"nextFireTime"`
+	LastFireTime   *string              `json:"lastFireTime"`
+	LastSuccess    *bool                `json:"lastSuccess"`
+	LastRecordID   *uint64              `json:"lastRecordId"`
+	LastError      *string              `json:"lastError"`
+	CreatorID      uint64               `json:"creatorId"`
+	CreateTime     string               `json:"createTime"`
+	UpdateTime     string               `json:"updateTime"`
+}
+
+// ApiScheduleFormDto 接口定时执行计划创建/更新表单
+type ApiScheduleFormDto struct {
+	Name           string               `json:"name" binding:"required,min=1,max=100"`
+	InterfaceID    uint64               `json:"interfaceId" binding:"required"`
+	CronExpression string               `json:"cronExpression" binding:"required,max=100"`
+	TimeZone       *string              `json:"timeZone" binding:"omitempty,max=50"`
+	Params         ApiScheduleParamsDto `json:"params"`
+	Description    *string              `json:"description" binding:"omitempty,max=500"`
+	Status         *int                 `json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+// ApiScheduleParamsDto 定时执行使用的接口参数
+type ApiScheduleParamsDto struct {
+	EnvironmentID *uint64           `json:"environmentId"`
+	Headers       map[string]string `json:"headers"`
+	URLParams     map[string]any    `json:"urlParams"`
+	PathParams    map[string]any    `json:"pathParams"`
+	BodyParams    map[string]any    `json:"bodyParams"`
+	RawBody       *string           `json:"rawBody"`
+	Variables     map[string]string `json:"variables"`
+	Timeout       *int64            `json:"timeout"`
+}
+
+// ApiScheduleQueryDto 接口定时执行计划查询DTO
+type ApiScheduleQueryDto struct {
+	Name        *string `form:"name"`
+	InterfaceID *uint64 `form:"interfaceId"`
+	Status      *int    `form:"status" binding:"omitempty,oneof=0 1"`
+	// ViewerUID 与 ViewerRoleIDs 为查询人及其角色，只返回查询人可以执行的接口的计划，为空时不过滤，由服务层填充
+	ViewerUID     *uint64  `form:"-"`
+	ViewerRoleIDs []uint64 `form:"-"`
+	Pagination
+}
@@ -0,0 +1,31 @@
+package dto
+
+// ApiTransportConfigDto 传输层配置的非敏感参数：自定义CA、客户端证书、SNI与代理
+type ApiTransportConfigDto struct {
+	// CACert 自定义CA证书（PEM，可包含多个证书），追加到系统信任的根证书中
+	CACert string `json:"caCert,omitempty"`
+	// ClientCert 客户端证书（PEM），用于双向TLS，私钥加密保存
+	ClientCert string `json:"clientCert,omitempty"`
+	// InsecureSkipVerify 跳过服务端证书校验，需要单独授权
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// ServerName 覆盖TLS握手时的SNI与证书校验主机名
+	ServerName string `json:"serverName,omitempty"`
+	// ProxyURL 代理地址，支持 http://、https://、socks5://
+	ProxyURL      string `json:"proxyUrl,omitempty"`
+	ProxyUsername string `json:"proxyUsername,omitempty"`
+}
+
+// ApiTransportDto 传输层配置DTO，不返回私钥与代理密码
+type ApiTransportDto struct {
+	ApiTransportConfigDto
+	HasClientKey     bool `json:"hasClientKey"`
+	HasProxyPassword bool `json:"hasProxyPassword"`
+}
+
+// ApiTransportFormDto 传输层配置表单
+// ClientKey 为客户端私钥（PEM），ProxyPassword 为代理密码；更新时为空表示保留原值
+type ApiTransportFormDto struct {
+	ApiTransportConfigDto
+	ClientKey     *string `json:"clientKey"`
+	ProxyPassword *string `json:"proxyPassword"`
+}
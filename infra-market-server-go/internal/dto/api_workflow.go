@@ -0,0 +1,101 @@
+package dto
+
+// ApiWorkflowDto 接口工作流DTO
+type ApiWorkflowDto struct {
+	ID            uint64               `json:"id"`
+	Name          string               `json:"name"`
+	Description   *string              `json:"description"`
+	EnvironmentID *uint64              `json:"environmentId"`
+	Steps         []ApiWorkflowStepDto `json:"steps"`
+	Status        int                  `json:"status"`
+	CreateTime    string               `json:"createTime"`
+	UpdateTime    string               `json:"updateTime"`
+}
+
+// ApiWorkflowFormDto 接口工作流创建/更新表单
+type ApiWorkflowFormDto struct {
+	Name          string               `json:"name" binding:"required,min=1,max=100"`
+	Description   *string              `json:"description" binding:"omitempty,max=500"`
+	EnvironmentID *uint64              `json:"environmentId"`
+	Steps         []ApiWorkflowStepDto `json:"steps" binding:"required,min=1"`
+	Status        *int                 `json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+// ApiWorkflowQueryDto 接口工作流查询DTO
+type ApiWorkflowQueryDto struct {
+	Name   *string `form:"name"`
+	Status *int    `form:"status" binding:"omitempty,oneof=0 1"`
+	Pagination
+}
+
+// ApiWorkflowStepDto 工作流步骤DTO
+// Headers、URLParams、PathParams、BodyParams 为该步骤固定的参数值，Mappings 将前序步骤的响应值写入本步骤参数
+type ApiWorkflowStepDto struct {
+	Name              *string                 `json:"name"`
+	InterfaceID       *uint64                 `json:"interfaceId" binding:"required"`
+	ContinueOnFailure bool                    `json:"continueOnFailure"`
+	Headers           map[string]string       `json:"headers"`
+	URLParams         map[string]any          `json:"urlParams"`
+	PathParams        map[string]any          `json:"pathParams"`
+	BodyParams        map[string]any          `json:"bodyParams"`
+	Variables         map[string]string       `json:"variables"`
+	Mappings          []ApiWorkflowMappingDto `json:"mappings"`
+}
+
+// ApiWorkflowMappingDto 步骤间取值映射DTO
+// SourceStep 为前序步骤序号（从1开始）；SourcePath 为空时使用该步骤接口 valuePath 提取的值，否则按JSONPath从响应体取值
+// TargetType 为 URL_PARAM、PATH_PARAM、HEADER_PARAM、BODY_PARAM 或 VARIABLE（模板变量）
+type ApiWorkflowMappingDto struct {
+	SourceStep int     `json:"sourceStep"`
+	SourcePath *string `json:"sourcePath"`
+	TargetType string  `json:"targetType"`
+	TargetName string  `json:"targetName"`
+}
+
+// ApiWorkflowRunRequestDto 工作流执行请求DTO
+type ApiWorkflowRunRequestDto struct {
+	EnvironmentID *uint64           `json:"environmentId"`
+	Variables     map[string]string `json:"variables"`
+	Remark        *string           `json:"remark"`
+}
+
+// ApiWorkflowRunDto 工作流执行记录DTO
+type ApiWorkflowRunDto struct {
+	ID            uint64                     `json:"id"`
+	WorkflowID    uint64                     `json:"workflowId"`
+	WorkflowName  string                     `json:"workflowName"`
+	EnvironmentID *uint64                    `json:"environmentId"`
+	ExecutorID    uint64                     `json:"executorId"`
+	ExecutorName  string                     `json:"executorName"`
+	TotalSteps    int                        `json:"totalSteps"`
+	SuccessSteps  int                        `json:"successSteps"`
+	Steps         []ApiWorkflowStepResultDto `json:"steps"`
+	ExecutionTime int64                      `json:"executionTime"`
+	Success       bool                       `json:"success"`
+	ErrorMessage  *string                    `json:"errorMessage"`
+	CreateTime    string                     `json:"createTime"`
+}
+
+// ApiWorkflowStepResultDto 工作流步骤执行结果DTO
+type ApiWorkflowStepResultDto struct {
+	StepIndex      int     `json:"stepIndex"`
+	Name           *string `json:"name"`
+	InterfaceID    uint64  `json:"interfaceId"`
+	RecordID       *uint64 `json:"recordId"`
+	Status         int     `json:"status"`
+	Success        bool    `json:"success"`
+	Skipped        bool    `json:"skipped"`
+	ExtractedValue *string `json:"extractedValue"`
+	ResponseTime   int64   `json:"responseTime"`
+	Error          *string `json:"error"`
+}
+
+// ApiWorkflowRunQueryDto 工作流执行记录查询DTO
+type ApiWorkflowRunQueryDto struct {
+	WorkflowID *uint64 `form:"workflowId"`
+	Success    *bool   `form:"success"`
+	// ViewerUID 与 ViewerRoleIDs 为查询人及其角色，只返回各步骤接口均可见的执行记录，为空时不过滤，由服务层填充
+	ViewerUID     *uint64  `form:"-"`
+	ViewerRoleIDs []uint64 `form:"-"`
+	Pagination
+}
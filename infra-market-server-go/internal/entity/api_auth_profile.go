@@ -0,0 +1,17 @@
+package entity
+
+// ApiAuthProfile 接口认证配置实体类
+// 对应数据库表 api_auth_profile，Config 保存非敏感配置（JSON），Secret 保存加密后的密钥
+type ApiAuthProfile struct {
+	BaseEntity
+	Name        string  `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Type        string  `gorm:"column:type;type:varchar(50);not null" json:"type"`
+	Config      *string `gorm:"column:config;type:text" json:"config"`
+	Secret      *string `gorm:"column:secret;type:text" json:"-"`
+	Description *string `gorm:"column:description;type:varchar(500)" json:"description"`
+	Status      int     `gorm:"column:status;type:tinyint;not null;default:1;index:idx_status" json:"status"`
+}
+
+func (ApiAuthProfile) TableName() string {
+	return "api_auth_profile"
+}
@@ -0,0 +1,24 @@
+package entity
+
+// ApiBatchExecution 接口批量执行实体类
+// 对应数据库表 api_batch_execution，逐行结果保存在 api_batch_row_result，每行数据的接口执行记录通过 batch_id 关联
+type ApiBatchExecution struct {
+	BaseEntity
+	InterfaceID   uint64  `gorm:"column:interface_id;not null;index:idx_interface_id" json:"interfaceId"`
+	EnvironmentID *uint64 `gorm:"column:environment_id" json:"environmentId"`
+	ExecutorID    uint64  `gorm:"column:executor_id;not null;index:idx_executor_id" json:"executorId"`
+	ExecutorName  string  `gorm:"column:executor_name;type:varchar(50);not null" json:"executorName"`
+	FileName      *string `gorm:"column:file_name;type:varchar(255)" json:"fileName"`
+	Concurrency   int     `gorm:"column:concurrency;not null;default:1" json:"concurrency"`
+	StopOnError   bool    `gorm:"column:stop_on_error;type:tinyint(1);not null;default:0" json:"stopOnError"`
+	Status        string  `gorm:"column:status;type:varchar(20);not null;index:idx_status" json:"status"`
+	TotalRows     int     `gorm:"column:total_rows;not null;default:0" json:"totalRows"`
+	FinishedRows  int     `gorm:"column:finished_rows;not null;default:0" json:"finishedRows"`
+	SuccessRows   int     `gorm:"column:success_rows;not null;default:0" json:"successRows"`
+	FailedRows    int     `gorm:"column:failed_rows;not null;default:0" json:"failedRows"`
+	FinishTime    *int64  `gorm:"column:finish_time;type:bigint" json:"finishTime"`
+}
+
+func (ApiBatchExecution) TableName() string {
+	return "api_batch_execution"
+}
@@ -0,0 +1,20 @@
+package entity
+
+// ApiBatchRowResult 批量执行单行结果实体类
+// 对应数据库表 api_batch_row_result，Row 以JSON保存该行数据
+type ApiBatchRowResult struct {
+	BaseEntity
+	BatchID      uint64  `gorm:"column:batch_id;not null;uniqueIndex:uk_batch_row" json:"batchId"`
+	RowIndex     int     `gorm:"column:row_index;not null;uniqueIndex:uk_batch_row" json:"rowIndex"`
+	Row          *string `gorm:"column:row_data;type:text" json:"row"`
+	RecordID     *uint64 `gorm:"column:record_id" json:"recordId"`
+	Status       int     `gorm:"column:status;not null;default:0" json:"status"`
+	Success      bool    `gorm:"column:success;type:tinyint(1);not null;default:0" json:"success"`
+	Skipped      bool    `gorm:"column:skipped;type:tinyint(1);not null;default:0" json:"skipped"`
+	ResponseTime int64   `gorm:"column:response_time;not null;default:0" json:"responseTime"`
+	Error        *string `gorm:"column:error;type:text" json:"error"`
+}
+
+func (ApiBatchRowResult) TableName() string {
+	return "api_batch_row_result"
+}
@@ -0,0 +1,23 @@
+package entity
+
+// ApiEnvironment 接口执行环境实体类
+// 对应数据库表 api_environment
+type ApiEnvironment struct {
+	BaseEntity
+	Name          string  `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Code          string  `gorm:"column:code;type:varchar(50);not null;uniqueIndex:uk_code" json:"code"`
+	BaseURL       string  `gorm:"column:base_url;type:varchar(500);not null" json:"baseUrl"`
+	Variables     *string `gorm:"column:variables;type:text" json:"variables"`
+	AuthProfileID *uint64 `gorm:"column:auth_profile_id;index:idx_auth_profile_id" json:"authProfileId"`
+	Description   *string `gorm:"column:description;type:varchar(500)" json:"description"`
+	Sort          int     `gorm:"column:sort;not null;default:0" json:"sort"`
+	Status        int     `gorm:"column:status;type:tinyint;not null;default:1;index:idx_status" json:"status"`
+	// TransportConfig 传输层配置（JSON）
+	TransportConfig *string `gorm:"column:transport_config;type:text" json:"transportConfig"`
+	// TransportSecret 传输层敏感信息（客户端私钥、代理密码），加密存储
+	TransportSecret *string `gorm:"column:transport_secret;type:text" json:"-"`
+}
+
+func (ApiEnvironment) TableName() string {
+	return "api_environment"
+}
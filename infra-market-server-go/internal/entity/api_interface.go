@@ -9,11 +9,32 @@ type ApiInterface struct {
 	URL         string  `gorm:"column:url;type:varchar(500);not null" json:"url"`
 	Description *string `gorm:"column:description;type:text" json:"description"`
 	PostType    *string `gorm:"column:post_type;type:varchar(50)" json:"postType"`
+	BodyMode    *string `gorm:"column:body_mode;type:varchar(20)" json:"bodyMode"`
+	RawBody     *string `gorm:"column:raw_body;type:longtext" json:"rawBody"`
 	Params      *string `gorm:"column:params;type:text" json:"params"`
 	Status      *int    `gorm:"column:status;type:tinyint;default:1" json:"status"`
 	Environment *string `gorm:"column:environment;type:varchar(20)" json:"environment"`
-	Timeout     *int64  `gorm:"column:timeout;type:bigint" json:"timeout"`
-	ValuePath   *string `gorm:"column:value_path;type:varchar(255)" json:"valuePath"`
+	// EnvironmentID 默认执行环境，URL为相对路径时与环境基础地址拼接
+	EnvironmentID *uint64 `gorm:"column:environment_id;index:idx_environment_id" json:"environmentId"`
+	// GroupID 所属分组，为空表示未分组
+	GroupID *uint64 `gorm:"column:group_id;index:idx_group_id" json:"groupId"`
+	// AuthProfileID 认证配置，优先于环境的认证配置
+	AuthProfileID   *uint64 `gorm:"column:auth_profile_id;index:idx_auth_profile_id" json:"authProfileId"`
+	Timeout         *int64  `gorm:"column:timeout;type:bigint" json:"timeout"`
+	FollowRedirects *bool   `gorm:"column:follow_redirects;type:tinyint(1)" json:"followRedirects"`
+	MaxRedirects    *int    `gorm:"column:max_redirects" json:"maxRedirects"`
+	HttpVersion     *string `gorm:"column:http_version;type:varchar(20)" json:"httpVersion"`
+	ValuePath       *string `gorm:"column:value_path;type:varchar(255)" json:"valuePath"`
+	// Assertions 响应断言配置（JSON数组）
+	Assertions *string `gorm:"column:assertions;type:text" json:"assertions"`
+	// RetryPolicy 重试策略（JSON）
+	RetryPolicy *string `gorm:"column:retry_policy;type:text" json:"retryPolicy"`
+	// TransportConfig 传输层配置（JSON），优先于环境的传输层配置
+	TransportConfig *string `gorm:"column:transport_config;type:text" json:"transportConfig"`
+	// TransportSecret 传输层敏感信息（客户端私钥、代理密码），加密存储
+	TransportSecret *string `gorm:"column:transport_secret;type:text" json:"-"`
+	// Version 当前版本号，对应 api_interface_version 中的最新版本
+	Version *int `gorm:"column:version" json:"version"`
 }
 
 func (ApiInterface) TableName() string {
@@ -0,0 +1,15 @@
+package entity
+
+// ApiInterfaceAcl 接口授权实体类
+// 对应数据库表 api_interface_acl，负责人也以授权记录保存；接口没有任何授权记录时所有人都可以访问，但只有管理员与创建人可以设置授权
+type ApiInterfaceAcl struct {
+	BaseEntity
+	InterfaceID uint64 `gorm:"column:interface_id;not null;uniqueIndex:uk_interface_subject" json:"interfaceId"`
+	SubjectType string `gorm:"column:subject_type;type:varchar(20);not null;uniqueIndex:uk_interface_subject;index:idx_subject" json:"subjectType"`
+	SubjectID   uint64 `gorm:"column:subject_id;not null;uniqueIndex:uk_interface_subject;index:idx_subject" json:"subjectId"`
+	Permission  string `gorm:"column:permission;type:varchar(20);not null" json:"permission"`
+}
+
+func (ApiInterfaceAcl) TableName() string {
+	return "api_interface_acl"
+}
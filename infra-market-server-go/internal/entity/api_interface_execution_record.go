@@ -4,21 +4,36 @@ package entity
 // 对应数据库表 api_interface_execution_record
 type ApiInterfaceExecutionRecord struct {
 	BaseEntity
-	InterfaceID     *uint64 `gorm:"column:interface_id;not null;index:idx_interface_id" json:"interfaceId"`
-	ExecutorID      *uint64 `gorm:"column:executor_id;not null;index:idx_executor_id" json:"executorId"`
-	ExecutorName    string  `gorm:"column:executor_name;type:varchar(50);not null;index:idx_executor_name" json:"executorName"`
-	RequestParams   *string `gorm:"column:request_params;type:longtext" json:"requestParams"`
-	RequestHeaders  *string `gorm:"column:request_headers;type:longtext" json:"requestHeaders"`
-	RequestBody     *string `gorm:"column:request_body;type:longtext" json:"requestBody"`
-	ResponseStatus  *int    `gorm:"column:response_status" json:"responseStatus"`
-	ResponseHeaders *string `gorm:"column:response_headers;type:longtext" json:"responseHeaders"`
-	ResponseBody    *string `gorm:"column:response_body;type:longtext" json:"responseBody"`
-	ExecutionTime   *int64  `gorm:"column:execution_time;type:bigint;index:idx_execution_time" json:"executionTime"`
-	Success         *bool   `gorm:"column:success;type:tinyint(1);not null;default:0;index:idx_success" json:"success"`
-	ErrorMessage    *string `gorm:"column:error_message;type:text" json:"errorMessage"`
-	Remark          *string `gorm:"column:remark;type:text" json:"remark"`
-	ClientIP        *string `gorm:"column:client_ip;type:varchar(50)" json:"clientIp"`
-	UserAgent       *string `gorm:"column:user_agent;type:varchar(500)" json:"userAgent"`
+	InterfaceID       *uint64 `gorm:"column:interface_id;not null;index:idx_interface_id" json:"interfaceId"`
+	InterfaceVersion  *int    `gorm:"column:interface_version" json:"interfaceVersion"` // 执行时的接口版本号
+	EnvironmentID     *uint64 `gorm:"column:environment_id;index:idx_environment_id" json:"environmentId"`
+	WorkflowRunID     *uint64 `gorm:"column:workflow_run_id;index:idx_workflow_run_id" json:"workflowRunId"`
+	ScheduleID        *uint64 `gorm:"column:schedule_id;index:idx_schedule_id" json:"scheduleId"`
+	BatchID           *uint64 `gorm:"column:batch_id;index:idx_batch_id" json:"batchId"`
+	ReplayOfID        *uint64 `gorm:"column:replay_of_id;index:idx_replay_of_id" json:"replayOfId"` // 重放来源的执行记录ID
+	ExecutorID        *uint64 `gorm:"column:executor_id;not null;index:idx_executor_id" json:"executorId"`
+	ExecutorName      string  `gorm:"column:executor_name;type:varchar(50);not null;index:idx_executor_name" json:"executorName"`
+	RequestParams     *string `gorm:"column:request_params;type:longtext" json:"requestParams"`
+	RequestPathParams *string `gorm:"column:request_path_params;type:text" json:"requestPathParams"`
+	RequestHeaders    *string `gorm:"column:request_headers;type:longtext" json:"requestHeaders"`
+	RequestBody       *string `gorm:"column:request_body;type:longtext" json:"requestBody"`
+	RequestFiles      *string `gorm:"column:request_files;type:text" json:"requestFiles"`
+	ResponseStatus    *int    `gorm:"column:response_status" json:"responseStatus"`
+	ResponseHeaders   *string `gorm:"column:response_headers;type:longtext" json:"responseHeaders"`
+	ResponseBody      *string `gorm:"column:response_body;type:longtext" json:"responseBody"`
+	ResponseBodyInfo  *string `gorm:"column:response_body_info;type:longtext" json:"responseBodyInfo"` // 响应体元信息（JSON）
+	ResponseBlobKey   *string `gorm:"column:response_blob_key;type:varchar(100)" json:"-"`             // 完整响应体在本地目录中的文件名
+	RedirectChain     *string `gorm:"column:redirect_chain;type:text" json:"redirectChain"`
+	Attempts          *string `gorm:"column:attempts;type:text" json:"attempts"`          // 重试时每次请求的结果（JSON）
+	ParamSources      *string `gorm:"column:param_sources;type:text" json:"paramSources"` // 各参数取值的来源（JSON）
+	AssertionResults  *string `gorm:"column:assertion_results;type:text" json:"assertionResults"`
+	ExecutionTime     *int64  `gorm:"column:execution_time;type:bigint;index:idx_execution_time" json:"executionTime"`
+	Success           *bool   `gorm:"column:success;type:tinyint(1);not null;default:0;index:idx_success" json:"success"`
+	ErrorMessage      *string `gorm:"column:error_message;type:text" json:"errorMessage"`
+	Remark            *string `gorm:"column:remark;type:text" json:"remark"`
+	ClientIP          *string `gorm:"column:client_ip;type:varchar(50)" json:"clientIp"`
+	UserAgent         *string `gorm:"column:user_agent;type:varchar(500)" json:"userAgent"`
+	RawPayload        *string `gorm:"column:raw_payload;type:longtext" json:"-"` // 脱敏前的原始内容（加密存储）
 }
 
 func (ApiInterfaceExecutionRecord) TableName() string {
@@ -0,0 +1,17 @@
+package entity
+
+// ApiInterfaceGroup 接口分组实体类
+// 对应数据库表 api_interface_group，分组通过 parent_id 组成树形结构
+type ApiInterfaceGroup struct {
+	BaseEntity
+	Name        string  `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	ParentID    *uint64 `gorm:"column:parent_id;index:idx_parent_id" json:"parentId"`
+	Description *string `gorm:"column:description;type:varchar(255)" json:"description"`
+	Sort        int     `gorm:"column:sort;not null;default:0" json:"sort"`
+	// OwnerRoleID 所属团队（以角色表示），未设置时继承上级分组的团队
+	OwnerRoleID *uint64 `gorm:"column:owner_role_id;index:idx_owner_role_id" json:"ownerRoleId"`
+}
+
+func (ApiInterfaceGroup) TableName() string {
+	return "api_interface_group"
+}
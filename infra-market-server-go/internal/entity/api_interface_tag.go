@@ -0,0 +1,12 @@
+package entity
+
+// ApiInterfaceTag 接口标签实体类
+// 对应数据库表 api_interface_tag
+type ApiInterfaceTag struct {
+	BaseEntity
+	Name string `gorm:"column:name;type:varchar(50);not null;uniqueIndex:uk_name" json:"name"`
+}
+
+func (ApiInterfaceTag) TableName() string {
+	return "api_interface_tag"
+}
@@ -0,0 +1,13 @@
+package entity
+
+// ApiInterfaceTagRelation 接口标签关联实体类
+// 对应数据库表 api_interface_tag_relation
+type ApiInterfaceTagRelation struct {
+	BaseEntity
+	InterfaceID uint64 `gorm:"column:interface_id;not null;uniqueIndex:uk_interface_tag" json:"interfaceId"`
+	TagID       uint64 `gorm:"column:tag_id;not null;uniqueIndex:uk_interface_tag;index:idx_tag_id" json:"tagId"`
+}
+
+func (ApiInterfaceTagRelation) TableName() string {
+	return "api_interface_tag_relation"
+}
@@ -0,0 +1,21 @@
+package entity
+
+// ApiInterfaceVersion 接口定义版本实体类
+// 对应数据库表 api_interface_version，每次保存接口时记录保存后的完整定义
+type ApiInterfaceVersion struct {
+	BaseEntity
+	InterfaceID uint64 `gorm:"column:interface_id;not null;uniqueIndex:uk_interface_version,priority:1" json:"interfaceId"`
+	Version     int    `gorm:"column:version;not null;uniqueIndex:uk_interface_version,priority:2" json:"version"`
+	Action      string `gorm:"column:action;type:varchar(20);not null" json:"action"`
+	// RestoredFrom 恢复历史版本时对应的版本号
+	RestoredFrom *int `gorm:"column:restored_from" json:"restoredFrom"`
+	// EditorID 保存人，基线版本为空
+	EditorID   *uint64 `gorm:"column:editor_id;index:idx_editor_id" json:"editorId"`
+	EditorName *string `gorm:"column:editor_name;type:varchar(50)" json:"editorName"`
+	// Snapshot 接口定义快照（JSON），传输层密钥为密文
+	Snapshot string `gorm:"column:snapshot;type:longtext;not null" json:"-"`
+}
+
+func (ApiInterfaceVersion) TableName() string {
+	return "api_interface_version"
+}
@@ -0,0 +1,24 @@
+package entity
+
+// ApiSchedule 接口定时执行计划实体类
+// 对应数据库表 api_schedule，Params 以JSON保存执行参数，触发时以创建人身份执行
+type ApiSchedule struct {
+	BaseEntity
+	Name           string  `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	InterfaceID    uint64  `gorm:"column:interface_id;not null;index:idx_interface_id" json:"interfaceId"`
+	CronExpression string  `gorm:"column:cron_expression;type:varchar(100);not null" json:"cronExpression"`
+	TimeZone       string  `gorm:"column:time_zone;type:varchar(50);not null" json:"timeZone"`
+	Params         *string `gorm:"column:params;type:longtext" json:"params"`
+	Description    *string `gorm:"column:description;type:varchar(500)" json:"description"`
+	Status         int     `gorm:"column:status;type:tinyint;not null;default:1;index:idx_status" json:"status"`
+	NextFireTime   *int64  `gorm:"column:next_fire_time;type:bigint;index:idx_next_fire_time" json:"nextFireTime"`
+	LastFireTime   *int64  `gorm:"column:last_fire_time;type:bigint" json:"lastFireTime"`
+	LastSuccess    *bool   `gorm:"column:last_success;type:tinyint(1)" json:"lastSuccess"`
+	LastRecordID   *uint64 `gorm:"column:last_record_id" json:"lastRecordId"`
+	LastError      *string `gorm:"column:last_error;type:text" json:"lastError"`
+	CreatorID      uint64  `gorm:"column:creator_id;not null;default:0" json:"creatorId"`
+}
+
+func (ApiSchedule) TableName() string {
+	return "api_schedule"
+}
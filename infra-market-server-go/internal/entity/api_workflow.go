@@ -0,0 +1,37 @@
+package entity
+
+// ApiWorkflow 接口工作流实体类
+// 对应数据库表 api_workflow，Steps 以JSON数组保存有序的执行步骤
+type ApiWorkflow struct {
+	BaseEntity
+	Name          string  `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Description   *string `gorm:"column:description;type:varchar(500)" json:"description"`
+	EnvironmentID *uint64 `gorm:"column:environment_id;index:idx_environment_id" json:"environmentId"`
+	Steps         *string `gorm:"column:steps;type:longtext" json:"steps"`
+	Status        int     `gorm:"column:status;type:tinyint;not null;default:1;index:idx_status" json:"status"`
+}
+
+func (ApiWorkflow) TableName() string {
+	return "api_workflow"
+}
+
+// ApiWorkflowRun 接口工作流执行记录实体类
+// 对应数据库表 api_workflow_run，各步骤的接口执行记录通过 workflow_run_id 关联
+type ApiWorkflowRun struct {
+	BaseEntity
+	WorkflowID    uint64  `gorm:"column:workflow_id;not null;index:idx_workflow_id" json:"workflowId"`
+	WorkflowName  string  `gorm:"column:workflow_name;type:varchar(100);not null" json:"workflowName"`
+	EnvironmentID *uint64 `gorm:"column:environment_id" json:"environmentId"`
+	ExecutorID    uint64  `gorm:"column:executor_id;not null;index:idx_executor_id" json:"executorId"`
+	ExecutorName  string  `gorm:"column:executor_name;type:varchar(50);not null" json:"executorName"`
+	TotalSteps    int     `gorm:"column:total_steps;not null;default:0" json:"totalSteps"`
+	SuccessSteps  int     `gorm:"column:success_steps;not null;default:0" json:"successSteps"`
+	StepResults   *string `gorm:"column:step_results;type:longtext" json:"stepResults"`
+	ExecutionTime int64   `gorm:"column:execution_time;type:bigint" json:"executionTime"`
+	Success       bool    `gorm:"column:success;type:tinyint(1);not null;default:0;index:idx_success" json:"success"`
+	ErrorMessage  *string `gorm:"column:error_message;type:text" json:"errorMessage"`
+}
+
+func (ApiWorkflowRun) TableName() string {
+	return "api_workflow_run"
+}
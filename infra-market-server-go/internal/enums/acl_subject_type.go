@@ -0,0 +1,24 @@
+package enums
+
+// AclSubjectType 接口授权对象类型
+type AclSubjectType string
+
+const (
+	AclSubjectUser AclSubjectType = "USER" // 用户
+	AclSubjectRole AclSubjectType = "ROLE" // 角色
+)
+
+func (t AclSubjectType) Code() string {
+	return string(t)
+}
+
+func AclSubjectTypeFromCode(code string) *AclSubjectType {
+	types := map[string]AclSubjectType{
+		"USER": AclSubjectUser,
+		"ROLE": AclSubjectRole,
+	}
+	if subjectType, ok := types[code]; ok {
+		return &subjectType
+	}
+	return nil
+}
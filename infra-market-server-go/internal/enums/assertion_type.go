@@ -0,0 +1,57 @@
+package enums
+
+// AssertionType 响应断言类型枚举
+type AssertionType string
+
+const (
+	AssertionTypeStatusCode   AssertionType = "STATUS_CODE"   // 响应状态码
+	AssertionTypeJSONPath     AssertionType = "JSON_PATH"     // 响应体JSONPath取值
+	AssertionTypeHeader       AssertionType = "HEADER"        // 响应头
+	AssertionTypeResponseTime AssertionType = "RESPONSE_TIME" // 最大响应时间（毫秒）
+)
+
+func (a AssertionType) Code() string {
+	return string(a)
+}
+
+func AssertionTypeFromCode(code string) *AssertionType {
+	types := map[string]AssertionType{
+		"STATUS_CODE":   AssertionTypeStatusCode,
+		"JSON_PATH":     AssertionTypeJSONPath,
+		"HEADER":        AssertionTypeHeader,
+		"RESPONSE_TIME": AssertionTypeResponseTime,
+	}
+	if assertionType, ok := types[code]; ok {
+		return &assertionType
+	}
+	return nil
+}
+
+// AssertionOperator 响应断言比较方式枚举
+type AssertionOperator string
+
+const (
+	AssertionOperatorEquals   AssertionOperator = "EQUALS"   // 等于
+	AssertionOperatorContains AssertionOperator = "CONTAINS" // 包含
+	AssertionOperatorRegex    AssertionOperator = "REGEX"    // 正则匹配
+	AssertionOperatorExists   AssertionOperator = "EXISTS"   // 存在
+	AssertionOperatorRange    AssertionOperator = "RANGE"    // 区间，格式为 min-max，仅用于状态码
+)
+
+func (a AssertionOperator) Code() string {
+	return string(a)
+}
+
+func AssertionOperatorFromCode(code string) *AssertionOperator {
+	operators := map[string]AssertionOperator{
+		"EQUALS":   AssertionOperatorEquals,
+		"CONTAINS": AssertionOperatorContains,
+		"REGEX":    AssertionOperatorRegex,
+		"EXISTS":   AssertionOperatorExists,
+		"RANGE":    AssertionOperatorRange,
+	}
+	if operator, ok := operators[code]; ok {
+		return &operator
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package enums
+
+// AuthType 认证配置类型枚举
+type AuthType string
+
+const (
+	AuthTypeBasic                   AuthType = "BASIC"                     // HTTP Basic 认证
+	AuthTypeBearer                  AuthType = "BEARER"                    // 固定 Bearer Token
+	AuthTypeAPIKey                  AuthType = "API_KEY"                   // API Key，位于请求头或查询参数
+	AuthTypeHMAC                    AuthType = "HMAC"                      // HMAC 请求签名
+	AuthTypeOAuth2ClientCredentials AuthType = "OAUTH2_CLIENT_CREDENTIALS" // OAuth2 客户端凭证模式
+)
+
+func (a AuthType) Code() string {
+	return string(a)
+}
+
+func AuthTypeFromCode(code string) *AuthType {
+	types := map[string]AuthType{
+		"BASIC":                     AuthTypeBasic,
+		"BEARER":                    AuthTypeBearer,
+		"API_KEY":                   AuthTypeAPIKey,
+		"HMAC":                      AuthTypeHMAC,
+		"OAUTH2_CLIENT_CREDENTIALS": AuthTypeOAuth2ClientCredentials,
+	}
+	if authType, ok := types[code]; ok {
+		return &authType
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package enums
+
+// BatchStatus 批量执行状态枚举
+type BatchStatus string
+
+const (
+	BatchStatusRunning     BatchStatus = "RUNNING"     // 执行中
+	BatchStatusCompleted   BatchStatus = "COMPLETED"   // 全部行已执行
+	BatchStatusStopped     BatchStatus = "STOPPED"     // 出错后停止，剩余行未执行
+	BatchStatusInterrupted BatchStatus = "INTERRUPTED" // 服务重启等原因导致中断，剩余行未执行
+)
+
+func (b BatchStatus) Code() string {
+	return string(b)
+}
+
+func BatchStatusFromCode(code string) *BatchStatus {
+	statuses := map[string]BatchStatus{
+		"RUNNING":     BatchStatusRunning,
+		"COMPLETED":   BatchStatusCompleted,
+		"STOPPED":     BatchStatusStopped,
+		"INTERRUPTED": BatchStatusInterrupted,
+	}
+	if status, ok := statuses[code]; ok {
+		return &status
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package enums
+
+// BodyMode 请求体模式枚举
+type BodyMode string
+
+const (
+	BodyModeParams BodyMode = "PARAMS" // 根据Body参数组装请求体
+	BodyModeRaw    BodyMode = "RAW"    // 原样发送请求体模板
+)
+
+func (b BodyMode) Code() string {
+	return string(b)
+}
+
+func BodyModeFromCode(code string) *BodyMode {
+	modes := map[string]BodyMode{
+		"PARAMS": BodyModeParams,
+		"RAW":    BodyModeRaw,
+	}
+	if mode, ok := modes[code]; ok {
+		return &mode
+	}
+	return nil
+}
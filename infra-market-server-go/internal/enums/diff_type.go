@@ -0,0 +1,14 @@
+package enums
+
+// DiffType 响应对比差异类型
+type DiffType string
+
+const (
+	DiffTypeAdded   DiffType = "ADDED"   // 新响应中新增
+	DiffTypeRemoved DiffType = "REMOVED" // 新响应中缺失
+	DiffTypeChanged DiffType = "CHANGED" // 取值或类型变化
+)
+
+func (t DiffType) Code() string {
+	return string(t)
+}
@@ -0,0 +1,24 @@
+package enums
+
+// GroupDeleteStrategy 删除接口分组时分组内容（子分组与接口）的处理策略
+type GroupDeleteStrategy string
+
+const (
+	GroupDeleteMoveUp GroupDeleteStrategy = "MOVE_UP" // 移动到上级分组，顶级分组的内容变为未分组
+	GroupDeleteAll    GroupDeleteStrategy = "DELETE"  // 连同子分组与其中的接口一并删除
+)
+
+func (s GroupDeleteStrategy) Code() string {
+	return string(s)
+}
+
+func GroupDeleteStrategyFromCode(code string) *GroupDeleteStrategy {
+	strategies := map[string]GroupDeleteStrategy{
+		"MOVE_UP": GroupDeleteMoveUp,
+		"DELETE":  GroupDeleteAll,
+	}
+	if strategy, ok := strategies[code]; ok {
+		return &strategy
+	}
+	return nil
+}
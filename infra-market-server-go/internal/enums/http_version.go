@@ -0,0 +1,26 @@
+package enums
+
+// HttpVersion HTTP协议版本枚举
+type HttpVersion string
+
+const (
+	HttpVersionAuto   HttpVersion = "AUTO"     // 自动协商
+	HttpVersionHTTP11 HttpVersion = "HTTP/1.1" // 仅使用HTTP/1.1
+	HttpVersionHTTP2  HttpVersion = "HTTP/2"   // 仅使用HTTP/2（明文地址使用h2c）
+)
+
+func (v HttpVersion) Code() string {
+	return string(v)
+}
+
+func HttpVersionFromCode(code string) *HttpVersion {
+	versions := map[string]HttpVersion{
+		"AUTO":     HttpVersionAuto,
+		"HTTP/1.1": HttpVersionHTTP11,
+		"HTTP/2":   HttpVersionHTTP2,
+	}
+	if version, ok := versions[code]; ok {
+		return &version
+	}
+	return nil
+}
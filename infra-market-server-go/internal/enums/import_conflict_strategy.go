@@ -0,0 +1,41 @@
+package enums
+
+// ImportConflictStrategy 导入接口时已存在同名操作（相同方法与URL）的处理策略
+type ImportConflictStrategy string
+
+const (
+	ImportConflictSkip      ImportConflictStrategy = "SKIP"      // 跳过，保留已有接口
+	ImportConflictOverwrite ImportConflictStrategy = "OVERWRITE" // 覆盖已有接口
+	ImportConflictRename    ImportConflictStrategy = "RENAME"    // 重命名后作为新接口创建
+)
+
+func (s ImportConflictStrategy) Code() string {
+	return string(s)
+}
+
+func ImportConflictStrategyFromCode(code string) *ImportConflictStrategy {
+	strategies := map[string]ImportConflictStrategy{
+		"SKIP":      ImportConflictSkip,
+		"OVERWRITE": ImportConflictOverwrite,
+		"RENAME":    ImportConflictRename,
+	}
+	if strategy, ok := strategies[code]; ok {
+		return &strategy
+	}
+	return nil
+}
+
+// ImportAction 单个接口的导入结果动作
+type ImportAction string
+
+const (
+	ImportActionCreate    ImportAction = "CREATE"    // 新建
+	ImportActionOverwrite ImportAction = "OVERWRITE" // 覆盖已有接口
+	ImportActionRename    ImportAction = "RENAME"    // 重命名后新建
+	ImportActionSkip      ImportAction = "SKIP"      // 已存在，跳过
+	ImportActionFailed    ImportAction = "FAILED"    // 转换或保存失败
+)
+
+func (a ImportAction) Code() string {
+	return string(a)
+}
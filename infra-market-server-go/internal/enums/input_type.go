@@ -15,6 +15,7 @@ const (
 	InputTypePASSWORD    InputType = "PASSWORD"
 	InputTypeEMAIL       InputType = "EMAIL"
 	InputTypeURL         InputType = "URL"
+	InputTypeFILE        InputType = "FILE"
 )
 
 func (i InputType) Code() string {
@@ -34,6 +35,7 @@ func InputTypeFromCode(code string) *InputType {
 		"PASSWORD":     InputTypePASSWORD,
 		"EMAIL":        InputTypeEMAIL,
 		"URL":          InputTypeURL,
+		"FILE":         InputTypeFILE,
 	}
 	if inputType, ok := types[code]; ok {
 		return &inputType
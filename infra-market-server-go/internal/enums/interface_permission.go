@@ -0,0 +1,44 @@
+package enums
+
+// InterfacePermission 接口权限，级别由低到高，高级别包含低级别的全部权限
+type InterfacePermission string
+
+const (
+	InterfacePermissionView    InterfacePermission = "VIEW"    // 查看
+	InterfacePermissionExecute InterfacePermission = "EXECUTE" // 查看与执行
+	InterfacePermissionEdit    InterfacePermission = "EDIT"    // 查看、执行、修改与删除
+	InterfacePermissionOwner   InterfacePermission = "OWNER"   // 负责人，拥有全部权限并可以管理授权
+)
+
+func (p InterfacePermission) Code() string {
+	return string(p)
+}
+
+// Level 权限级别，数值越大权限越高
+func (p InterfacePermission) Level() int {
+	levels := map[InterfacePermission]int{
+		InterfacePermissionView:    1,
+		InterfacePermissionExecute: 2,
+		InterfacePermissionEdit:    3,
+		InterfacePermissionOwner:   4,
+	}
+	return levels[p]
+}
+
+// Includes 判断是否包含指定权限
+func (p InterfacePermission) Includes(required InterfacePermission) bool {
+	return p.Level() >= required.Level()
+}
+
+func InterfacePermissionFromCode(code string) *InterfacePermission {
+	permissions := map[string]InterfacePermission{
+		"VIEW":    InterfacePermissionView,
+		"EXECUTE": InterfacePermissionExecute,
+		"EDIT":    InterfacePermissionEdit,
+		"OWNER":   InterfacePermissionOwner,
+	}
+	if permission, ok := permissions[code]; ok {
+		return &permission
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package enums
+
+// InterfaceVersionAction 接口版本的产生方式
+type InterfaceVersionAction string
+
+const (
+	InterfaceVersionActionBaseline InterfaceVersionAction = "BASELINE" // 启用版本记录前已存在的接口定义
+	InterfaceVersionActionCreate   InterfaceVersionAction = "CREATE"   // 新建接口
+	InterfaceVersionActionUpdate   InterfaceVersionAction = "UPDATE"   // 编辑接口
+	InterfaceVersionActionCopy     InterfaceVersionAction = "COPY"     // 复制接口
+	InterfaceVersionActionRestore  InterfaceVersionAction = "RESTORE"  // 恢复历史版本
+)
+
+func (a InterfaceVersionAction) Code() string {
+	return string(a)
+}
@@ -0,0 +1,14 @@
+package enums
+
+// ParamSource 执行时参数取值的来源
+type ParamSource string
+
+const (
+	ParamSourceUser    ParamSource = "USER"    // 调用方传入
+	ParamSourceDefault ParamSource = "DEFAULT" // 未传入时使用默认值
+	ParamSourceLocked  ParamSource = "LOCKED"  // 不可修改的参数，固定使用默认值
+)
+
+func (s ParamSource) Code() string {
+	return string(s)
+}
@@ -7,6 +7,7 @@ const (
 	ParamTypeURL    ParamType = "URL_PARAM"
 	ParamTypeBody   ParamType = "BODY_PARAM"
 	ParamTypeHeader ParamType = "HEADER_PARAM"
+	ParamTypePath   ParamType = "PATH_PARAM"
 )
 
 func (p ParamType) Code() string {
@@ -18,6 +19,7 @@ func ParamTypeFromCode(code string) *ParamType {
 		"URL_PARAM":    ParamTypeURL,
 		"BODY_PARAM":   ParamTypeBody,
 		"HEADER_PARAM": ParamTypeHeader,
+		"PATH_PARAM":   ParamTypePath,
 	}
 	if paramType, ok := types[code]; ok {
 		return &paramType
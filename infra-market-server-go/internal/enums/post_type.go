@@ -6,16 +6,60 @@ type PostType string
 const (
 	PostTypeApplicationJSON               PostType = "application/json"
 	PostTypeApplicationXWWWFormURLEncoded PostType = "application/x-www-form-urlencoded"
+	PostTypeMultipartFormData             PostType = "multipart/form-data"
+
+	// 原始请求体类型（RAW模式）
+	PostTypeApplicationXML PostType = "application/xml"
+	PostTypeTextXML        PostType = "text/xml"
+	PostTypeTextPlain      PostType = "text/plain"
 )
 
 func (p PostType) Code() string {
 	return string(p)
 }
 
+// IsRawBodyType 是否可用于RAW模式的请求体类型
+func (p PostType) IsRawBodyType() bool {
+	switch p {
+	case PostTypeApplicationJSON, PostTypeApplicationXML, PostTypeTextXML, PostTypeTextPlain:
+		return true
+	}
+	return false
+}
+
+// DataType 请求体类型对应的代码编辑器数据类型
+func (p PostType) DataType() DataType {
+	switch p {
+	case PostTypeApplicationJSON:
+		return DataTypeJSON
+	case PostTypeApplicationXML, PostTypeTextXML:
+		return DataTypeXML
+	}
+	return DataTypeTEXT
+}
+
 func PostTypeFromCode(code string) *PostType {
 	types := map[string]PostType{
 		"application/json":                  PostTypeApplicationJSON,
 		"application/x-www-form-urlencoded": PostTypeApplicationXWWWFormURLEncoded,
+		"multipart/form-data":               PostTypeMultipartFormData,
+		"application/xml":                   PostTypeApplicationXML,
+		"text/xml":                          PostTypeTextXML,
+		"text/plain":                        PostTypeTextPlain,
+	}
+	if postType, ok := types[code]; ok {
+		return &postType
+	}
+	return nil
+}
+
+// PostTypeFromDataType 根据代码编辑器数据类型推导RAW模式的请求体类型
+func PostTypeFromDataType(code string) *PostType {
+	types := map[string]PostType{
+		"JSON":        PostTypeApplicationJSON,
+		"JSON_OBJECT": PostTypeApplicationJSON,
+		"XML":         PostTypeApplicationXML,
+		"TEXT":        PostTypeTextPlain,
 	}
 	if postType, ok := types[code]; ok {
 		return &postType
@@ -5,6 +5,21 @@ const (
 	// 系统权限编码
 	SystemPermissionCode = "system"
 
+	// 查看未脱敏执行记录的权限编码
+	RecordUnmaskedPermissionCode = "interface:execution:record:unmasked"
+
+	// 接口或环境配置跳过TLS证书校验的权限编码
+	TransportInsecurePermissionCode = "interface:transport:insecure"
+
+	// 管理认证配置的权限编码
+	AuthProfileManagePermissionCode = "interface:auth-profile:manage"
+
+	// 接口或环境引用认证配置的权限编码
+	AuthProfileUsePermissionCode = "interface:auth-profile:use"
+
+	// 管理执行环境的权限编码
+	EnvironmentManagePermissionCode = "interface:environment:manage"
+
 	// 系统角色编码
 	AdminRoleCode = "admin"
 
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-go/stringx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiAuthProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewApiAuthProfileRepository(db *gorm.DB) *ApiAuthProfileRepository {
+	return &ApiAuthProfileRepository{db: db}
+}
+
+// FindByID 根据ID查询
+func (r *ApiAuthProfileRepository) FindByID(id uint64) (*entity.ApiAuthProfile, error) {
+	var profile entity.ApiAuthProfile
+	err := r.db.First(&profile, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Page 分页查询
+func (r *ApiAuthProfileRepository) Page(query dto.ApiAuthProfileQueryDto) ([]entity.ApiAuthProfile, int64, error) {
+	var profiles []entity.ApiAuthProfile
+
+	db := ApplyNameStatusFilters(r.db.Model(&entity.ApiAuthProfile{}), query.Name, query.Status)
+	if !stringx.IsEmpty(query.Type) {
+		db = db.Where("type = ?", *query.Type)
+	}
+
+	return PaginateQuery(db, &query, "id DESC", &profiles)
+}
+
+// ListEnabled 查询所有启用的认证配置
+func (r *ApiAuthProfileRepository) ListEnabled() ([]entity.ApiAuthProfile, error) {
+	var profiles []entity.ApiAuthProfile
+	err := r.db.Where("status = ?", 1).Order("id ASC").Find(&profiles).Error
+	return profiles, err
+}
+
+// Create 创建认证配置
+func (r *ApiAuthProfileRepository) Create(profile *entity.ApiAuthProfile) error {
+	return r.db.Create(profile).Error
+}
+
+// Update 更新认证配置
+func (r *ApiAuthProfileRepository) Update(profile *entity.ApiAuthProfile) error {
+	return r.db.Save(profile).Error
+}
+
+// Delete 删除认证配置
+func (r *ApiAuthProfileRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.ApiAuthProfile{}, id).Error
+}
+
+// CountReferences 统计引用该认证配置的接口和环境数量
+func (r *ApiAuthProfileRepository) CountReferences(id uint64) (int64, error) {
+	var interfaceCount, environmentCount int64
+	if err := r.db.Model(&entity.ApiInterface{}).Where("auth_profile_id = ?", id).Count(&interfaceCount).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&entity.ApiEnvironment{}).Where("auth_profile_id = ?", id).Count(&environmentCount).Error; err != nil {
+		return 0, err
+	}
+	return interfaceCount + environmentCount, nil
+}
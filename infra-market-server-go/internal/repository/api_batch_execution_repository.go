@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-go/stringx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"gorm.io/gorm"
+)
+
+type ApiBatchExecutionRepository struct {
+	db *gorm.DB
+}
+
+func NewApiBatchExecutionRepository(db *gorm.DB) *ApiBatchExecutionRepository {
+	return &ApiBatchExecutionRepository{db: db}
+}
+
+// FindByID 根据ID查询
+func (r *ApiBatchExecutionRepository) FindByID(id uint64) (*entity.ApiBatchExecution, error) {
+	var batch entity.ApiBatchExecution
+	err := r.db.First(&batch, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// Page 分页查询
+func (r *ApiBatchExecutionRepository) Page(query dto.ApiBatchExecutionQueryDto) ([]entity.ApiBatchExecution, int64, error) {
+	var batches []entity.ApiBatchExecution
+
+	db := r.db.Model(&entity.ApiBatchExecution{})
+	if query.InterfaceID != nil {
+		db = db.Where("interface_id = ?", *query.InterfaceID)
+	}
+	if !stringx.IsEmpty(query.Status) {
+		db = db.Where("status = ?", *query.Status)
+	}
+	if query.ViewerUID != nil {
+		// 与接口列表一致：没有授权记录的接口所有人可见，否则需要授权给查询人或其角色
+		restricted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id")
+		granted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id").
+			Where("(subject_type = ? AND subject_id = ?) OR (subject_type = ? AND subject_id IN ?)",
+				enums.AclSubjectUser.Code(), *query.ViewerUID, enums.AclSubjectRole.Code(), query.ViewerRoleIDs)
+		db = db.Where("(interface_id NOT IN (?) OR interface_id IN (?))", restricted, granted)
+	}
+
+	return PaginateQuery(db, &query, "id DESC", &batches)
+}
+
+// Create 创建批量执行
+func (r *ApiBatchExecutionRepository) Create(batch *entity.ApiBatchExecution) error {
+	return r.db.Create(batch).Error
+}
+
+// Update 更新批量执行
+func (r *ApiBatchExecutionRepository) Update(batch *entity.ApiBatchExecution) error {
+	return r.db.Save(batch).Error
+}
+
+// FindStaleRunning 查询执行中且在指定时间后没有更新过的批量执行
+func (r *ApiBatchExecutionRepository) FindStaleRunning(status string, updatedBefore int64) ([]entity.ApiBatchExecution, error) {
+	var batches []entity.ApiBatchExecution
+	err := r.db.Where("status = ? AND update_time < ?", status, updatedBefore).Find(&batches).Error
+	return batches, err
+}
+
+// UpdateFields 更新批量执行的指定字段
+func (r *ApiBatchExecutionRepository) UpdateFields(id uint64, fields map[string]any) error {
+	return r.db.Model(&entity.ApiBatchExecution{}).Where("id = ?", id).Updates(fields).Error
+}
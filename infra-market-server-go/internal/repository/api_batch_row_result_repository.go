@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiBatchRowResultRepository struct {
+	db *gorm.DB
+}
+
+func NewApiBatchRowResultRepository(db *gorm.DB) *ApiBatchRowResultRepository {
+	return &ApiBatchRowResultRepository{db: db}
+}
+
+// FindByBatchID 按行号查询批次的逐行结果
+func (r *ApiBatchRowResultRepository) FindByBatchID(batchID uint64) ([]entity.ApiBatchRowResult, error) {
+	var results []entity.ApiBatchRowResult
+	err := r.db.Where("batch_id = ?", batchID).Order("row_index ASC").Find(&results).Error
+	return results, err
+}
+
+// FindFailedByBatchID 查询批次中执行失败（不含跳过）的行
+func (r *ApiBatchRowResultRepository) FindFailedByBatchID(batchID uint64) ([]entity.ApiBatchRowResult, error) {
+	var results []entity.ApiBatchRowResult
+	err := r.db.Where("batch_id = ? AND success = ? AND skipped = ?", batchID, false, false).
+		Order("row_index ASC").
+		Find(&results).Error
+	return results, err
+}
+
+// Create 创建单行结果
+func (r *ApiBatchRowResultRepository) Create(result *entity.ApiBatchRowResult) error {
+	return r.db.Create(result).Error
+}
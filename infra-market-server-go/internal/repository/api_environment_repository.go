@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiEnvironmentRepository struct {
+	db *gorm.DB
+}
+
+func NewApiEnvironmentRepository(db *gorm.DB) *ApiEnvironmentRepository {
+	return &ApiEnvironmentRepository{db: db}
+}
+
+// FindByID 根据ID查询
+func (r *ApiEnvironmentRepository) FindByID(id uint64) (*entity.ApiEnvironment, error) {
+	var environment entity.ApiEnvironment
+	err := r.db.First(&environment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &environment, nil
+}
+
+// FindByCode 根据编码查询
+func (r *ApiEnvironmentRepository) FindByCode(code string) (*entity.ApiEnvironment, error) {
+	var environment entity.ApiEnvironment
+	err := r.db.Where("code = ?", code).First(&environment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &environment, nil
+}
+
+// FindByIDs 批量查询
+func (r *ApiEnvironmentRepository) FindByIDs(ids []uint64) ([]entity.ApiEnvironment, error) {
+	if len(ids) == 0 {
+		return []entity.ApiEnvironment{}, nil
+	}
+	var environments []entity.ApiEnvironment
+	err := r.db.Where("id IN ?", ids).Find(&environments).Error
+	return environments, err
+}
+
+// Page 分页查询
+func (r *ApiEnvironmentRepository) Page(query dto.ApiEnvironmentQueryDto) ([]entity.ApiEnvironment, int64, error) {
+	var environments []entity.ApiEnvironment
+
+	db := ApplyNameStatusFilters(r.db.Model(&entity.ApiEnvironment{}), query.Name, query.Status)
+
+	return PaginateQuery(db, &query, "sort ASC, id ASC", &environments)
+}
+
+// ListEnabled 查询所有启用的环境
+func (r *ApiEnvironmentRepository) ListEnabled() ([]entity.ApiEnvironment, error) {
+	var environments []entity.ApiEnvironment
+	err := r.db.Where("status = ?", 1).Order("sort ASC, id ASC").Find(&environments).Error
+	return environments, err
+}
+
+// Create 创建环境
+func (r *ApiEnvironmentRepository) Create(environment *entity.ApiEnvironment) error {
+	return r.db.Create(environment).Error
+}
+
+// Update 更新环境
+func (r *ApiEnvironmentRepository) Update(environment *entity.ApiEnvironment) error {
+	return r.db.Save(environment).Error
+}
+
+// Delete 删除环境
+func (r *ApiEnvironmentRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.ApiEnvironment{}, id).Error
+}
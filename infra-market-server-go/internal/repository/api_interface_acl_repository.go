@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiInterfaceAclRepository struct {
+	db *gorm.DB
+}
+
+func NewApiInterfaceAclRepository(db *gorm.DB) *ApiInterfaceAclRepository {
+	return &ApiInterfaceAclRepository{db: db}
+}
+
+// FindByInterfaceID 查询接口的全部授权
+func (r *ApiInterfaceAclRepository) FindByInterfaceID(interfaceID uint64) ([]entity.ApiInterfaceAcl, error) {
+	var acls []entity.ApiInterfaceAcl
+	err := r.db.Where("interface_id = ?", interfaceID).Order("id ASC").Find(&acls).Error
+	return acls, err
+}
+
+// FindByInterfaceIDs 批量查询接口的授权
+func (r *ApiInterfaceAclRepository) FindByInterfaceIDs(interfaceIDs []uint64) ([]entity.ApiInterfaceAcl, error) {
+	if len(interfaceIDs) == 0 {
+		return []entity.ApiInterfaceAcl{}, nil
+	}
+	var acls []entity.ApiInterfaceAcl
+	err := r.db.Where("interface_id IN ?", interfaceIDs).Order("id ASC").Find(&acls).Error
+	return acls, err
+}
+
+// Create 创建授权
+func (r *ApiInterfaceAclRepository) Create(acl *entity.ApiInterfaceAcl) error {
+	return r.db.Create(acl).Error
+}
+
+// FindInterfaceIDsWithoutAcl 查询没有任何授权记录的接口ID
+func (r *ApiInterfaceAclRepository) FindInterfaceIDsWithoutAcl() ([]uint64, error) {
+	var ids []uint64
+	granted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id")
+	err := r.db.Model(&entity.ApiInterface{}).Where("id NOT IN (?)", granted).Order("id ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// DeleteByInterfaceIDs 删除接口的全部授权
+func (r *ApiInterfaceAclRepository) DeleteByInterfaceIDs(interfaceIDs []uint64) error {
+	if len(interfaceIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("interface_id IN ?", interfaceIDs).Delete(&entity.ApiInterfaceAcl{}).Error
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/bucketheadv/infra-go/stringx"
 	"github.com/bucketheadv/infra-market/internal/dto"
 	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
 	"gorm.io/gorm"
 )
 
@@ -27,15 +28,51 @@ func (r *ApiInterfaceExecutionRecordRepository) FindByID(id uint64) (*entity.Api
 	return &record, nil
 }
 
+// FindByIDs 批量查询
+func (r *ApiInterfaceExecutionRecordRepository) FindByIDs(ids []uint64) ([]entity.ApiInterfaceExecutionRecord, error) {
+	if len(ids) == 0 {
+		return []entity.ApiInterfaceExecutionRecord{}, nil
+	}
+	var records []entity.ApiInterfaceExecutionRecord
+	err := r.db.Where("id IN ?", ids).Find(&records).Error
+	return records, err
+}
+
 // Page 分页查询
 func (r *ApiInterfaceExecutionRecordRepository) Page(query dto.ApiInterfaceExecutionRecordQueryDto) ([]entity.ApiInterfaceExecutionRecord, int64, error) {
 	var records []entity.ApiInterfaceExecutionRecord
+	db := r.applyFilters(r.db.Model(&entity.ApiInterfaceExecutionRecord{}), query)
+	return PaginateQuery(db, &query, "id DESC", &records)
+}
 
-	db := r.db.Model(&entity.ApiInterfaceExecutionRecord{})
+// FindAll 按查询条件查询执行记录（忽略分页参数），最多返回 limit 条
+func (r *ApiInterfaceExecutionRecordRepository) FindAll(query dto.ApiInterfaceExecutionRecordQueryDto, limit int) ([]entity.ApiInterfaceExecutionRecord, error) {
+	var records []entity.ApiInterfaceExecutionRecord
+	db := r.applyFilters(r.db.Model(&entity.ApiInterfaceExecutionRecord{}), query)
+	err := db.Order("id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
 
+// applyFilters 应用执行记录查询条件
+func (r *ApiInterfaceExecutionRecordRepository) applyFilters(db *gorm.DB, query dto.ApiInterfaceExecutionRecordQueryDto) *gorm.DB {
+	if len(query.IDs) > 0 {
+		db = db.Where("id IN ?", query.IDs)
+	}
 	if query.InterfaceID != nil {
 		db = db.Where("interface_id = ?", *query.InterfaceID)
 	}
+	if query.WorkflowRunID != nil {
+		db = db.Where("workflow_run_id = ?", *query.WorkflowRunID)
+	}
+	if query.ScheduleID != nil {
+		db = db.Where("schedule_id = ?", *query.ScheduleID)
+	}
+	if query.BatchID != nil {
+		db = db.Where("batch_id = ?", *query.BatchID)
+	}
+	if query.ReplayOfID != nil {
+		db = db.Where("replay_of_id = ?", *query.ReplayOfID)
+	}
 
 	// 关键字查询：在执行人姓名、错误信息、备注等字段中搜索
 	if !stringx.IsEmpty(query.Keyword) {
@@ -64,18 +101,15 @@ func (r *ApiInterfaceExecutionRecordRepository) Page(query dto.ApiInterfaceExecu
 	if query.EndTime != nil {
 		db = db.Where("create_time <= ?", *query.EndTime)
 	}
-
-	return PaginateQuery(db, &query, "id DESC", &records)
-}
-
-// FindByExecutorID 根据执行人ID查询
-func (r *ApiInterfaceExecutionRecordRepository) FindByExecutorID(executorID uint64, limit int) ([]entity.ApiInterfaceExecutionRecord, error) {
-	var records []entity.ApiInterfaceExecutionRecord
-	err := r.db.Where("executor_id = ?", executorID).
-		Order("create_time DESC").
-		Limit(limit).
-		Find(&records).Error
-	return records, err
+	if query.ViewerUID != nil {
+		// 与接口列表一致：没有授权记录的接口所有人可见，否则需要授权给查询人或其角色
+		restricted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id")
+		granted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id").
+			Where("(subject_type = ? AND subject_id = ?) OR (subject_type = ? AND subject_id IN ?)",
+				enums.AclSubjectUser.Code(), *query.ViewerUID, enums.AclSubjectRole.Code(), query.ViewerRoleIDs)
+		db = db.Where("(interface_id NOT IN (?) OR interface_id IN (?))", restricted, granted)
+	}
+	return db
 }
 
 // Create 创建执行记录
@@ -92,6 +126,19 @@ func (r *ApiInterfaceExecutionRecordRepository) CountByTimeRange(startTime, endT
 	return count, err
 }
 
+// FindBlobKeys 查询仍被执行记录引用的响应体文件名
+func (r *ApiInterfaceExecutionRecordRepository) FindBlobKeys(keys []string) ([]string, error) {
+	var referenced []string
+	if len(keys) == 0 {
+		return referenced, nil
+	}
+	err := r.db.Model(&entity.ApiInterfaceExecutionRecord{}).
+		Where("response_blob_key IN ?", keys).
+		Distinct().
+		Pluck("response_blob_key", &referenced).Error
+	return referenced, err
+}
+
 // DeleteByTimeBefore 删除指定时间之前的记录
 func (r *ApiInterfaceExecutionRecordRepository) DeleteByTimeBefore(beforeTime int64) (int64, error) {
 	result := r.db.Where("create_time < ?", beforeTime).
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiInterfaceGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewApiInterfaceGroupRepository(db *gorm.DB) *ApiInterfaceGroupRepository {
+	return &ApiInterfaceGroupRepository{db: db}
+}
+
+// FindByID 根据ID查询
+func (r *ApiInterfaceGroupRepository) FindByID(id uint64) (*entity.ApiInterfaceGroup, error) {
+	var group entity.ApiInterfaceGroup
+	err := r.db.First(&group, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// FindAll 查询全部分组
+func (r *ApiInterfaceGroupRepository) FindAll() ([]entity.ApiInterfaceGroup, error) {
+	var groups []entity.ApiInterfaceGroup
+	err := r.db.Order("sort ASC, id ASC").Find(&groups).Error
+	return groups, err
+}
+
+// ExistsByParentAndName 判断同一上级分组下是否已存在同名分组，excludeID 为更新时排除的分组
+func (r *ApiInterfaceGroupRepository) ExistsByParentAndName(parentID *uint64, name string, excludeID uint64) (bool, error) {
+	var count int64
+	db := r.db.Model(&entity.ApiInterfaceGroup{}).Where("name = ? AND id != ?", name, excludeID)
+	if parentID == nil {
+		db = db.Where("parent_id IS NULL")
+	} else {
+		db = db.Where("parent_id = ?", *parentID)
+	}
+	err := db.Count(&count).Error
+	return count > 0, err
+}
+
+// Create 创建分组
+func (r *ApiInterfaceGroupRepository) Create(group *entity.ApiInterfaceGroup) error {
+	return r.db.Create(group).Error
+}
+
+// Update 更新分组
+func (r *ApiInterfaceGroupRepository) Update(group *entity.ApiInterfaceGroup) error {
+	return r.db.Save(group).Error
+}
+
+// UpdateParentID 将指定上级分组下的子分组移动到新的上级分组
+func (r *ApiInterfaceGroupRepository) UpdateParentID(parentID uint64, newParentID *uint64) error {
+	return r.db.Model(&entity.ApiInterfaceGroup{}).
+		Where("parent_id = ?", parentID).
+		Update("parent_id", newParentID).Error
+}
+
+// DeleteByIDs 批量删除分组
+func (r *ApiInterfaceGroupRepository) DeleteByIDs(ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", ids).Delete(&entity.ApiInterfaceGroup{}).Error
+}
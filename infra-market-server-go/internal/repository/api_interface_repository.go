@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/bucketheadv/infra-go/stringx"
 	"github.com/bucketheadv/infra-market/internal/dto"
 	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
 	"gorm.io/gorm"
 )
 
@@ -35,6 +38,16 @@ func (r *ApiInterfaceRepository) FindByIDs(ids []uint64) ([]entity.ApiInterface,
 	return interfaces, err
 }
 
+// FindAllByIDs 批量查询（不区分状态）
+func (r *ApiInterfaceRepository) FindAllByIDs(ids []uint64) ([]entity.ApiInterface, error) {
+	if len(ids) == 0 {
+		return []entity.ApiInterface{}, nil
+	}
+	var interfaces []entity.ApiInterface
+	err := r.db.Where("id IN ?", ids).Order("id ASC").Find(&interfaces).Error
+	return interfaces, err
+}
+
 // Page 分页查询
 func (r *ApiInterfaceRepository) Page(query dto.ApiInterfaceQueryDto) ([]entity.ApiInterface, int64, error) {
 	var interfaces []entity.ApiInterface
@@ -53,10 +66,46 @@ func (r *ApiInterfaceRepository) Page(query dto.ApiInterfaceQueryDto) ([]entity.
 	if !stringx.IsEmpty(query.Environment) {
 		db = db.Where("environment = ?", *query.Environment)
 	}
+	if query.EnvironmentID != nil {
+		db = db.Where("environment_id = ?", *query.EnvironmentID)
+	}
+	if query.GroupID != nil {
+		if *query.GroupID == 0 {
+			db = db.Where("group_id IS NULL")
+		} else {
+			db = db.Where("group_id IN ?", query.GroupIDs)
+		}
+	}
+	if query.TagID != nil {
+		tagged := r.db.Model(&entity.ApiInterfaceTagRelation{}).Select("interface_id").Where("tag_id = ?", *query.TagID)
+		db = db.Where("id IN (?)", tagged)
+	}
+	if query.ViewerUID != nil {
+		// 没有授权记录的接口所有人可见，否则需要授权给查询人或其角色
+		restricted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id")
+		granted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id").
+			Where("(subject_type = ? AND subject_id = ?) OR (subject_type = ? AND subject_id IN ?)",
+				enums.AclSubjectUser.Code(), *query.ViewerUID, enums.AclSubjectRole.Code(), query.ViewerRoleIDs)
+		db = db.Where("(id NOT IN (?) OR id IN (?))", restricted, granted)
+	}
 
 	return PaginateQuery(db, &query, "create_time DESC", &interfaces)
 }
 
+// FindByMethodAndURL 根据请求方法和URL查询接口
+func (r *ApiInterfaceRepository) FindByMethodAndURL(method, url string) ([]entity.ApiInterface, error) {
+	var interfaces []entity.ApiInterface
+	err := r.db.Where("method = ? AND url = ?", method, url).Order("create_time DESC").Find(&interfaces).Error
+	return interfaces, err
+}
+
+// ExistsByName 判断接口名称是否已存在
+func (r *ApiInterfaceRepository) ExistsByName(name string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.ApiInterface{}).Where("name = ?", name).Count(&count).Error
+	return count > 0, err
+}
+
 // Create 创建接口
 func (r *ApiInterfaceRepository) Create(apiInterface *entity.ApiInterface) error {
 	return r.db.Create(apiInterface).Error
@@ -72,6 +121,65 @@ func (r *ApiInterfaceRepository) Delete(id uint64) error {
 	return r.db.Delete(&entity.ApiInterface{}, id).Error
 }
 
+// DeleteByIDs 批量删除接口
+func (r *ApiInterfaceRepository) DeleteByIDs(ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", ids).Delete(&entity.ApiInterface{}).Error
+}
+
+// FindIDsByGroupIDs 查询分组下的接口ID
+func (r *ApiInterfaceRepository) FindIDsByGroupIDs(groupIDs []uint64) ([]uint64, error) {
+	ids := make([]uint64, 0)
+	if len(groupIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&entity.ApiInterface{}).Where("group_id IN ?", groupIDs).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// UpdateGroupID 批量移动接口到指定分组，groupID 为空表示移出分组
+func (r *ApiInterfaceRepository) UpdateGroupID(ids []uint64, groupID *uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&entity.ApiInterface{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{"group_id": groupID, "update_time": time.Now().UnixMilli()}).Error
+}
+
+// MoveGroupInterfaces 将分组下的接口移动到新的分组
+func (r *ApiInterfaceRepository) MoveGroupInterfaces(groupID uint64, newGroupID *uint64) error {
+	return r.db.Model(&entity.ApiInterface{}).
+		Where("group_id = ?", groupID).
+		Updates(map[string]any{"group_id": newGroupID, "update_time": time.Now().UnixMilli()}).Error
+}
+
+// CountByGroupIDs 统计各分组下直接包含的接口数量
+func (r *ApiInterfaceRepository) CountByGroupIDs(groupIDs []uint64) (map[uint64]int64, error) {
+	counts := make(map[uint64]int64)
+	if len(groupIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		GroupID uint64
+		Count   int64
+	}
+	err := r.db.Model(&entity.ApiInterface{}).
+		Select("group_id, COUNT(*) AS count").
+		Where("group_id IN ?", groupIDs).
+		Group("group_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.GroupID] = row.Count
+	}
+	return counts, nil
+}
+
 // Count 获取接口总数
 func (r *ApiInterfaceRepository) Count() (int64, error) {
 	var count int64
@@ -79,6 +187,15 @@ func (r *ApiInterfaceRepository) Count() (int64, error) {
 	return count, err
 }
 
+// CountByEnvironmentID 统计使用指定默认环境的接口数量
+func (r *ApiInterfaceRepository) CountByEnvironmentID(environmentID uint64) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.ApiInterface{}).
+		Where("environment_id = ?", environmentID).
+		Count(&count).Error
+	return count, err
+}
+
 // CountBeforeDate 获取指定时间之前的接口总数
 func (r *ApiInterfaceRepository) CountBeforeDate(timestamp int64) (int64, error) {
 	var count int64
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiInterfaceTagRelationRepository struct {
+	db *gorm.DB
+}
+
+func NewApiInterfaceTagRelationRepository(db *gorm.DB) *ApiInterfaceTagRelationRepository {
+	return &ApiInterfaceTagRelationRepository{db: db}
+}
+
+// FindByInterfaceIDs 批量查询接口的标签关联
+func (r *ApiInterfaceTagRelationRepository) FindByInterfaceIDs(interfaceIDs []uint64) ([]entity.ApiInterfaceTagRelation, error) {
+	if len(interfaceIDs) == 0 {
+		return []entity.ApiInterfaceTagRelation{}, nil
+	}
+	var relations []entity.ApiInterfaceTagRelation
+	err := r.db.Where("interface_id IN ?", interfaceIDs).Find(&relations).Error
+	return relations, err
+}
+
+// FindInterfaceIDsByTagID 查询关联了标签的接口ID
+func (r *ApiInterfaceTagRelationRepository) FindInterfaceIDsByTagID(tagID uint64) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&entity.ApiInterfaceTagRelation{}).Where("tag_id = ?", tagID).Pluck("interface_id", &ids).Error
+	return ids, err
+}
+
+// CountByTagIDs 统计各标签关联的接口数量
+func (r *ApiInterfaceTagRelationRepository) CountByTagIDs(tagIDs []uint64) (map[uint64]int64, error) {
+	counts := make(map[uint64]int64)
+	if len(tagIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		TagID uint64
+		Count int64
+	}
+	err := r.db.Model(&entity.ApiInterfaceTagRelation{}).
+		Select("tag_id, COUNT(*) AS count").
+		Where("tag_id IN ?", tagIDs).
+		Group("tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+// Create 创建标签关联
+func (r *ApiInterfaceTagRelationRepository) Create(relation *entity.ApiInterfaceTagRelation) error {
+	return r.db.Create(relation).Error
+}
+
+// DeleteByInterfaceIDs 删除接口的所有标签关联
+func (r *ApiInterfaceTagRelationRepository) DeleteByInterfaceIDs(interfaceIDs []uint64) error {
+	if len(interfaceIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("interface_id IN ?", interfaceIDs).Delete(&entity.ApiInterfaceTagRelation{}).Error
+}
+
+// DeleteByTagID 删除标签的所有关联
+func (r *ApiInterfaceTagRelationRepository) DeleteByTagID(tagID uint64) error {
+	return r.db.Where("tag_id = ?", tagID).Delete(&entity.ApiInterfaceTagRelation{}).Error
+}
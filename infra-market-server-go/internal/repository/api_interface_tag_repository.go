@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiInterfaceTagRepository struct {
+	db *gorm.DB
+}
+
+func NewApiInterfaceTagRepository(db *gorm.DB) *ApiInterfaceTagRepository {
+	return &ApiInterfaceTagRepository{db: db}
+}
+
+// FindAll 查询全部标签
+func (r *ApiInterfaceTagRepository) FindAll() ([]entity.ApiInterfaceTag, error) {
+	var tags []entity.ApiInterfaceTag
+	err := r.db.Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+// FindByIDs 批量查询
+func (r *ApiInterfaceTagRepository) FindByIDs(ids []uint64) ([]entity.ApiInterfaceTag, error) {
+	if len(ids) == 0 {
+		return []entity.ApiInterfaceTag{}, nil
+	}
+	var tags []entity.ApiInterfaceTag
+	err := r.db.Where("id IN ?", ids).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+// FindByNames 根据名称批量查询
+func (r *ApiInterfaceTagRepository) FindByNames(names []string) ([]entity.ApiInterfaceTag, error) {
+	if len(names) == 0 {
+		return []entity.ApiInterfaceTag{}, nil
+	}
+	var tags []entity.ApiInterfaceTag
+	err := r.db.Where("name IN ?", names).Find(&tags).Error
+	return tags, err
+}
+
+// Create 创建标签
+func (r *ApiInterfaceTagRepository) Create(tag *entity.ApiInterfaceTag) error {
+	return r.db.Create(tag).Error
+}
+
+// Delete 删除标签
+func (r *ApiInterfaceTagRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.ApiInterfaceTag{}, id).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ApiInterfaceVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewApiInterfaceVersionRepository(db *gorm.DB) *ApiInterfaceVersionRepository {
+	return &ApiInterfaceVersionRepository{db: db}
+}
+
+// FindByID 根据ID查询
+func (r *ApiInterfaceVersionRepository) FindByID(id uint64) (*entity.ApiInterfaceVersion, error) {
+	var version entity.ApiInterfaceVersion
+	err := r.db.First(&version, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// Page 分页查询接口的版本，按版本号倒序
+func (r *ApiInterfaceVersionRepository) Page(query dto.ApiInterfaceVersionQueryDto) ([]entity.ApiInterfaceVersion, int64, error) {
+	var versions []entity.ApiInterfaceVersion
+
+	// 列表不返回快照，避免加载大字段
+	db := r.db.Model(&entity.ApiInterfaceVersion{}).Omit("snapshot").
+		Where("interface_id = ?", *query.InterfaceID)
+
+	return PaginateQuery(db, &query, "version DESC", &versions)
+}
+
+// MaxVersion 获取接口当前最大的版本号，没有版本时返回0
+func (r *ApiInterfaceVersionRepository) MaxVersion(interfaceID uint64) (int, error) {
+	var version int
+	err := r.db.Model(&entity.ApiInterfaceVersion{}).
+		Where("interface_id = ?", interfaceID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	return version, err
+}
+
+// FindCreatorID 查询接口的创建人：第一个版本为新建或复制时的保存人，无法确定时返回 nil
+func (r *ApiInterfaceVersionRepository) FindCreatorID(interfaceID uint64, actions []string) (*uint64, error) {
+	var versions []entity.ApiInterfaceVersion
+	err := r.db.Where("interface_id = ?", interfaceID).Order("version ASC").Limit(1).Find(&versions).Error
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	for _, action := range actions {
+		if versions[0].Action == action {
+			return versions[0].EditorID, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create 创建版本
+func (r *ApiInterfaceVersionRepository) Create(version *entity.ApiInterfaceVersion) error {
+	return r.db.Create(version).Error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"gorm.io/gorm"
+)
+
+type ApiScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewApiScheduleRepository(db *gorm.DB) *ApiScheduleRepository {
+	return &ApiScheduleRepository{db: db}
+}
+
+// FindByID 根据ID查询
+func (r *ApiScheduleRepository) FindByID(id uint64) (*entity.ApiSchedule, error) {
+	var schedule entity.ApiSchedule
+	err := r.db.First(&schedule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Page 分页查询
+func (r *ApiScheduleRepository) Page(query dto.ApiScheduleQueryDto) ([]entity.ApiSchedule, int64, error) {
+	var schedules []entity.ApiSchedule
+
+	db := ApplyNameStatusFilters(r.db.Model(&entity.ApiSchedule{}), query.Name, query.Status)
+	if query.InterfaceID != nil {
+		db = db.Where("interface_id = ?", *query.InterfaceID)
+	}
+	if query.ViewerUID != nil {
+		// 没有授权记录的接口所有人可执行，否则需要授予查询人或其角色执行及以上权限
+		restricted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id")
+		executable := []string{enums.InterfacePermissionExecute.Code(), enums.InterfacePermissionEdit.Code(), enums.InterfacePermissionOwner.Code()}
+		granted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id").
+			Where("permission IN ? AND ((subject_type = ? AND subject_id = ?) OR (subject_type = ? AND subject_id IN ?))",
+				executable, enums.AclSubjectUser.Code(), *query.ViewerUID, enums.AclSubjectRole.Code(), query.ViewerRoleIDs)
+		db = db.Where("(interface_id NOT IN (?) OR interface_id IN (?))", restricted, granted)
+	}
+
+	return PaginateQuery(db, &query, "id DESC", &schedules)
+}
+
+// FindDue 查询已到触发时间的启用计划
+func (r *ApiScheduleRepository) FindDue(now int64) ([]entity.ApiSchedule, error) {
+	var schedules []entity.ApiSchedule
+	err := r.db.Where("status = ? AND next_fire_time IS NOT NULL AND next_fire_time <= ?", 1, now).
+		Order("next_fire_time ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// Create 创建计划
+func (r *ApiScheduleRepository) Create(schedule *entity.ApiSchedule) error {
+	return r.db.Create(schedule).Error
+}
+
+// Update 更新计划
+func (r *ApiScheduleRepository) Update(schedule *entity.ApiSchedule) error {
+	return r.db.Save(schedule).Error
+}
+
+// UpdateFields 更新计划的部分字段
+func (r *ApiScheduleRepository) UpdateFields(id uint64, fields map[string]any) error {
+	return r.db.Model(&entity.ApiSchedule{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// Delete 删除计划
+func (r *ApiScheduleRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.ApiSchedule{}, id).Error
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"gorm.io/gorm"
+)
+
+type ApiWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func NewApiWorkflowRepository(db *gorm.DB) *ApiWorkflowRepository {
+	return &ApiWorkflowRepository{db: db}
+}
+
+// FindByID 根据ID查询
+func (r *ApiWorkflowRepository) FindByID(id uint64) (*entity.ApiWorkflow, error) {
+	var workflow entity.ApiWorkflow
+	err := r.db.First(&workflow, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// Page 分页查询
+func (r *ApiWorkflowRepository) Page(query dto.ApiWorkflowQueryDto) ([]entity.ApiWorkflow, int64, error) {
+	var workflows []entity.ApiWorkflow
+
+	db := ApplyNameStatusFilters(r.db.Model(&entity.ApiWorkflow{}), query.Name, query.Status)
+
+	return PaginateQuery(db, &query, "id DESC", &workflows)
+}
+
+// Create 创建工作流
+func (r *ApiWorkflowRepository) Create(workflow *entity.ApiWorkflow) error {
+	return r.db.Create(workflow).Error
+}
+
+// Update 更新工作流
+func (r *ApiWorkflowRepository) Update(workflow *entity.ApiWorkflow) error {
+	return r.db.Save(workflow).Error
+}
+
+// Delete 删除工作流
+func (r *ApiWorkflowRepository) Delete(id uint64) error {
+	return r.db.Delete(&entity.ApiWorkflow{}, id).Error
+}
+
+// FindRunByID 根据ID查询执行记录
+func (r *ApiWorkflowRepository) FindRunByID(id uint64) (*entity.ApiWorkflowRun, error) {
+	var run entity.ApiWorkflowRun
+	err := r.db.First(&run, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// PageRuns 分页查询执行记录
+func (r *ApiWorkflowRepository) PageRuns(query dto.ApiWorkflowRunQueryDto) ([]entity.ApiWorkflowRun, int64, error) {
+	var runs []entity.ApiWorkflowRun
+
+	db := r.db.Model(&entity.ApiWorkflowRun{})
+	if query.WorkflowID != nil {
+		db = db.Where("workflow_id = ?", *query.WorkflowID)
+	}
+	if query.Success != nil {
+		db = db.Where("success = ?", *query.Success)
+	}
+	if query.ViewerUID != nil {
+		// 排除包含不可见接口步骤的执行记录，接口可见规则与接口列表一致
+		restricted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id")
+		granted := r.db.Model(&entity.ApiInterfaceAcl{}).Select("interface_id").
+			Where("(subject_type = ? AND subject_id = ?) OR (subject_type = ? AND subject_id IN ?)",
+				enums.AclSubjectUser.Code(), *query.ViewerUID, enums.AclSubjectRole.Code(), query.ViewerRoleIDs)
+		hidden := r.db.Model(&entity.ApiInterfaceExecutionRecord{}).Select("workflow_run_id").
+			Where("workflow_run_id IS NOT NULL AND interface_id IN (?) AND interface_id NOT IN (?)", restricted, granted)
+		db = db.Where("id NOT IN (?)", hidden)
+	}
+
+	return PaginateQuery(db, &query, "id DESC", &runs)
+}
+
+// CreateRun 创建执行记录
+func (r *ApiWorkflowRepository) CreateRun(run *entity.ApiWorkflowRun) error {
+	return r.db.Create(run).Error
+}
+
+// UpdateRun 更新执行记录
+func (r *ApiWorkflowRepository) UpdateRun(run *entity.ApiWorkflowRun) error {
+	return r.db.Save(run).Error
+}
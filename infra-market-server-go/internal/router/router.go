@@ -13,5 +13,11 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) (*gin.Engine, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := c.Migrate(); err != nil {
+		return nil, err
+	}
+	if err := c.StartScheduler(); err != nil {
+		return nil, err
+	}
 	return c.SetupRouter()
 }
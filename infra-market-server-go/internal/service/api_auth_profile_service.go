@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+	"github.com/go-redis/redis/v8"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	oauth2TokenPrefix       = "auth:oauth2:token:"
+	oauth2TokenExpireBuffer = 60 // 提前过期的秒数，避免使用即将失效的Token
+	oauth2TokenTimeout      = 30 * time.Second
+
+	defaultHMACSignatureHeader = "X-Signature"
+	defaultHMACTimestampHeader = "X-Timestamp"
+	defaultHMACAccessKeyHeader = "X-Access-Key"
+)
+
+// ApiAuthProfileService 认证配置管理
+// 规则：管理认证配置需要管理权限；接口或环境新引用认证配置需要使用或管理权限，保持原有引用时不再校验
+type ApiAuthProfileService struct {
+	authProfileRepo *repository.ApiAuthProfileRepository
+	redisClient     *redis.Client
+	authService     *AuthService
+	cipher          *util.SecretCipher
+}
+
+func NewApiAuthProfileService(
+	authProfileRepo *repository.ApiAuthProfileRepository,
+	redisClient *redis.Client,
+	authService *AuthService,
+	cipher *util.SecretCipher,
+) *ApiAuthProfileService {
+	return &ApiAuthProfileService{
+		authProfileRepo: authProfileRepo,
+		redisClient:     redisClient,
+		authService:     authService,
+		cipher:          cipher,
+	}
+}
+
+// FindPage 分页查询认证配置
+func (s *ApiAuthProfileService) FindPage(query dto.ApiAuthProfileQueryDto) dto.ApiData[dto.PageResult[dto.ApiAuthProfileDto]] {
+	profiles, total, err := s.authProfileRepo.Page(query)
+	return PageResultBuilder(profiles, total, err, s.convertToDto, &query)
+}
+
+// FindAllEnabled 查询所有启用的认证配置
+func (s *ApiAuthProfileService) FindAllEnabled() dto.ApiData[[]dto.ApiAuthProfileDto] {
+	profiles, err := s.authProfileRepo.ListEnabled()
+	if err != nil {
+		return dto.Error[[]dto.ApiAuthProfileDto]("查询失败", http.StatusInternalServerError)
+	}
+
+	result := make([]dto.ApiAuthProfileDto, len(profiles))
+	for i := range profiles {
+		result[i] = s.convertToDto(&profiles[i])
+	}
+	return dto.Success(result)
+}
+
+// FindByID 根据ID查询
+func (s *ApiAuthProfileService) FindByID(id uint64) dto.ApiData[dto.ApiAuthProfileDto] {
+	profile, err := s.authProfileRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiAuthProfileDto]("认证配置不存在", http.StatusNotFound)
+	}
+	return dto.Success(s.convertToDto(profile))
+}
+
+// Save 创建认证配置，需要管理权限
+func (s *ApiAuthProfileService) Save(form dto.ApiAuthProfileFormDto, uid uint64) dto.ApiData[dto.ApiAuthProfileDto] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[dto.ApiAuthProfileDto](err.Error(), http.StatusForbidden)
+	}
+	profile := &entity.ApiAuthProfile{Status: 1}
+	if err := s.applyForm(profile, &form); err != nil {
+		return dto.Error[dto.ApiAuthProfileDto](err.Error(), http.StatusBadRequest)
+	}
+
+	if err := s.authProfileRepo.Create(profile); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "创建认证配置失败，配置名称: %s, 错误: %v\n", form.Name, err)
+		return dto.Error[dto.ApiAuthProfileDto]("创建认证配置失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(profile))
+}
+
+// Update 更新认证配置，需要管理权限
+func (s *ApiAuthProfileService) Update(id uint64, form dto.ApiAuthProfileFormDto, uid uint64) dto.ApiData[dto.ApiAuthProfileDto] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[dto.ApiAuthProfileDto](err.Error(), http.StatusForbidden)
+	}
+	profile, err := s.authProfileRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiAuthProfileDto]("认证配置不存在", http.StatusNotFound)
+	}
+
+	if err := s.applyForm(profile, &form); err != nil {
+		return dto.Error[dto.ApiAuthProfileDto](err.Error(), http.StatusBadRequest)
+	}
+
+	if err := s.authProfileRepo.Update(profile); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新认证配置失败，配置ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiAuthProfileDto]("更新认证配置失败", http.StatusInternalServerError)
+	}
+
+	// 配置变更后缓存的OAuth2 Token可能已失效
+	s.clearCachedToken(id)
+
+	return dto.Success(s.convertToDto(profile))
+}
+
+// Delete 删除认证配置，需要管理权限
+func (s *ApiAuthProfileService) Delete(id uint64, uid uint64) dto.ApiData[any] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[any](err.Error(), http.StatusForbidden)
+	}
+	if _, err := s.authProfileRepo.FindByID(id); err != nil {
+		return dto.Error[any]("认证配置不存在", http.StatusNotFound)
+	}
+
+	// 被接口或环境引用时不允许删除
+	count, err := s.authProfileRepo.CountReferences(id)
+	if err != nil {
+		return dto.Error[any]("删除认证配置失败", http.StatusInternalServerError)
+	}
+	if count > 0 {
+		return dto.Error[any]("该认证配置已被接口或环境引用，无法删除", http.StatusBadRequest)
+	}
+
+	if err := s.authProfileRepo.Delete(id); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "删除认证配置失败，配置ID: %d, 错误: %v\n", id, err)
+		return dto.Error[any]("删除认证配置失败", http.StatusInternalServerError)
+	}
+	s.clearCachedToken(id)
+	return dto.Success[any](nil)
+}
+
+// UpdateStatus 更新认证配置状态，需要管理权限
+func (s *ApiAuthProfileService) UpdateStatus(id uint64, status int, uid uint64) dto.ApiData[dto.ApiAuthProfileDto] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[dto.ApiAuthProfileDto](err.Error(), http.StatusForbidden)
+	}
+	profile, err := s.authProfileRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiAuthProfileDto]("认证配置不存在", http.StatusNotFound)
+	}
+
+	profile.Status = status
+	if err := s.authProfileRepo.Update(profile); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新认证配置状态失败，配置ID: %d, 状态: %d, 错误: %v\n", id, status, err)
+		return dto.Error[dto.ApiAuthProfileDto]("更新状态失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(profile))
+}
+
+// ValidateReference 校验接口或环境引用的认证配置是否存在，currentID 为原有引用
+// 引用新的认证配置需要使用或管理权限，避免借用他人的认证配置向任意地址发送凭证
+func (s *ApiAuthProfileService) ValidateReference(profileID, currentID *uint64, uid uint64) error {
+	if profileID == nil {
+		return nil
+	}
+	if _, err := s.authProfileRepo.FindByID(*profileID); err != nil {
+		return fmt.Errorf("认证配置不存在")
+	}
+	if currentID != nil && *currentID == *profileID {
+		return nil
+	}
+	if uid == 0 || !(s.authService.HasPermission(uid, enums.AuthProfileUsePermissionCode) ||
+		s.authService.HasPermission(uid, enums.AuthProfileManagePermissionCode)) {
+		return fmt.Errorf("无权使用该认证配置")
+	}
+	return nil
+}
+
+// checkManagePermission 校验用户是否可以管理认证配置
+func (s *ApiAuthProfileService) checkManagePermission(uid uint64) error {
+	if uid == 0 || !s.authService.HasPermission(uid, enums.AuthProfileManagePermissionCode) {
+		return fmt.Errorf("无权管理认证配置")
+	}
+	return nil
+}
+
+// Resolve 获取执行时使用的认证配置：接口配置优先，其次为环境配置
+func (s *ApiAuthProfileService) Resolve(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment) (*entity.ApiAuthProfile, error) {
+	profileID := apiInterface.AuthProfileID
+	if profileID == nil && environment != nil {
+		profileID = environment.AuthProfileID
+	}
+	if profileID == nil {
+		return nil, nil
+	}
+
+	profile, err := s.authProfileRepo.FindByID(*profileID)
+	if err != nil {
+		return nil, fmt.Errorf("认证配置不存在，配置ID: %d", *profileID)
+	}
+	if profile.Status != 1 {
+		return nil, fmt.Errorf("认证配置 %s 已禁用", profile.Name)
+	}
+	return profile, nil
+}
+
+// Apply 在发送前将认证信息写入请求
+// 认证信息只作用于实际发出的请求，不会出现在执行记录的请求头和请求参数中
+func (s *ApiAuthProfileService) Apply(request *resty.Request, profile *entity.ApiAuthProfile, method, rawURL, body string) error {
+	if profile == nil {
+		return nil
+	}
+
+	config := parseAuthProfileConfig(profile)
+	secret, err := s.decryptSecret(profile)
+	if err != nil {
+		return err
+	}
+
+	switch enums.AuthType(profile.Type) {
+	case enums.AuthTypeBasic:
+		request.SetBasicAuth(config.Username, secret)
+	case enums.AuthTypeBearer:
+		request.SetAuthToken(secret)
+	case enums.AuthTypeAPIKey:
+		if strings.EqualFold(config.In, "QUERY") {
+			request.SetQueryParam(config.KeyName, secret)
+		} else {
+			request.SetHeader(config.KeyName, secret)
+		}
+	case enums.AuthTypeHMAC:
+		return s.applyHMAC(request, config, secret, method, rawURL, body)
+	case enums.AuthTypeOAuth2ClientCredentials:
+		token, err := s.fetchOAuth2Token(profile.ID, config, secret)
+		if err != nil {
+			return err
+		}
+		request.SetAuthToken(token)
+	default:
+		return fmt.Errorf("不支持的认证类型: %s", profile.Type)
+	}
+	return nil
+}
+
+// applyHMAC 对请求签名
+// 待签名字符串为 METHOD\nPATH\nTIMESTAMP\nBODY，签名结果为十六进制小写
+func (s *ApiAuthProfileService) applyHMAC(request *resty.Request, config dto.ApiAuthProfileConfigDto, secret, method, rawURL, body string) error {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("解析请求URL失败: %w", err)
+	}
+	path := parsedURL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	var hashFunc func() hash.Hash
+	switch strings.ToUpper(config.Algorithm) {
+	case "", "SHA256":
+		hashFunc = sha256.New
+	case "SHA1":
+		hashFunc = sha1.New
+	case "SHA512":
+		hashFunc = sha512.New
+	default:
+		return fmt.Errorf("不支持的HMAC算法: %s", config.Algorithm)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	stringToSign := strings.Join([]string{strings.ToUpper(method), path, timestamp, body}, "\n")
+	mac := hmac.New(hashFunc, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	request.SetHeader(headerOrDefault(config.TimestampHeader, defaultHMACTimestampHeader), timestamp)
+	request.SetHeader(headerOrDefault(config.SignatureHeader, defaultHMACSignatureHeader), signature)
+	if config.AccessKey != "" {
+		request.SetHeader(headerOrDefault(config.AccessKeyHeader, defaultHMACAccessKeyHeader), config.AccessKey)
+	}
+	return nil
+}
+
+// fetchOAuth2Token 获取OAuth2访问令牌，优先使用Redis中缓存的Token
+func (s *ApiAuthProfileService) fetchOAuth2Token(profileID uint64, config dto.ApiAuthProfileConfigDto, secret string) (string, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("%s%d", oauth2TokenPrefix, profileID)
+	if token, err := s.redisClient.Get(ctx, key).Result(); err == nil && token != "" {
+		return token, nil
+	}
+
+	formData := map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     config.ClientID,
+		"client_secret": secret,
+	}
+	if config.Scope != "" {
+		formData["scope"] = config.Scope
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	resp, err := resty.New().SetTimeout(oauth2TokenTimeout).R().
+		SetFormData(formData).
+		SetResult(&tokenResp).
+		Post(config.TokenURL)
+	if err != nil {
+		return "", fmt.Errorf("获取OAuth2 Token失败: %w", err)
+	}
+	if !resp.IsSuccess() || tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("获取OAuth2 Token失败，状态码: %d, 响应: %s", resp.StatusCode(), resp.String())
+	}
+
+	// 按过期时间缓存Token，未返回过期时间时不缓存
+	if tokenResp.ExpiresIn > 0 {
+		ttl := tokenResp.ExpiresIn - oauth2TokenExpireBuffer
+		if ttl <= 0 {
+			ttl = tokenResp.ExpiresIn / 2
+		}
+		if ttl > 0 {
+			s.redisClient.Set(ctx, key, tokenResp.AccessToken, time.Duration(ttl)*time.Second)
+		}
+	}
+	return tokenResp.AccessToken, nil
+}
+
+// clearCachedToken 清除缓存的OAuth2 Token
+func (s *ApiAuthProfileService) clearCachedToken(profileID uint64) {
+	s.redisClient.Del(context.Background(), fmt.Sprintf("%s%d", oauth2TokenPrefix, profileID))
+}
+
+// decryptSecret 解密密钥
+func (s *ApiAuthProfileService) decryptSecret(profile *entity.ApiAuthProfile) (string, error) {
+	if profile.Secret == nil || *profile.Secret == "" {
+		return "", nil
+	}
+	secret, err := s.cipher.Decrypt(*profile.Secret)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解密认证配置密钥失败，配置ID: %d, 错误: %v\n", profile.ID, err)
+		return "", fmt.Errorf("认证配置 %s 的密钥无法解密", profile.Name)
+	}
+	return secret, nil
+}
+
+// applyForm 校验表单并写入实体，密钥加密保存
+func (s *ApiAuthProfileService) applyForm(profile *entity.ApiAuthProfile, form *dto.ApiAuthProfileFormDto) error {
+	authType := enums.AuthTypeFromCode(form.Type)
+	if authType == nil {
+		return fmt.Errorf("不支持的认证类型: %s", form.Type)
+	}
+	if err := validateAuthProfileConfig(*authType, form.Config); err != nil {
+		return err
+	}
+
+	profile.Name = form.Name
+	profile.Type = form.Type
+	profile.Description = form.Description
+	if jsonBytes, err := json.Marshal(form.Config); err == nil {
+		profile.Config = basic.Ptr(string(jsonBytes))
+	}
+	if form.Secret != nil && *form.Secret != "" {
+		encrypted, err := s.cipher.Encrypt(*form.Secret)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "加密认证配置密钥失败: %v\n", err)
+			return fmt.Errorf("密钥加密失败")
+		}
+		profile.Secret = basic.Ptr(encrypted)
+	}
+	if profile.Secret == nil || *profile.Secret == "" {
+		return fmt.Errorf("密钥不能为空")
+	}
+	if form.Status != nil {
+		profile.Status = *form.Status
+	}
+	return nil
+}
+
+// convertToDto 转换实体为DTO
+func (s *ApiAuthProfileService) convertToDto(profile *entity.ApiAuthProfile) dto.ApiAuthProfileDto {
+	return dto.ApiAuthProfileDto{
+		ID:          profile.ID,
+		Name:        profile.Name,
+		Type:        profile.Type,
+		Config:      parseAuthProfileConfig(profile),
+		HasSecret:   profile.Secret != nil && *profile.Secret != "",
+		Description: profile.Description,
+		Status:      profile.Status,
+		CreateTime:  util.Format(&profile.CreateTime),
+		UpdateTime:  util.Format(&profile.UpdateTime),
+	}
+}
+
+// validateAuthProfileConfig 按认证类型校验必填配置
+func validateAuthProfileConfig(authType enums.AuthType, config dto.ApiAuthProfileConfigDto) error {
+	switch authType {
+	case enums.AuthTypeBasic:
+		if config.Username == "" {
+			return fmt.Errorf("Basic认证的用户名不能为空")
+		}
+	case enums.AuthTypeAPIKey:
+		if config.KeyName == "" {
+			return fmt.Errorf("API Key的名称不能为空")
+		}
+		if config.In != "" && !strings.EqualFold(config.In, "HEADER") && !strings.EqualFold(config.In, "QUERY") {
+			return fmt.Errorf("API Key的位置只能是 HEADER 或 QUERY")
+		}
+	case enums.AuthTypeHMAC:
+		switch strings.ToUpper(config.Algorithm) {
+		case "", "SHA256", "SHA1", "SHA512":
+		default:
+			return fmt.Errorf("不支持的HMAC算法: %s", config.Algorithm)
+		}
+	case enums.AuthTypeOAuth2ClientCredentials:
+		if config.TokenURL == "" || config.ClientID == "" {
+			return fmt.Errorf("OAuth2的Token地址和Client ID不能为空")
+		}
+		if _, err := url.ParseRequestURI(config.TokenURL); err != nil {
+			return fmt.Errorf("OAuth2的Token地址不正确")
+		}
+	}
+	return nil
+}
+
+// parseAuthProfileConfig 解析认证配置JSON
+func parseAuthProfileConfig(profile *entity.ApiAuthProfile) dto.ApiAuthProfileConfigDto {
+	var config dto.ApiAuthProfileConfigDto
+	if profile.Config == nil || *profile.Config == "" {
+		return config
+	}
+	if err := json.Unmarshal([]byte(*profile.Config), &config); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析认证配置失败，配置ID: %d, 错误: %v\n", profile.ID, err)
+	}
+	return config
+}
+
+// headerOrDefault 未配置请求头名称时使用默认值
+func headerOrDefault(header, defaultHeader string) string {
+	if header == "" {
+		return defaultHeader
+	}
+	return header
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/util"
+)
+
+func TestAuthProfileSecretUsesConfiguredKey(t *testing.T) {
+	s := &ApiAuthProfileService{cipher: newTestSecretCipher(t)}
+	profile := &entity.ApiAuthProfile{Name: "token"}
+
+	form := dto.ApiAuthProfileFormDto{Name: "token", Type: "BEARER", Secret: stringPtr("bearer-token")}
+	if err := s.applyForm(profile, &form); err != nil {
+		t.Fatalf("applyForm() error: %v", err)
+	}
+	if strings.Contains(stringValue(profile.Secret), "bearer-token") {
+		t.Fatalf("密钥未加密保存: %s", stringValue(profile.Secret))
+	}
+	if legacy, err := util.Decrypt(stringValue(profile.Secret)); err == nil && legacy == "bearer-token" {
+		t.Errorf("密钥不应使用默认密钥加密")
+	}
+	if got, err := s.decryptSecret(profile); err != nil || got != "bearer-token" {
+		t.Errorf("decryptSecret() = %q, %v, want bearer-token", got, err)
+	}
+
+	// 未填写密钥时保留原密钥
+	form.Secret = nil
+	if err := s.applyForm(profile, &form); err != nil {
+		t.Fatalf("applyForm() error: %v", err)
+	}
+	if got, _ := s.decryptSecret(profile); got != "bearer-token" {
+		t.Errorf("更新后密钥 = %q, want bearer-token", got)
+	}
+
+	// 兼容早期使用默认密钥加密的密钥
+	legacy, _ := util.Encrypt("legacy-token")
+	profile.Secret = stringPtr(legacy)
+	if got, err := s.decryptSecret(profile); err != nil || got != "legacy-token" {
+		t.Errorf("decryptSecret(legacy) = %q, %v, want legacy-token", got, err)
+	}
+}
+
+func TestAuthProfilePermissionRequiresUser(t *testing.T) {
+	s := &ApiAuthProfileService{}
+	if err := s.checkManagePermission(0); err == nil {
+		t.Errorf("checkManagePermission(0) 应返回错误")
+	}
+	if result := s.Save(dto.ApiAuthProfileFormDto{Name: "token", Type: "BEARER"}, 0); result.Code != 403 {
+		t.Errorf("Save() code = %d, want 403", result.Code)
+	}
+	if err := s.ValidateReference(nil, nil, 0); err != nil {
+		t.Errorf("ValidateReference(nil) error: %v", err)
+	}
+}
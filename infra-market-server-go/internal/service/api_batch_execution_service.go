@@ -0,0 +1,555 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+)
+
+const (
+	batchMaxRows            = 10000 // 单次批量执行的最大行数
+	batchDefaultConcurrency = 1
+	batchHeartbeatInterval  = 30 * time.Second // 执行中的批次刷新更新时间的间隔
+	batchStaleTimeout       = 2 * time.Minute  // 执行中的批次超过该时长未更新视为已中断
+	batchRecoveryInterval   = time.Minute      // 检查中断批次的间隔
+)
+
+type ApiBatchExecutionService struct {
+	batchRepo           *repository.ApiBatchExecutionRepository
+	rowResultRepo       *repository.ApiBatchRowResultRepository
+	apiInterfaceRepo    *repository.ApiInterfaceRepository
+	userRepo            *repository.UserRepository
+	apiInterfaceService *ApiInterfaceService
+	redactor            *SecretRedactor
+	aclService          *ApiInterfaceAclService
+	recoveryOnce        sync.Once
+}
+
+func NewApiBatchExecutionService(
+	batchRepo *repository.ApiBatchExecutionRepository,
+	rowResultRepo *repository.ApiBatchRowResultRepository,
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	userRepo *repository.UserRepository,
+	apiInterfaceService *ApiInterfaceService,
+	redactor *SecretRedactor,
+	aclService *ApiInterfaceAclService,
+) *ApiBatchExecutionService {
+	return &ApiBatchExecutionService{
+		batchRepo:           batchRepo,
+		rowResultRepo:       rowResultRepo,
+		apiInterfaceRepo:    apiInterfaceRepo,
+		userRepo:            userRepo,
+		apiInterfaceService: apiInterfaceService,
+		redactor:            redactor,
+		aclService:          aclService,
+	}
+}
+
+// Execute 创建批量执行并在后台逐行执行，立即返回批次信息，需要接口的执行权限
+func (s *ApiBatchExecutionService) Execute(req dto.ApiBatchExecuteRequestDto, executorID uint64, clientIP, userAgent string) dto.ApiData[dto.ApiBatchExecutionDto] {
+	if len(req.Rows) == 0 {
+		return dto.Error[dto.ApiBatchExecutionDto]("数据集不能为空", http.StatusBadRequest)
+	}
+	if len(req.Rows) > batchMaxRows {
+		return dto.Error[dto.ApiBatchExecutionDto](fmt.Sprintf("数据集行数不能超过 %d", batchMaxRows), http.StatusBadRequest)
+	}
+
+	apiInterface, err := s.apiInterfaceRepo.FindByID(*req.InterfaceID)
+	if err != nil {
+		return dto.Error[dto.ApiBatchExecutionDto]("接口不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(apiInterface.ID, executorID, enums.InterfacePermissionExecute); err != nil {
+		return dto.Error[dto.ApiBatchExecutionDto](err.Error(), http.StatusForbidden)
+	}
+
+	executorName := "未知用户"
+	if user, err := s.userRepo.FindByUID(executorID); err == nil {
+		executorName = user.Username
+	}
+
+	concurrency := batchDefaultConcurrency
+	if req.Concurrency != nil {
+		concurrency = *req.Concurrency
+	}
+
+	batch := &entity.ApiBatchExecution{
+		InterfaceID:   apiInterface.ID,
+		EnvironmentID: req.EnvironmentID,
+		ExecutorID:    executorID,
+		ExecutorName:  executorName,
+		FileName:      req.FileName,
+		Concurrency:   concurrency,
+		StopOnError:   req.StopOnError,
+		Status:        enums.BatchStatusRunning.Code(),
+		TotalRows:     len(req.Rows),
+	}
+	if err := s.batchRepo.Create(batch); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "创建批量执行失败，接口ID: %d, 错误: %v\n", apiInterface.ID, err)
+		return dto.Error[dto.ApiBatchExecutionDto]("创建批量执行失败", http.StatusInternalServerError)
+	}
+
+	// 后台执行会修改 batch，需在启动前转换结果
+	result := s.convertToDto(batch)
+	go s.run(batch, apiInterface, req, executorID, clientIP, userAgent)
+
+	return dto.Success(result)
+}
+
+// FindPage 分页查询批量执行，只返回可见接口的批次
+func (s *ApiBatchExecutionService) FindPage(query dto.ApiBatchExecutionQueryDto, uid uint64) dto.ApiData[dto.PageResult[dto.ApiBatchExecutionDto]] {
+	s.aclService.ApplyBatchVisibility(&query, uid)
+	batches, total, err := s.batchRepo.Page(query)
+	return PageResultBuilder(batches, total, err, s.convertToDto, &query)
+}
+
+// FindByID 查询批量执行汇总，包含失败行，需要接口的查看权限
+func (s *ApiBatchExecutionService) FindByID(id uint64, uid uint64) dto.ApiData[dto.ApiBatchExecutionDto] {
+	batch, err := s.batchRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiBatchExecutionDto]("批量执行不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(batch.InterfaceID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiBatchExecutionDto](err.Error(), http.StatusForbidden)
+	}
+
+	failed, err := s.rowResultRepo.FindFailedByBatchID(id)
+	if err != nil {
+		return dto.Error[dto.ApiBatchExecutionDto]("查询批量执行结果失败", http.StatusInternalServerError)
+	}
+
+	secretParams := s.secretParams(batch.InterfaceID)
+	result := s.convertToDto(batch)
+	result.FailedResults = make([]dto.ApiBatchRowResultDto, 0, len(failed))
+	for i := range failed {
+		result.FailedResults = append(result.FailedResults, s.convertRowResultToDto(&failed[i], secretParams))
+	}
+	return dto.Success(result)
+}
+
+// ExportCSV 导出批量执行结果为CSV，需要接口的查看权限
+func (s *ApiBatchExecutionService) ExportCSV(id uint64, uid uint64) dto.ApiData[[]byte] {
+	batch, err := s.batchRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[[]byte]("批量执行不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(batch.InterfaceID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[[]byte](err.Error(), http.StatusForbidden)
+	}
+	rowResults, err := s.rowResultRepo.FindByBatchID(id)
+	if err != nil {
+		return dto.Error[[]byte]("查询批量执行结果失败", http.StatusInternalServerError)
+	}
+	secretParams := s.secretParams(batch.InterfaceID)
+	rows := make([]dto.ApiBatchRowResultDto, 0, len(rowResults))
+	for i := range rowResults {
+		rows = append(rows, s.convertRowResultToDto(&rowResults[i], secretParams))
+	}
+
+	// 数据集列按名称排序后追加在结果列之后
+	columnSet := make(map[string]struct{})
+	for _, row := range rows {
+		for k := range row.Row {
+			columnSet[k] = struct{}{}
+		}
+	}
+	columns := make([]string, 0, len(columnSet))
+	for k := range columnSet {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+
+	var buf bytes.Buffer
+	// 写入 UTF-8 BOM，便于 Excel 正确识别中文
+	buf.WriteString("\xEF\xBB\xBF")
+	writer := csv.NewWriter(&buf)
+	header := append([]string{"rowIndex", "success", "skipped", "status", "responseTime", "recordId", "error"}, columns...)
+	if err := writer.Write(header); err != nil {
+		return dto.Error[[]byte]("导出失败", http.StatusInternalServerError)
+	}
+	for _, row := range rows {
+		recordID, errMsg := "", ""
+		if row.RecordID != nil {
+			recordID = strconv.FormatUint(*row.RecordID, 10)
+		}
+		if row.Error != nil {
+			errMsg = *row.Error
+		}
+		line := []string{
+			strconv.Itoa(row.RowIndex),
+			strconv.FormatBool(row.Success),
+			strconv.FormatBool(row.Skipped),
+			strconv.Itoa(row.Status),
+			strconv.FormatInt(row.ResponseTime, 10),
+			recordID,
+			errMsg,
+		}
+		for _, column := range columns {
+			line = append(line, stringifyDatasetValue(row.Row[column]))
+		}
+		if err := writer.Write(line); err != nil {
+			return dto.Error[[]byte]("导出失败", http.StatusInternalServerError)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return dto.Error[[]byte]("导出失败", http.StatusInternalServerError)
+	}
+	return dto.Success(buf.Bytes())
+}
+
+// run 按并发上限执行所有行，stopOnError 时第一行失败后不再启动新的行
+func (s *ApiBatchExecutionService) run(
+	batch *entity.ApiBatchExecution,
+	apiInterface *entity.ApiInterface,
+	req dto.ApiBatchExecuteRequestDto,
+	executorID uint64,
+	clientIP, userAgent string,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "批量执行异常，批次ID: %d, 错误: %v\n", batch.ID, r)
+		}
+	}()
+
+	secretParams := passwordParamNames(apiInterface)
+	paramTypes := make(map[string]string)
+	for _, param := range parseInterfaceParams(apiInterface) {
+		if param.Name != nil && param.ParamType != nil {
+			paramTypes[*param.Name] = *param.ParamType
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	stopped := false
+	sem := make(chan struct{}, batch.Concurrency)
+
+	// 慢接口执行期间也定期刷新更新时间，避免被判定为已中断
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(batchHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				s.saveProgress(batch)
+				mu.Unlock()
+			}
+		}
+	}()
+
+	for i, row := range req.Rows {
+		sem <- struct{}{}
+
+		mu.Lock()
+		if stopped {
+			mu.Unlock()
+			<-sem
+			s.saveRowResult(batch.ID, dto.ApiBatchRowResultDto{RowIndex: i + 1, Row: row, Skipped: true}, secretParams)
+			continue
+		}
+		mu.Unlock()
+
+		wg.Add(1)
+		go func(index int, row map[string]any) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := s.executeRow(batch, index, row, paramTypes, req, executorID, clientIP, userAgent)
+			s.saveRowResult(batch.ID, result, secretParams)
+
+			mu.Lock()
+			defer mu.Unlock()
+			batch.FinishedRows++
+			if result.Success {
+				batch.SuccessRows++
+			} else {
+				batch.FailedRows++
+				if batch.StopOnError {
+					stopped = true
+				}
+			}
+			s.saveProgress(batch)
+		}(i, row)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	batch.Status = enums.BatchStatusCompleted.Code()
+	if stopped {
+		batch.Status = enums.BatchStatusStopped.Code()
+	}
+	batch.FinishTime = basic.Ptr(time.Now().UnixMilli())
+	s.saveProgress(batch)
+}
+
+// executeRow 将一行数据映射为接口参数并执行
+func (s *ApiBatchExecutionService) executeRow(
+	batch *entity.ApiBatchExecution,
+	index int,
+	row map[string]any,
+	paramTypes map[string]string,
+	req dto.ApiBatchExecuteRequestDto,
+	executorID uint64,
+	clientIP, userAgent string,
+) dto.ApiBatchRowResultDto {
+	executeReq := dto.ApiExecuteRequestDto{
+		InterfaceID:   req.InterfaceID,
+		EnvironmentID: req.EnvironmentID,
+		Headers:       make(map[string]string),
+		URLParams:     make(map[string]any),
+		PathParams:    make(map[string]any),
+		BodyParams:    make(map[string]any),
+		Variables:     make(map[string]string),
+		Remark:        req.Remark,
+		BatchID:       basic.Ptr(batch.ID),
+	}
+	for column, value := range row {
+		switch paramTypes[column] {
+		case enums.ParamTypeURL.Code():
+			executeReq.URLParams[column] = value
+		case enums.ParamTypePath.Code():
+			executeReq.PathParams[column] = value
+		case enums.ParamTypeHeader.Code():
+			executeReq.Headers[column] = stringifyDatasetValue(value)
+		case enums.ParamTypeBody.Code():
+			executeReq.BodyParams[column] = value
+		default:
+			executeReq.Variables[column] = stringifyDatasetValue(value)
+		}
+	}
+
+	result := dto.ApiBatchRowResultDto{RowIndex: index + 1, Row: row}
+	executeResult := s.apiInterfaceService.Execute(executeReq, executorID, clientIP, userAgent)
+	if executeResult.Code != http.StatusOK {
+		result.Error = maskURLsInText(stringPtr(executeResult.Message))
+		return result
+	}
+	result.RecordID = executeResult.Data.RecordID
+	result.Status = executeResult.Data.Status
+	result.Success = executeResult.Data.Success
+	result.ResponseTime = executeResult.Data.ResponseTime
+	result.Error = executeResult.Data.Error
+	return result
+}
+
+// StartRecovery 启动中断批次检测：服务重启后不会继续执行的批次，以及其他副本异常退出遗留的批次，
+// 超过 batchStaleTimeout 未更新时标记为中断
+func (s *ApiBatchExecutionService) StartRecovery() {
+	s.recoveryOnce.Do(func() {
+		go func() {
+			s.recoverInterrupted()
+			ticker := time.NewTicker(batchRecoveryInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				s.recoverInterrupted()
+			}
+		}()
+	})
+}
+
+// recoverInterrupted 将长时间未更新的执行中批次标记为中断
+func (s *ApiBatchExecutionService) recoverInterrupted() {
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "检测中断批量执行异常: %v\n", r)
+		}
+	}()
+
+	now := time.Now()
+	batches, err := s.batchRepo.FindStaleRunning(enums.BatchStatusRunning.Code(), now.Add(-batchStaleTimeout).UnixMilli())
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询中断批量执行失败: %v\n", err)
+		return
+	}
+	for _, batch := range batches {
+		fields := map[string]any{
+			"status":      enums.BatchStatusInterrupted.Code(),
+			"finish_time": now.UnixMilli(),
+			"update_time": now.UnixMilli(),
+		}
+		if err := s.batchRepo.UpdateFields(batch.ID, fields); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "标记批量执行中断失败，批次ID: %d, 错误: %v\n", batch.ID, err)
+			continue
+		}
+		logx.Infof(context.Background(), logx.NameApp, "批量执行已中断，批次ID: %d, 已完成 %d/%d 行", batch.ID, batch.FinishedRows, batch.TotalRows)
+	}
+}
+
+// saveProgress 保存批次的进度与状态并刷新更新时间，调用方需持有锁
+func (s *ApiBatchExecutionService) saveProgress(batch *entity.ApiBatchExecution) {
+	fields := map[string]any{
+		"update_time":   time.Now().UnixMilli(),
+		"status":        batch.Status,
+		"finished_rows": batch.FinishedRows,
+		"success_rows":  batch.SuccessRows,
+		"failed_rows":   batch.FailedRows,
+		"finish_time":   batch.FinishTime,
+	}
+	if err := s.batchRepo.UpdateFields(batch.ID, fields); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新批量执行进度失败，批次ID: %d, 错误: %v\n", batch.ID, err)
+	}
+}
+
+// saveRowResult 保存单行执行结果，行数据脱敏后保存
+func (s *ApiBatchExecutionService) saveRowResult(batchID uint64, result dto.ApiBatchRowResultDto, secretParams map[string]bool) {
+	rowResult := &entity.ApiBatchRowResult{
+		BatchID:      batchID,
+		RowIndex:     result.RowIndex,
+		RecordID:     result.RecordID,
+		Status:       result.Status,
+		Success:      result.Success,
+		Skipped:      result.Skipped,
+		ResponseTime: result.ResponseTime,
+		Error:        result.Error,
+	}
+	if jsonBytes, err := json.Marshal(s.redactor.RedactDatasetRow(result.Row, secretParams)); err == nil {
+		rowResult.Row = basic.Ptr(string(jsonBytes))
+	}
+	if err := s.rowResultRepo.Create(rowResult); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "保存批量执行结果失败，批次ID: %d, 行号: %d, 错误: %v\n", batchID, result.RowIndex, err)
+	}
+}
+
+// secretParams 查询接口中需要脱敏的参数名，接口不存在时返回空集合
+func (s *ApiBatchExecutionService) secretParams(interfaceID uint64) map[string]bool {
+	apiInterface, err := s.apiInterfaceRepo.FindByID(interfaceID)
+	if err != nil {
+		return make(map[string]bool)
+	}
+	return passwordParamNames(apiInterface)
+}
+
+// convertRowResultToDto 转换单行结果为DTO，按当前规则再次脱敏行数据与错误信息（兼容历史数据）
+func (s *ApiBatchExecutionService) convertRowResultToDto(rowResult *entity.ApiBatchRowResult, secretParams map[string]bool) dto.ApiBatchRowResultDto {
+	result := convertBatchRowResultToDto(rowResult)
+	result.Row = s.redactor.RedactDatasetRow(result.Row, secretParams)
+	result.Error = redactExecutionError(result.Status, result.Error)
+	return result
+}
+
+// convertToDto 转换实体为DTO
+func (s *ApiBatchExecutionService) convertToDto(batch *entity.ApiBatchExecution) dto.ApiBatchExecutionDto {
+	var progress float64
+	if batch.TotalRows > 0 {
+		progress = float64(batch.FinishedRows) / float64(batch.TotalRows) * 100
+	}
+	var finishTime *string
+	if batch.FinishTime != nil {
+		finishTime = basic.Ptr(util.Format(batch.FinishTime))
+	}
+	return dto.ApiBatchExecutionDto{
+		ID:            batch.ID,
+		InterfaceID:   batch.InterfaceID,
+		EnvironmentID: batch.EnvironmentID,
+		ExecutorID:    batch.ExecutorID,
+		ExecutorName:  batch.ExecutorName,
+		FileName:      batch.FileName,
+		Concurrency:   batch.Concurrency,
+		StopOnError:   batch.StopOnError,
+		Status:        batch.Status,
+		TotalRows:     batch.TotalRows,
+		FinishedRows:  batch.FinishedRows,
+		SuccessRows:   batch.SuccessRows,
+		FailedRows:    batch.FailedRows,
+		Progress:      progress,
+		CreateTime:    util.Format(&batch.CreateTime),
+		FinishTime:    finishTime,
+	}
+}
+
+// ParseBatchDataset 解析上传的数据集文件
+// .json 文件需为对象数组；其他文件按CSV解析，首行为列名
+func ParseBatchDataset(fileName string, content []byte) ([]map[string]any, error) {
+	content = bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF"))
+	if strings.HasSuffix(strings.ToLower(fileName), ".json") {
+		var rows []map[string]any
+		if err := json.Unmarshal(content, &rows); err != nil {
+			return nil, fmt.Errorf("JSON数据集必须为对象数组: %v", err)
+		}
+		return rows, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("CSV数据集缺少表头: %v", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	rows := make([]map[string]any, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV数据集解析失败: %v", err)
+		}
+		row := make(map[string]any, len(header))
+		for i, column := range header {
+			if column == "" || i >= len(record) {
+				continue
+			}
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// convertBatchRowResultToDto 转换单行结果为DTO
+func convertBatchRowResultToDto(rowResult *entity.ApiBatchRowResult) dto.ApiBatchRowResultDto {
+	result := dto.ApiBatchRowResultDto{
+		RowIndex:     rowResult.RowIndex,
+		RecordID:     rowResult.RecordID,
+		Status:       rowResult.Status,
+		Success:      rowResult.Success,
+		Skipped:      rowResult.Skipped,
+		ResponseTime: rowResult.ResponseTime,
+		Error:        rowResult.Error,
+	}
+	if rowResult.Row != nil {
+		if err := json.Unmarshal([]byte(*rowResult.Row), &result.Row); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "解析批量执行行数据失败，批次ID: %d, 行号: %d, 错误: %v\n", rowResult.BatchID, rowResult.RowIndex, err)
+		}
+	}
+	return result
+}
+
+// stringifyDatasetValue 将数据集中的值转换为字符串
+func stringifyDatasetValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return formatJSONValue(v)
+	}
+}
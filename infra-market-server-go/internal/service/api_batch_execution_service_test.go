@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/entity"
+)
+
+func TestConvertRowResultRedactsRowAndError(t *testing.T) {
+	s := &ApiBatchExecutionService{redactor: &SecretRedactor{headers: map[string]bool{"authorization": true}}}
+	secretParams := map[string]bool{"password": true}
+	tests := []struct {
+		name      string
+		rowResult entity.ApiBatchRowResult
+		wantError string
+	}{
+		{"早期记录的失败响应体", entity.ApiBatchRowResult{Status: 500, Error: stringPtr(`{"token":"secret-token"}`)}, "HTTP 500"},
+		{"请求失败中的地址", entity.ApiBatchRowResult{Error: stringPtr(`Get "https://a/b?api_key=secret-key": timeout`)}, `Get "https://a/b?api_key=******": timeout`},
+		{"成功行", entity.ApiBatchRowResult{Status: 200, Success: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rowResult.Row = stringPtr(`{"name":"alice","password":"p","Authorization":"Bearer t"}`)
+			got := s.convertRowResultToDto(&tt.rowResult, secretParams)
+			if stringValue(got.Error) != tt.wantError {
+				t.Errorf("错误信息 = %q, want %q", stringValue(got.Error), tt.wantError)
+			}
+			if got.Row["name"] != "alice" || got.Row["password"] != redactionMask || got.Row["Authorization"] != redactionMask {
+				t.Errorf("行数据 = %v", got.Row)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+)
+
+// ApiEnvironmentService 接口执行环境管理
+// 规则：环境被所有接口共用，修改基础地址会把请求与认证信息发往新的地址，新增、修改与删除环境需要管理权限
+type ApiEnvironmentService struct {
+	environmentRepo    *repository.ApiEnvironmentRepository
+	apiInterfaceRepo   *repository.ApiInterfaceRepository
+	authProfileService *ApiAuthProfileService
+	transportService   *ApiTransportService
+	authService        *AuthService
+}
+
+func NewApiEnvironmentService(
+	environmentRepo *repository.ApiEnvironmentRepository,
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	authProfileService *ApiAuthProfileService,
+	transportService *ApiTransportService,
+	authService *AuthService,
+) *ApiEnvironmentService {
+	return &ApiEnvironmentService{
+		environmentRepo:    environmentRepo,
+		apiInterfaceRepo:   apiInterfaceRepo,
+		authProfileService: authProfileService,
+		transportService:   transportService,
+		authService:        authService,
+	}
+}
+
+// FindPage 分页查询环境
+func (s *ApiEnvironmentService) FindPage(query dto.ApiEnvironmentQueryDto) dto.ApiData[dto.PageResult[dto.ApiEnvironmentDto]] {
+	environments, total, err := s.environmentRepo.Page(query)
+	return PageResultBuilder(environments, total, err, s.convertToDto, &query)
+}
+
+// FindAllEnabled 查询所有启用的环境
+func (s *ApiEnvironmentService) FindAllEnabled() dto.ApiData[[]dto.ApiEnvironmentDto] {
+	environments, err := s.environmentRepo.ListEnabled()
+	if err != nil {
+		return dto.Error[[]dto.ApiEnvironmentDto]("查询失败", http.StatusInternalServerError)
+	}
+
+	result := make([]dto.ApiEnvironmentDto, len(environments))
+	for i := range environments {
+		result[i] = s.convertToDto(&environments[i])
+	}
+	return dto.Success(result)
+}
+
+// FindByID 根据ID查询
+func (s *ApiEnvironmentService) FindByID(id uint64) dto.ApiData[dto.ApiEnvironmentDto] {
+	environment, err := s.environmentRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiEnvironmentDto]("环境不存在", http.StatusNotFound)
+	}
+	return dto.Success(s.convertToDto(environment))
+}
+
+// Save 创建环境，需要管理权限，uid 为操作人
+func (s *ApiEnvironmentService) Save(form dto.ApiEnvironmentFormDto, uid uint64) dto.ApiData[dto.ApiEnvironmentDto] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusForbidden)
+	}
+	if _, err := s.environmentRepo.FindByCode(form.Code); err == nil {
+		return dto.Error[dto.ApiEnvironmentDto]("环境编码已存在", http.StatusBadRequest)
+	}
+	if err := s.authProfileService.ValidateReference(form.AuthProfileID, nil, uid); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusBadRequest)
+	}
+	if err := s.transportService.CheckPermission(form.Transport, uid); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusForbidden)
+	}
+
+	environment := &entity.ApiEnvironment{Status: 1}
+	if err := s.applyForm(environment, &form); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusBadRequest)
+	}
+
+	if err := s.environmentRepo.Create(environment); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "创建环境失败，环境编码: %s, 错误: %v\n", form.Code, err)
+		return dto.Error[dto.ApiEnvironmentDto]("创建环境失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(environment))
+}
+
+// Update 更新环境，需要管理权限，uid 为操作人
+func (s *ApiEnvironmentService) Update(id uint64, form dto.ApiEnvironmentFormDto, uid uint64) dto.ApiData[dto.ApiEnvironmentDto] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusForbidden)
+	}
+	environment, err := s.environmentRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiEnvironmentDto]("环境不存在", http.StatusNotFound)
+	}
+
+	if existing, err := s.environmentRepo.FindByCode(form.Code); err == nil && existing.ID != id {
+		return dto.Error[dto.ApiEnvironmentDto]("环境编码已存在", http.StatusBadRequest)
+	}
+	if err := s.authProfileService.ValidateReference(form.AuthProfileID, environment.AuthProfileID, uid); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusBadRequest)
+	}
+	if err := s.transportService.CheckPermission(form.Transport, uid); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusForbidden)
+	}
+
+	if err := s.applyForm(environment, &form); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusBadRequest)
+	}
+
+	if err := s.environmentRepo.Update(environment); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新环境失败，环境ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiEnvironmentDto]("更新环境失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(environment))
+}
+
+// Delete 删除环境，需要管理权限
+func (s *ApiEnvironmentService) Delete(id uint64, uid uint64) dto.ApiData[any] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[any](err.Error(), http.StatusForbidden)
+	}
+	if _, err := s.environmentRepo.FindByID(id); err != nil {
+		return dto.Error[any]("环境不存在", http.StatusNotFound)
+	}
+
+	// 被接口引用为默认环境时不允许删除
+	count, err := s.apiInterfaceRepo.CountByEnvironmentID(id)
+	if err != nil {
+		return dto.Error[any]("删除环境失败", http.StatusInternalServerError)
+	}
+	if count > 0 {
+		return dto.Error[any]("该环境已被接口引用，无法删除", http.StatusBadRequest)
+	}
+
+	if err := s.environmentRepo.Delete(id); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "删除环境失败，环境ID: %d, 错误: %v\n", id, err)
+		return dto.Error[any]("删除环境失败", http.StatusInternalServerError)
+	}
+	return dto.Success[any](nil)
+}
+
+// UpdateStatus 更新环境状态，需要管理权限
+func (s *ApiEnvironmentService) UpdateStatus(id uint64, status int, uid uint64) dto.ApiData[dto.ApiEnvironmentDto] {
+	if err := s.checkManagePermission(uid); err != nil {
+		return dto.Error[dto.ApiEnvironmentDto](err.Error(), http.StatusForbidden)
+	}
+	environment, err := s.environmentRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiEnvironmentDto]("环境不存在", http.StatusNotFound)
+	}
+
+	environment.Status = status
+	if err := s.environmentRepo.Update(environment); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新环境状态失败，环境ID: %d, 状态: %d, 错误: %v\n", id, status, err)
+		return dto.Error[dto.ApiEnvironmentDto]("更新状态失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(environment))
+}
+
+// checkManagePermission 校验用户是否可以管理执行环境
+func (s *ApiEnvironmentService) checkManagePermission(uid uint64) error {
+	if uid == 0 || !s.authService.HasPermission(uid, enums.EnvironmentManagePermissionCode) {
+		return fmt.Errorf("无权管理执行环境")
+	}
+	return nil
+}
+
+// applyForm 校验表单并写入实体
+func (s *ApiEnvironmentService) applyForm(environment *entity.ApiEnvironment, form *dto.ApiEnvironmentFormDto) error {
+	transportConfig, transportSecret, err := s.transportService.applyForm(form.Transport, environment.TransportConfig, environment.TransportSecret)
+	if err != nil {
+		return err
+	}
+	environment.TransportConfig = transportConfig
+	environment.TransportSecret = transportSecret
+	environment.Name = form.Name
+	environment.Code = form.Code
+	environment.BaseURL = form.BaseURL
+	environment.AuthProfileID = form.AuthProfileID
+	environment.Description = form.Description
+	environment.Variables = nil
+	if len(form.Variables) > 0 {
+		if jsonBytes, err := json.Marshal(form.Variables); err == nil {
+			environment.Variables = basic.Ptr(string(jsonBytes))
+		}
+	}
+	if form.Sort != nil {
+		environment.Sort = *form.Sort
+	}
+	if form.Status != nil {
+		environment.Status = *form.Status
+	}
+	return nil
+}
+
+// convertToDto 转换实体为DTO
+func (s *ApiEnvironmentService) convertToDto(environment *entity.ApiEnvironment) dto.ApiEnvironmentDto {
+	return dto.ApiEnvironmentDto{
+		ID:            environment.ID,
+		Name:          environment.Name,
+		Code:          environment.Code,
+		BaseURL:       environment.BaseURL,
+		Variables:     parseEnvironmentVariables(environment),
+		AuthProfileID: environment.AuthProfileID,
+		Description:   environment.Description,
+		Sort:          environment.Sort,
+		Status:        environment.Status,
+		Transport:     convertTransportToDto(environment.TransportConfig, environment.TransportSecret),
+		CreateTime:    util.Format(&environment.CreateTime),
+		UpdateTime:    util.Format(&environment.UpdateTime),
+	}
+}
+
+// parseEnvironmentVariables 解析环境变量JSON
+func parseEnvironmentVariables(environment *entity.ApiEnvironment) map[string]string {
+	variables := make(map[string]string)
+	if environment == nil || environment.Variables == nil || *environment.Variables == "" {
+		return variables
+	}
+	if err := json.Unmarshal([]byte(*environment.Variables), &variables); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析环境变量失败，环境ID: %d, 错误: %v\n", environment.ID, err)
+	}
+	return variables
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+	"gorm.io/gorm"
+)
+
+// ApiInterfaceAclService 接口负责人与授权管理
+// 规则：接口没有任何授权记录时所有人都可以查看、执行与编辑，只有管理员与接口创建人可以设置授权；
+// 设置授权后只有管理员、负责人与被授权的用户或角色可以访问，且至少保留一个负责人；
+// 权限按 查看 < 执行 < 编辑 < 负责人 逐级包含，用户同时通过自身与角色获得授权时取最高权限
+type ApiInterfaceAclService struct {
+	db               *gorm.DB
+	aclRepo          *repository.ApiInterfaceAclRepository
+	apiInterfaceRepo *repository.ApiInterfaceRepository
+	versionRepo      *repository.ApiInterfaceVersionRepository
+	userRepo         *repository.UserRepository
+	roleRepo         *repository.RoleRepository
+	authService      *AuthService
+}
+
+// interfaceCreateActions 可以确定接口创建人的版本操作
+var interfaceCreateActions = []string{enums.InterfaceVersionActionCreate.Code(), enums.InterfaceVersionActionCopy.Code()}
+
+func NewApiInterfaceAclService(
+	db *gorm.DB,
+	aclRepo *repository.ApiInterfaceAclRepository,
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	versionRepo *repository.ApiInterfaceVersionRepository,
+	userRepo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	authService *AuthService,
+) *ApiInterfaceAclService {
+	return &ApiInterfaceAclService{
+		db:               db,
+		aclRepo:          aclRepo,
+		apiInterfaceRepo: apiInterfaceRepo,
+		versionRepo:      versionRepo,
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		authService:      authService,
+	}
+}
+
+// interfaceViewer 访问接口的用户及其角色，管理员不受授权限制
+type interfaceViewer struct {
+	uid     uint64
+	roleIDs []uint64
+	admin   bool
+}
+
+// List 查询接口的授权，需要查看权限
+func (s *ApiInterfaceAclService) List(interfaceID uint64, uid uint64) dto.ApiData[[]dto.ApiInterfaceAclDto] {
+	if _, err := s.apiInterfaceRepo.FindByID(interfaceID); err != nil {
+		return dto.Error[[]dto.ApiInterfaceAclDto]("接口不存在", http.StatusNotFound)
+	}
+	if err := s.CheckPermission(interfaceID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[[]dto.ApiInterfaceAclDto](err.Error(), http.StatusForbidden)
+	}
+
+	acls, err := s.aclRepo.FindByInterfaceID(interfaceID)
+	if err != nil {
+		return dto.Error[[]dto.ApiInterfaceAclDto]("查询接口授权失败", http.StatusInternalServerError)
+	}
+	return dto.Success(s.convertToDtos(acls))
+}
+
+// Save 整体替换接口的授权，需要负责人权限，接口还没有授权时需要是管理员或接口创建人；至少需要一个负责人
+func (s *ApiInterfaceAclService) Save(interfaceID uint64, form dto.ApiInterfaceAclFormDto, uid uint64) dto.ApiData[[]dto.ApiInterfaceAclDto] {
+	if _, err := s.apiInterfaceRepo.FindByID(interfaceID); err != nil {
+		return dto.Error[[]dto.ApiInterfaceAclDto]("接口不存在", http.StatusNotFound)
+	}
+	existing, err := s.aclRepo.FindByInterfaceID(interfaceID)
+	if err != nil {
+		return dto.Error[[]dto.ApiInterfaceAclDto]("查询接口授权失败", http.StatusInternalServerError)
+	}
+	if len(existing) == 0 {
+		if !s.authService.IsAdmin(uid) && !s.isCreator(interfaceID, uid) {
+			return dto.Error[[]dto.ApiInterfaceAclDto]("接口还没有负责人，只有管理员或接口创建人可以设置授权", http.StatusForbidden)
+		}
+	} else if err := s.CheckPermission(interfaceID, uid, enums.InterfacePermissionOwner); err != nil {
+		return dto.Error[[]dto.ApiInterfaceAclDto](err.Error(), http.StatusForbidden)
+	}
+
+	acls, err := s.validateEntries(interfaceID, form.Entries)
+	if err != nil {
+		return dto.Error[[]dto.ApiInterfaceAclDto](err.Error(), http.StatusBadRequest)
+	}
+
+	err = WithTransaction(s.db, func(tx *gorm.DB) error {
+		txAclRepo := repository.NewApiInterfaceAclRepository(tx)
+		if err := txAclRepo.DeleteByInterfaceIDs([]uint64{interfaceID}); err != nil {
+			return err
+		}
+		for i := range acls {
+			if err := txAclRepo.Create(&acls[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "保存接口授权失败，接口ID: %d, 错误: %v\n", interfaceID, err)
+		return dto.Error[[]dto.ApiInterfaceAclDto]("保存接口授权失败", http.StatusInternalServerError)
+	}
+	return dto.Success(s.convertToDtos(acls))
+}
+
+// CheckPermission 校验用户对接口拥有指定权限
+func (s *ApiInterfaceAclService) CheckPermission(interfaceID uint64, uid uint64, required enums.InterfacePermission) error {
+	acls, err := s.aclRepo.FindByInterfaceID(interfaceID)
+	if err != nil {
+		return fmt.Errorf("查询接口授权失败")
+	}
+	permission := resolveInterfacePermission(acls, s.viewer(uid))
+	if permission != nil && permission.Includes(required) {
+		return nil
+	}
+
+	actions := map[enums.InterfacePermission]string{
+		enums.InterfacePermissionView:    "查看",
+		enums.InterfacePermissionExecute: "执行",
+		enums.InterfacePermissionEdit:    "修改",
+		enums.InterfacePermissionOwner:   "管理授权",
+	}
+	return fmt.Errorf("无权%s该接口", actions[required])
+}
+
+// ApplyVisibility 为接口查询设置可见范围，管理员可以查看全部接口
+func (s *ApiInterfaceAclService) ApplyVisibility(query *dto.ApiInterfaceQueryDto, uid uint64) {
+	viewer := s.viewer(uid)
+	if viewer.admin {
+		return
+	}
+	query.ViewerUID = basic.Ptr(viewer.uid)
+	query.ViewerRoleIDs = viewer.roleIDs
+}
+
+// ApplyRecordVisibility 为执行记录查询设置可见范围，只返回可见接口的执行记录，管理员可以查看全部记录
+func (s *ApiInterfaceAclService) ApplyRecordVisibility(query *dto.ApiInterfaceExecutionRecordQueryDto, uid uint64) {
+	viewer := s.viewer(uid)
+	if viewer.admin {
+		return
+	}
+	query.ViewerUID = basic.Ptr(viewer.uid)
+	query.ViewerRoleIDs = viewer.roleIDs
+}
+
+// ApplyRunVisibility 为工作流执行记录查询设置可见范围，只返回各步骤接口均可见的记录，管理员可以查看全部记录
+func (s *ApiInterfaceAclService) ApplyRunVisibility(query *dto.ApiWorkflowRunQueryDto, uid uint64) {
+	viewer := s.viewer(uid)
+	if viewer.admin {
+		return
+	}
+	query.ViewerUID = basic.Ptr(viewer.uid)
+	query.ViewerRoleIDs = viewer.roleIDs
+}
+
+// ApplyBatchVisibility 为批量执行查询设置可见范围，只返回可见接口的批次，管理员可以查看全部批次
+func (s *ApiInterfaceAclService) ApplyBatchVisibility(query *dto.ApiBatchExecutionQueryDto, uid uint64) {
+	viewer := s.viewer(uid)
+	if viewer.admin {
+		return
+	}
+	query.ViewerUID = basic.Ptr(viewer.uid)
+	query.ViewerRoleIDs = viewer.roleIDs
+}
+
+// ApplyScheduleVisibility 为定时计划查询设置可见范围，只返回可以执行的接口的计划，管理员可以查看全部计划
+func (s *ApiInterfaceAclService) ApplyScheduleVisibility(query *dto.ApiScheduleQueryDto, uid uint64) {
+	viewer := s.viewer(uid)
+	if viewer.admin {
+		return
+	}
+	query.ViewerUID = basic.Ptr(viewer.uid)
+	query.ViewerRoleIDs = viewer.roleIDs
+}
+
+// FillAccess 批量填充接口的负责人与当前用户的权限，返回当前用户可见的接口
+func (s *ApiInterfaceAclService) FillAccess(interfaces []dto.ApiInterfaceDto, uid uint64) []dto.ApiInterfaceDto {
+	if len(interfaces) == 0 {
+		return interfaces
+	}
+	ids := make([]uint64, 0, len(interfaces))
+	for _, interfaceDto := range interfaces {
+		ids = append(ids, *interfaceDto.ID)
+	}
+	acls, err := s.aclRepo.FindByInterfaceIDs(ids)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询接口授权失败: %v\n", err)
+		return []dto.ApiInterfaceDto{}
+	}
+	aclsByInterface := make(map[uint64][]entity.ApiInterfaceAcl)
+	ownerIDs := make([]uint64, 0)
+	for _, acl := range acls {
+		aclsByInterface[acl.InterfaceID] = append(aclsByInterface[acl.InterfaceID], acl)
+		if acl.Permission == enums.InterfacePermissionOwner.Code() {
+			ownerIDs = append(ownerIDs, acl.SubjectID)
+		}
+	}
+	userNames := s.userNames(ownerIDs)
+
+	viewer := s.viewer(uid)
+	result := make([]dto.ApiInterfaceDto, 0, len(interfaces))
+	for _, interfaceDto := range interfaces {
+		interfaceAcls := aclsByInterface[*interfaceDto.ID]
+		permission := resolveInterfacePermission(interfaceAcls, viewer)
+		if permission == nil {
+			continue
+		}
+		interfaceDto.Permission = basic.Ptr(permission.Code())
+		interfaceDto.Owners = []string{}
+		for _, acl := range interfaceAcls {
+			if acl.Permission == enums.InterfacePermissionOwner.Code() {
+				if name, ok := userNames[acl.SubjectID]; ok {
+					interfaceDto.Owners = append(interfaceDto.Owners, name)
+				}
+			}
+		}
+		result = append(result, interfaceDto)
+	}
+	return result
+}
+
+// validateEntries 校验授权项并转换为实体
+func (s *ApiInterfaceAclService) validateEntries(interfaceID uint64, entries []dto.ApiInterfaceAclEntryDto) ([]entity.ApiInterfaceAcl, error) {
+	acls := make([]entity.ApiInterfaceAcl, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	userIDs := make([]uint64, 0)
+	roleIDs := make([]uint64, 0)
+	hasOwner := false
+
+	for _, entry := range entries {
+		subjectType := enums.AclSubjectTypeFromCode(strings.ToUpper(*entry.SubjectType))
+		if subjectType == nil {
+			return nil, fmt.Errorf("不支持的授权对象类型: %s", *entry.SubjectType)
+		}
+		permission := enums.InterfacePermissionFromCode(strings.ToUpper(*entry.Permission))
+		if permission == nil {
+			return nil, fmt.Errorf("不支持的接口权限: %s", *entry.Permission)
+		}
+		if *permission == enums.InterfacePermissionOwner {
+			if *subjectType != enums.AclSubjectUser {
+				return nil, fmt.Errorf("负责人只能是用户")
+			}
+			hasOwner = true
+		}
+
+		key := fmt.Sprintf("%s:%d", subjectType.Code(), *entry.SubjectID)
+		if seen[key] {
+			return nil, fmt.Errorf("授权对象重复: %s", key)
+		}
+		seen[key] = true
+		if *subjectType == enums.AclSubjectUser {
+			userIDs = append(userIDs, *entry.SubjectID)
+		} else {
+			roleIDs = append(roleIDs, *entry.SubjectID)
+		}
+
+		acls = append(acls, entity.ApiInterfaceAcl{
+			InterfaceID: interfaceID,
+			SubjectType: subjectType.Code(),
+			SubjectID:   *entry.SubjectID,
+			Permission:  permission.Code(),
+		})
+	}
+	if !hasOwner {
+		return nil, fmt.Errorf("设置授权时至少需要一个负责人")
+	}
+
+	if users, err := s.userRepo.FindByUIDs(userIDs); err != nil || len(users) != len(userIDs) {
+		return nil, fmt.Errorf("授权的用户不存在")
+	}
+	if roles, err := s.roleRepo.FindByIDs(roleIDs); err != nil || len(roles) != len(roleIDs) {
+		return nil, fmt.Errorf("授权的角色不存在")
+	}
+	return acls, nil
+}
+
+// BackfillOwners 为没有任何授权记录的已有接口补充负责人：能确定创建人且创建人未删除时设为负责人，
+// 其余接口保持所有人可访问，由管理员设置授权。重复执行时只处理仍没有授权记录的接口
+func (s *ApiInterfaceAclService) BackfillOwners() error {
+	interfaceIDs, err := s.aclRepo.FindInterfaceIDsWithoutAcl()
+	if err != nil {
+		return fmt.Errorf("查询没有授权的接口失败: %w", err)
+	}
+	filled := 0
+	for _, interfaceID := range interfaceIDs {
+		creatorID, err := s.versionRepo.FindCreatorID(interfaceID, interfaceCreateActions)
+		if err != nil {
+			return fmt.Errorf("查询接口创建人失败，接口ID: %d: %w", interfaceID, err)
+		}
+		if creatorID == nil || *creatorID == SystemExecutorID {
+			continue
+		}
+		if users, err := s.userRepo.FindByUIDs([]uint64{*creatorID}); err != nil || len(users) == 0 {
+			continue
+		}
+		if err := addInterfaceOwner(s.db, interfaceID, *creatorID); err != nil {
+			return fmt.Errorf("补充接口负责人失败，接口ID: %d: %w", interfaceID, err)
+		}
+		filled++
+	}
+	if filled > 0 {
+		logx.Infof(context.Background(), logx.NameApp, "已为 %d 个接口补充负责人", filled)
+	}
+	return nil
+}
+
+// isCreator 判断用户是否为接口创建人
+func (s *ApiInterfaceAclService) isCreator(interfaceID uint64, uid uint64) bool {
+	creatorID, err := s.versionRepo.FindCreatorID(interfaceID, interfaceCreateActions)
+	return err == nil && creatorID != nil && *creatorID == uid && uid != SystemExecutorID
+}
+
+// viewer 获取用户及其角色
+func (s *ApiInterfaceAclService) viewer(uid uint64) interfaceViewer {
+	return interfaceViewer{
+		uid:     uid,
+		roleIDs: s.authService.GetRoleIDs(uid),
+		admin:   s.authService.IsAdmin(uid),
+	}
+}
+
+// userNames 批量获取用户名
+func (s *ApiInterfaceAclService) userNames(uids []uint64) map[uint64]string {
+	names := make(map[uint64]string, len(uids))
+	if len(uids) == 0 {
+		return names
+	}
+	users, err := s.userRepo.FindByUIDs(uids)
+	if err != nil {
+		return names
+	}
+	for _, user := range users {
+		names[user.ID] = user.Username
+	}
+	return names
+}
+
+// convertToDtos 转换授权为DTO并填充授权对象名称
+func (s *ApiInterfaceAclService) convertToDtos(acls []entity.ApiInterfaceAcl) []dto.ApiInterfaceAclDto {
+	userIDs := make([]uint64, 0)
+	roleIDs := make([]uint64, 0)
+	for _, acl := range acls {
+		if acl.SubjectType == enums.AclSubjectUser.Code() {
+			userIDs = append(userIDs, acl.SubjectID)
+		} else {
+			roleIDs = append(roleIDs, acl.SubjectID)
+		}
+	}
+	userNames := s.userNames(userIDs)
+	roleNames := make(map[uint64]string, len(roleIDs))
+	if roles, err := s.roleRepo.FindByIDs(roleIDs); err == nil {
+		for _, role := range roles {
+			roleNames[role.ID] = role.Name
+		}
+	}
+
+	result := make([]dto.ApiInterfaceAclDto, 0, len(acls))
+	for _, acl := range acls {
+		aclDto := dto.ApiInterfaceAclDto{
+			SubjectType: acl.SubjectType,
+			SubjectID:   acl.SubjectID,
+			Permission:  acl.Permission,
+			CreateTime:  util.Format(&acl.CreateTime),
+		}
+		names := roleNames
+		if acl.SubjectType == enums.AclSubjectUser.Code() {
+			names = userNames
+		}
+		if name, ok := names[acl.SubjectID]; ok {
+			aclDto.SubjectName = basic.Ptr(name)
+		}
+		result = append(result, aclDto)
+	}
+	return result
+}
+
+// resolveInterfacePermission 计算用户对接口的权限，acls 为接口的全部授权，无权访问时返回空
+// 没有授权记录的接口所有人都有编辑权限，但不能管理授权
+func resolveInterfacePermission(acls []entity.ApiInterfaceAcl, viewer interfaceViewer) *enums.InterfacePermission {
+	if viewer.admin {
+		return basic.Ptr(enums.InterfacePermissionOwner)
+	}
+	if len(acls) == 0 {
+		return basic.Ptr(enums.InterfacePermissionEdit)
+	}
+
+	roles := make(map[uint64]bool, len(viewer.roleIDs))
+	for _, roleID := range viewer.roleIDs {
+		roles[roleID] = true
+	}
+	var result *enums.InterfacePermission
+	for _, acl := range acls {
+		matched := (acl.SubjectType == enums.AclSubjectUser.Code() && acl.SubjectID == viewer.uid) ||
+			(acl.SubjectType == enums.AclSubjectRole.Code() && roles[acl.SubjectID])
+		if !matched {
+			continue
+		}
+		permission := enums.InterfacePermissionFromCode(acl.Permission)
+		if permission != nil && (result == nil || permission.Level() > result.Level()) {
+			result = permission
+		}
+	}
+	return result
+}
+
+// addInterfaceOwner 在事务中将用户设置为接口的负责人
+func addInterfaceOwner(tx *gorm.DB, interfaceID uint64, uid uint64) error {
+	return repository.NewApiInterfaceAclRepository(tx).Create(&entity.ApiInterfaceAcl{
+		InterfaceID: interfaceID,
+		SubjectType: enums.AclSubjectUser.Code(),
+		SubjectID:   uid,
+		Permission:  enums.InterfacePermissionOwner.Code(),
+	})
+}
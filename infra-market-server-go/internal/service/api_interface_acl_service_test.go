@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+func TestResolveInterfacePermission(t *testing.T) {
+	acl := func(subjectType enums.AclSubjectType, subjectID uint64, permission string) entity.ApiInterfaceAcl {
+		return entity.ApiInterfaceAcl{SubjectType: subjectType.Code(), SubjectID: subjectID, Permission: permission}
+	}
+	acls := []entity.ApiInterfaceAcl{
+		acl(enums.AclSubjectUser, 1, "OWNER"),
+		acl(enums.AclSubjectUser, 2, "VIEW"),
+		acl(enums.AclSubjectRole, 10, "EXECUTE"),
+		acl(enums.AclSubjectRole, 11, "EDIT"),
+		acl(enums.AclSubjectUser, 3, "UNKNOWN"),
+	}
+	tests := []struct {
+		name   string
+		acls   []entity.ApiInterfaceAcl
+		viewer interfaceViewer
+		want   string // 为空表示没有权限
+	}{
+		{"管理员拥有全部权限", acls, interfaceViewer{uid: 99, admin: true}, "OWNER"},
+		{"未配置授权时可编辑", nil, interfaceViewer{uid: 99}, "EDIT"},
+		{"用户授权", acls, interfaceViewer{uid: 1}, "OWNER"},
+		{"角色授权", acls, interfaceViewer{uid: 99, roleIDs: []uint64{10}}, "EXECUTE"},
+		{"取最高权限", acls, interfaceViewer{uid: 2, roleIDs: []uint64{10, 11}}, "EDIT"},
+		{"用户ID与角色ID不混用", acls, interfaceViewer{uid: 10}, ""},
+		{"未命中授权", acls, interfaceViewer{uid: 99, roleIDs: []uint64{12}}, ""},
+		{"忽略无效权限", acls, interfaceViewer{uid: 3}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveInterfacePermission(tt.acls, tt.viewer)
+			if got == nil {
+				if tt.want != "" {
+					t.Errorf("resolveInterfacePermission() = nil, want %s", tt.want)
+				}
+				return
+			}
+			if got.Code() != tt.want {
+				t.Errorf("resolveInterfacePermission() = %s, want %q", got.Code(), tt.want)
+			}
+		})
+	}
+}
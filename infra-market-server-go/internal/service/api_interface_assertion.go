@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+// validateAssertions 验证响应断言配置
+func validateAssertions(assertions []dto.ApiAssertionDto) error {
+	for i, assertion := range assertions {
+		index := i + 1
+		if assertion.Type == nil || enums.AssertionTypeFromCode(*assertion.Type) == nil {
+			return fmt.Errorf("第%d条断言的类型不正确", index)
+		}
+		assertionType := enums.AssertionType(*assertion.Type)
+		operator := assertionOperator(assertion)
+		if enums.AssertionOperatorFromCode(operator.Code()) == nil {
+			return fmt.Errorf("第%d条断言的比较方式不正确", index)
+		}
+		expected := ""
+		if assertion.Expected != nil {
+			expected = *assertion.Expected
+		}
+
+		switch assertionType {
+		case enums.AssertionTypeStatusCode:
+			if operator == enums.AssertionOperatorRange {
+				if _, _, err := parseStatusRange(expected); err != nil {
+					return fmt.Errorf("第%d条断言的状态码区间不正确: %s", index, expected)
+				}
+			} else if operator == enums.AssertionOperatorEquals {
+				if _, err := strconv.Atoi(strings.TrimSpace(expected)); err != nil {
+					return fmt.Errorf("第%d条断言的状态码不正确: %s", index, expected)
+				}
+			} else {
+				return fmt.Errorf("第%d条断言：状态码仅支持等于或区间比较", index)
+			}
+		case enums.AssertionTypeResponseTime:
+			if maxTime, err := strconv.ParseInt(strings.TrimSpace(expected), 10, 64); err != nil || maxTime <= 0 {
+				return fmt.Errorf("第%d条断言的最大响应时间不正确: %s", index, expected)
+			}
+		case enums.AssertionTypeJSONPath, enums.AssertionTypeHeader:
+			if assertion.Target == nil || strings.TrimSpace(*assertion.Target) == "" {
+				return fmt.Errorf("第%d条断言缺少断言目标", index)
+			}
+			if operator == enums.AssertionOperatorRange {
+				return fmt.Errorf("第%d条断言：区间比较仅支持状态码", index)
+			}
+			if operator == enums.AssertionOperatorRegex {
+				if _, err := regexp.Compile(expected); err != nil {
+					return fmt.Errorf("第%d条断言的正则表达式不正确: %v", index, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// evaluateAssertions 对响应执行断言，返回每条断言的结果
+func evaluateAssertions(assertions []dto.ApiAssertionDto, response *dto.ApiExecuteResponseDto) []dto.ApiAssertionResultDto {
+	if len(assertions) == 0 {
+		return nil
+	}
+
+	// 响应体只解析一次，供所有JSONPath断言共用
+	var bodyData any
+	var bodyErr error
+	bodyParsed := false
+
+	results := make([]dto.ApiAssertionResultDto, 0, len(assertions))
+	for _, assertion := range assertions {
+		result := dto.ApiAssertionResultDto{
+			Type:     assertion.Type,
+			Target:   assertion.Target,
+			Operator: assertion.Operator,
+			Expected: assertion.Expected,
+		}
+		expected := ""
+		if assertion.Expected != nil {
+			expected = *assertion.Expected
+		}
+		operator := assertionOperator(assertion)
+
+		switch enums.AssertionType(*assertion.Type) {
+		case enums.AssertionTypeStatusCode:
+			actual := strconv.Itoa(response.Status)
+			result.Actual = &actual
+			if operator == enums.AssertionOperatorRange {
+				min, max, _ := parseStatusRange(expected)
+				result.Passed = response.Status >= min && response.Status <= max
+			} else {
+				result.Passed = actual == strings.TrimSpace(expected)
+			}
+		case enums.AssertionTypeResponseTime:
+			actual := strconv.FormatInt(response.ResponseTime, 10)
+			result.Actual = &actual
+			maxTime, _ := strconv.ParseInt(strings.TrimSpace(expected), 10, 64)
+			result.Passed = response.ResponseTime <= maxTime
+		case enums.AssertionTypeHeader:
+			value, exists := findResponseHeader(response.Headers, *assertion.Target)
+			if exists {
+				result.Actual = &value
+			}
+			result.Passed = compareAssertionValue(operator, exists, value, nil, expected)
+		case enums.AssertionTypeJSONPath:
+			if !bodyParsed {
+				bodyParsed = true
+				if response.Body == nil {
+					bodyErr = fmt.Errorf("响应体为空")
+				} else {
+					bodyErr = json.Unmarshal([]byte(*response.Body), &bodyData)
+				}
+			}
+			if bodyErr != nil {
+				result.Message = stringPtr("响应体不是有效的JSON")
+				break
+			}
+			value, err := jsonpath.Get(*assertion.Target, bodyData)
+			exists := err == nil
+			actual := ""
+			if exists {
+				actual = formatJSONValue(value)
+				result.Actual = &actual
+			}
+			result.Passed = compareAssertionValue(operator, exists, actual, value, expected)
+		}
+
+		if !result.Passed && result.Message == nil {
+			result.Message = stringPtr(assertionFailureMessage(result))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// applyAssertionResults 根据断言结果更新执行成功标识
+// 配置了状态码断言时以断言结果为准，否则在请求本身成功的基础上叠加断言结果
+func applyAssertionResults(response *dto.ApiExecuteResponseDto, assertions []dto.ApiAssertionDto, results []dto.ApiAssertionResultDto) {
+	if len(results) == 0 {
+		return
+	}
+	response.AssertionResults = results
+
+	passed := true
+	failures := make([]string, 0)
+	for _, result := range results {
+		if !result.Passed {
+			passed = false
+			failures = append(failures, *result.Message)
+		}
+	}
+
+	hasStatusAssertion := false
+	for _, assertion := range assertions {
+		if *assertion.Type == enums.AssertionTypeStatusCode.Code() {
+			hasStatusAssertion = true
+			break
+		}
+	}
+
+	if hasStatusAssertion {
+		response.Success = passed
+		if passed {
+			response.Error = nil
+		}
+	} else {
+		response.Success = response.Success && passed
+	}
+	if !passed {
+		response.Error = stringPtr("断言失败: " + strings.Join(failures, "; "))
+	}
+}
+
+// parseInterfaceAssertions 解析接口的响应断言配置
+func parseInterfaceAssertions(apiInterface *entity.ApiInterface) []dto.ApiAssertionDto {
+	if apiInterface.Assertions == nil || *apiInterface.Assertions == "" {
+		return nil
+	}
+	var assertions []dto.ApiAssertionDto
+	if err := json.Unmarshal([]byte(*apiInterface.Assertions), &assertions); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析接口断言失败: %v\n", err)
+		return nil
+	}
+	return assertions
+}
+
+// assertionOperator 获取断言的比较方式，状态码和响应时间以外的断言默认为等于
+func assertionOperator(assertion dto.ApiAssertionDto) enums.AssertionOperator {
+	if assertion.Operator != nil && *assertion.Operator != "" {
+		return enums.AssertionOperator(*assertion.Operator)
+	}
+	return enums.AssertionOperatorEquals
+}
+
+// compareAssertionValue 按比较方式判断实际值是否满足期望值
+// raw 为JSONPath取到的原始值，数组类型的包含判断按元素匹配
+func compareAssertionValue(operator enums.AssertionOperator, exists bool, actual string, raw any, expected string) bool {
+	if operator == enums.AssertionOperatorExists {
+		return exists
+	}
+	if !exists {
+		return false
+	}
+	switch operator {
+	case enums.AssertionOperatorEquals:
+		return actual == expected
+	case enums.AssertionOperatorContains:
+		if items, ok := raw.([]any); ok {
+			for _, item := range items {
+				if formatJSONValue(item) == expected {
+					return true
+				}
+			}
+			return false
+		}
+		return strings.Contains(actual, expected)
+	case enums.AssertionOperatorRegex:
+		re, err := regexp.Compile(expected)
+		return err == nil && re.MatchString(actual)
+	}
+	return false
+}
+
+// findResponseHeader 不区分大小写查找响应头
+func findResponseHeader(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// parseStatusRange 解析状态码区间，格式为 200-299
+func parseStatusRange(expected string) (int, int, error) {
+	parts := strings.SplitN(expected, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid range")
+	}
+	min, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, err
+	}
+	if min > max {
+		return 0, 0, fmt.Errorf("invalid range")
+	}
+	return min, max, nil
+}
+
+// assertionFailureMessage 生成断言失败描述
+func assertionFailureMessage(result dto.ApiAssertionResultDto) string {
+	subject := *result.Type
+	if result.Target != nil && *result.Target != "" {
+		subject = fmt.Sprintf("%s[%s]", subject, *result.Target)
+	}
+	expected := ""
+	if result.Expected != nil {
+		expected = *result.Expected
+	}
+	if result.Actual == nil {
+		return fmt.Sprintf("%s 不存在", subject)
+	}
+	operator := enums.AssertionOperatorEquals.Code()
+	if result.Operator != nil && *result.Operator != "" {
+		operator = *result.Operator
+	}
+	return fmt.Sprintf("%s 期望 %s %s，实际为 %s", subject, operator, expected, *result.Actual)
+}
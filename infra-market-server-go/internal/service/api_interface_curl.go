@@ -0,0 +1,457 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+// curlValueOptions 需要取值的 curl 选项，未列出的选项视为开关
+var curlValueOptions = map[string]bool{
+	"-X": true, "--request": true,
+	"-H": true, "--header": true,
+	"-d": true, "--data": true, "--data-raw": true, "--data-binary": true, "--data-ascii": true, "--data-urlencode": true,
+	"-F": true, "--form": true, "--form-string": true,
+	"-u": true, "--user": true,
+	"-b": true, "--cookie": true,
+	"-A": true, "--user-agent": true,
+	"-e": true, "--referer": true,
+	"-m": true, "--max-time": true,
+	"--url": true, "--max-redirs": true, "--oauth2-bearer": true,
+	"-o": true, "--output": true, "-w": true, "--write-out": true,
+	"-x": true, "--proxy": true, "-E": true, "--cert": true, "--key": true, "--cacert": true,
+	"--connect-timeout": true, "--retry": true, "--resolve": true, "--limit-rate": true, "-T": true, "--upload-file": true,
+}
+
+// curlCommand 解析后的 curl 命令
+type curlCommand struct {
+	method          string
+	rawURL          string
+	headers         [][2]string
+	data            []string
+	forms           []string
+	user            string
+	get             bool
+	head            bool
+	followRedirects bool
+	maxRedirects    *int
+	httpVersion     *enums.HttpVersion
+	timeout         *int64
+}
+
+// parseCurlCommand 将 curl 命令行解析为接口表单草稿
+func parseCurlCommand(command string) (dto.ApiInterfaceFormDto, error) {
+	tokens, err := splitCurlCommand(command)
+	if err != nil {
+		return dto.ApiInterfaceFormDto{}, err
+	}
+	if len(tokens) == 0 || tokens[0] != "curl" {
+		return dto.ApiInterfaceFormDto{}, fmt.Errorf("命令必须以 curl 开头")
+	}
+
+	cmd, err := parseCurlOptions(tokens[1:])
+	if err != nil {
+		return dto.ApiInterfaceFormDto{}, err
+	}
+	if cmd.rawURL == "" {
+		return dto.ApiInterfaceFormDto{}, fmt.Errorf("curl 命令中缺少请求地址")
+	}
+	if !strings.Contains(cmd.rawURL, "://") {
+		cmd.rawURL = "http://" + cmd.rawURL
+	}
+	parsedURL, err := url.Parse(cmd.rawURL)
+	if err != nil {
+		return dto.ApiInterfaceFormDto{}, fmt.Errorf("解析请求地址失败: %w", err)
+	}
+	query := parsedURL.Query()
+	parsedURL.RawQuery = ""
+	parsedURL.Fragment = ""
+
+	method := strings.ToUpper(cmd.method)
+	if method == "" {
+		switch {
+		case cmd.head:
+			method = "HEAD"
+		case (len(cmd.data) > 0 && !cmd.get) || len(cmd.forms) > 0:
+			method = "POST"
+		default:
+			method = "GET"
+		}
+	}
+	if enums.HttpMethodFromCode(method) == nil {
+		return dto.ApiInterfaceFormDto{}, fmt.Errorf("不支持的HTTP方法: %s", method)
+	}
+
+	path := parsedURL.Path
+	if path == "" {
+		path = "/"
+	}
+	form := dto.ApiInterfaceFormDto{
+		Name:         basic.Ptr(method + " " + path),
+		Method:       basic.Ptr(method),
+		URL:          basic.Ptr(parsedURL.String()),
+		URLParams:    make([]dto.ApiParamDto, 0),
+		PathParams:   make([]dto.ApiParamDto, 0),
+		HeaderParams: make([]dto.ApiParamDto, 0),
+		BodyParams:   make([]dto.ApiParamDto, 0),
+		Timeout:      cmd.timeout,
+		MaxRedirects: cmd.maxRedirects,
+	}
+	if cmd.followRedirects {
+		form.FollowRedirects = basic.Ptr(true)
+	}
+	if cmd.httpVersion != nil {
+		form.HttpVersion = basic.Ptr(cmd.httpVersion.Code())
+	}
+
+	// 请求头，Content-Type 用于确定请求体类型
+	contentType := ""
+	for _, header := range cmd.headers {
+		if strings.EqualFold(header[0], "Content-Type") {
+			contentType = header[1]
+			continue
+		}
+		form.HeaderParams = append(form.HeaderParams, newDraftParam(header[0], enums.ParamTypeHeader, header[1], enums.DataTypeSTRING, len(form.HeaderParams)))
+	}
+	if cmd.user != "" {
+		param := newDraftParam("Authorization", enums.ParamTypeHeader, "Basic "+base64.StdEncoding.EncodeToString([]byte(cmd.user)), enums.DataTypeSTRING, len(form.HeaderParams))
+		param.InputType = basic.Ptr(enums.InputTypePASSWORD.Code())
+		form.HeaderParams = append(form.HeaderParams, param)
+	}
+
+	// -G 时请求体数据追加到URL参数
+	data := strings.Join(cmd.data, "&")
+	if cmd.get && data != "" {
+		if values, err := url.ParseQuery(data); err == nil {
+			for k, v := range values {
+				query[k] = append(query[k], v...)
+			}
+		}
+		data = ""
+	}
+	for _, name := range sortedQueryKeys(query) {
+		form.URLParams = append(form.URLParams, newDraftParam(name, enums.ParamTypeURL, query.Get(name), enums.DataTypeSTRING, len(form.URLParams)))
+	}
+
+	if len(cmd.forms) > 0 {
+		applyCurlMultipart(&form, cmd.forms)
+	} else if data != "" {
+		applyCurlData(&form, contentType, data)
+	}
+	return form, nil
+}
+
+// parseCurlOptions 解析 curl 选项
+func parseCurlOptions(args []string) (*curlCommand, error) {
+	cmd := &curlCommand{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		option, value, hasValue := arg, "", false
+
+		// 短选项可与取值连写，如 -XPOST、-H'Accept: */*'
+		if len(arg) > 2 && arg[0] == '-' && arg[1] != '-' && curlValueOptions[arg[:2]] {
+			option, value, hasValue = arg[:2], arg[2:], true
+		}
+		if !strings.HasPrefix(option, "-") {
+			if cmd.rawURL == "" {
+				cmd.rawURL = option
+			}
+			continue
+		}
+		if curlValueOptions[option] && !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("选项 %s 缺少取值", option)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch option {
+		case "-X", "--request":
+			cmd.method = value
+		case "--url":
+			cmd.rawURL = value
+		case "-H", "--header":
+			if name, headerValue, ok := strings.Cut(value, ":"); ok {
+				cmd.headers = append(cmd.headers, [2]string{strings.TrimSpace(name), strings.TrimSpace(headerValue)})
+			}
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii":
+			cmd.data = append(cmd.data, value)
+		case "--data-urlencode":
+			if name, dataValue, ok := strings.Cut(value, "="); ok && name != "" {
+				cmd.data = append(cmd.data, name+"="+url.QueryEscape(dataValue))
+			} else {
+				cmd.data = append(cmd.data, url.QueryEscape(strings.TrimPrefix(value, "=")))
+			}
+		case "-F", "--form", "--form-string":
+			cmd.forms = append(cmd.forms, value)
+		case "-u", "--user":
+			cmd.user = value
+		case "-b", "--cookie":
+			cmd.headers = append(cmd.headers, [2]string{"Cookie", value})
+		case "-A", "--user-agent":
+			cmd.headers = append(cmd.headers, [2]string{"User-Agent", value})
+		case "-e", "--referer":
+			cmd.headers = append(cmd.headers, [2]string{"Referer", value})
+		case "--oauth2-bearer":
+			cmd.headers = append(cmd.headers, [2]string{"Authorization", "Bearer " + value})
+		case "-G", "--get":
+			cmd.get = true
+		case "-I", "--head":
+			cmd.head = true
+		case "-L", "--location":
+			cmd.followRedirects = true
+		case "--max-redirs":
+			if n, err := strconv.Atoi(value); err == nil {
+				cmd.maxRedirects = basic.Ptr(n)
+			}
+		case "-m", "--max-time":
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				cmd.timeout = basic.Ptr(int64(math.Ceil(seconds)))
+			}
+		case "--http1.1":
+			cmd.httpVersion = basic.Ptr(enums.HttpVersionHTTP11)
+		case "--http2", "--http2-prior-knowledge":
+			cmd.httpVersion = basic.Ptr(enums.HttpVersionHTTP2)
+		}
+	}
+	return cmd, nil
+}
+
+// applyCurlMultipart 将 -F 表单字段转换为 multipart Body 参数
+func applyCurlMultipart(form *dto.ApiInterfaceFormDto, fields []string) {
+	form.PostType = basic.Ptr(enums.PostTypeMultipartFormData.Code())
+	form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			continue
+		}
+		// 去掉 ;type=、;filename= 等附加属性
+		value, _, _ = strings.Cut(value, ";")
+		if strings.HasPrefix(value, "@") || strings.HasPrefix(value, "<") {
+			param := newDraftParam(name, enums.ParamTypeBody, nil, enums.DataTypeSTRING, len(form.BodyParams))
+			param.InputType = basic.Ptr(enums.InputTypeFILE.Code())
+			param.Description = basic.Ptr("文件: " + value[1:])
+			form.BodyParams = append(form.BodyParams, param)
+			continue
+		}
+		form.BodyParams = append(form.BodyParams, newDraftParam(name, enums.ParamTypeBody, value, enums.DataTypeSTRING, len(form.BodyParams)))
+	}
+}
+
+// applyCurlData 根据 Content-Type 将 -d 请求体转换为Body参数或RAW请求体
+// 顶层为对象的JSON拆分为Body参数，表单数据按键值拆分，其余内容使用RAW模式
+func applyCurlData(form *dto.ApiInterfaceFormDto, contentType, data string) {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	trimmed := strings.TrimSpace(data)
+	if mediaType == "" && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		mediaType = enums.PostTypeApplicationJSON.Code()
+	}
+	if mediaType == "" {
+		// curl 的 -d 默认使用表单编码
+		mediaType = enums.PostTypeApplicationXWWWFormURLEncoded.Code()
+	}
+
+	isJSON := mediaType == enums.PostTypeApplicationJSON.Code() || strings.HasSuffix(mediaType, "+json")
+	if isJSON {
+		var object map[string]any
+		if err := json.Unmarshal([]byte(trimmed), &object); err == nil {
+			form.PostType = basic.Ptr(enums.PostTypeApplicationJSON.Code())
+			form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+			names := make([]string, 0, len(object))
+			for name := range object {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				value, dataType := draftValueType(object[name])
+				form.BodyParams = append(form.BodyParams, newDraftParam(name, enums.ParamTypeBody, value, dataType, len(form.BodyParams)))
+			}
+			return
+		}
+	}
+
+	if mediaType == enums.PostTypeApplicationXWWWFormURLEncoded.Code() {
+		if values, err := url.ParseQuery(data); err == nil {
+			form.PostType = basic.Ptr(mediaType)
+			form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+			for _, name := range sortedQueryKeys(values) {
+				form.BodyParams = append(form.BodyParams, newDraftParam(name, enums.ParamTypeBody, values.Get(name), enums.DataTypeSTRING, len(form.BodyParams)))
+			}
+			return
+		}
+	}
+
+	postType := enums.PostTypeFromCode(mediaType)
+	if postType == nil || !postType.IsRawBodyType() {
+		postType = basic.Ptr(enums.PostTypeTextPlain)
+		if isJSON {
+			postType = basic.Ptr(enums.PostTypeApplicationJSON)
+		}
+	}
+	form.PostType = basic.Ptr(postType.Code())
+	form.BodyMode = basic.Ptr(enums.BodyModeRaw.Code())
+	form.RawBody = basic.Ptr(data)
+	form.RawDataType = basic.Ptr(postType.DataType().Code())
+}
+
+// draftValueType 推断JSON值对应的参数数据类型，对象和数组保留为JSON文本
+func draftValueType(value any) (any, enums.DataType) {
+	switch v := value.(type) {
+	case string:
+		return v, enums.DataTypeSTRING
+	case bool:
+		return v, enums.DataTypeBOOLEAN
+	case float64:
+		if v == math.Trunc(v) {
+			if v > math.MaxInt32 || v < math.MinInt32 {
+				return v, enums.DataTypeLONG
+			}
+			return v, enums.DataTypeINTEGER
+		}
+		return v, enums.DataTypeDOUBLE
+	case nil:
+		return nil, enums.DataTypeSTRING
+	}
+	jsonBytes, _ := json.Marshal(value)
+	return string(jsonBytes), enums.DataTypeJSONObject
+}
+
+// newDraftParam 创建导入草稿中的参数，取值作为默认值
+func newDraftParam(name string, paramType enums.ParamType, value any, dataType enums.DataType, sortIndex int) dto.ApiParamDto {
+	inputType := enums.InputTypeTEXT
+	switch dataType {
+	case enums.DataTypeINTEGER, enums.DataTypeLONG, enums.DataTypeDOUBLE:
+		inputType = enums.InputTypeNUMBER
+	case enums.DataTypeJSONObject:
+		inputType = enums.InputTypeTEXTAREA
+	}
+	return dto.ApiParamDto{
+		Name:         basic.Ptr(name),
+		ParamType:    basic.Ptr(paramType.Code()),
+		InputType:    basic.Ptr(inputType.Code()),
+		DataType:     basic.Ptr(dataType.Code()),
+		Required:     basic.Ptr(false),
+		DefaultValue: value,
+		Changeable:   basic.Ptr(true),
+		Sort:         basic.Ptr(sortIndex),
+	}
+}
+
+// sortedQueryKeys 按名称排序的参数名
+func sortedQueryKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitCurlCommand 按 shell 规则拆分命令行，支持单引号、双引号、$'...' 与反斜杠续行
+func splitCurlCommand(command string) ([]string, error) {
+	tokens := make([]string, 0)
+	var current strings.Builder
+	inToken := false
+	runes := []rune(command)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes) && (runes[i+1] == '\n' || runes[i+1] == '\r'):
+			// 反斜杠续行
+			i++
+			if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+				i++
+			}
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		case r == '\'':
+			inToken = true
+			end := indexRune(runes, '\'', i+1)
+			if end < 0 {
+				return nil, fmt.Errorf("单引号未闭合")
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			i = end
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			// ANSI-C 引号：$'...'
+			inToken = true
+			i += 2
+			for ; i < len(runes) && runes[i] != '\''; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					current.WriteString(ansiCEscape(runes[i]))
+					continue
+				}
+				current.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("引号未闭合")
+			}
+		case r == '"':
+			inToken = true
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				current.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("双引号未闭合")
+			}
+		case r == '\\' && i+1 < len(runes):
+			inToken = true
+			i++
+			current.WriteRune(runes[i])
+		default:
+			inToken = true
+			current.WriteRune(r)
+		}
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// ansiCEscape $'...' 中的转义字符
+func ansiCEscape(r rune) string {
+	switch r {
+	case 'n':
+		return "\n"
+	case 't':
+		return "\t"
+	case 'r':
+		return "\r"
+	}
+	return string(r)
+}
+
+// indexRune 从指定位置开始查找字符
+func indexRune(runes []rune, target rune, start int) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == target {
+			return i
+		}
+	}
+	return -1
+}
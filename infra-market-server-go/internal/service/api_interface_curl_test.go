@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+)
+
+func TestSplitCurlCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{"空白分隔", "curl  -X\tPOST http://a", []string{"curl", "-X", "POST", "http://a"}, false},
+		{"单引号原样保留", `curl -H 'X-A: "b" \n'`, []string{"curl", "-H", `X-A: "b" \n`}, false},
+		{"双引号转义", `curl -d "{\"a\":\"\$1\"}"`, []string{"curl", "-d", `{"a":"$1"}`}, false},
+		{"ANSI-C引号", `curl -d $'a\nb\'c'`, []string{"curl", "-d", "a\nb'c"}, false},
+		{"反斜杠续行", "curl \\\n  -X GET \\\r\n  http://a", []string{"curl", "-X", "GET", "http://a"}, false},
+		{"引号拼接", `curl http://a/'b c'"d"`, []string{"curl", "http://a/b cd"}, false},
+		{"反斜杠转义空格", `curl http://a/b\ c`, []string{"curl", "http://a/b c"}, false},
+		{"空字符串参数", `curl -d ''`, []string{"curl", "-d", ""}, false},
+		{"单引号未闭合", `curl -d 'abc`, nil, true},
+		{"双引号未闭合", `curl -d "abc`, nil, true},
+		{"ANSI-C引号未闭合", `curl -d $'abc`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitCurlCommand(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitCurlCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCurlCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCurlCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		method   string
+		url      string
+		headers  []string
+		urlQuery []string
+		postType string
+		bodyMode string
+		body     []string
+		rawBody  string
+	}{
+		{
+			name:    "默认GET并拆分URL参数",
+			command: `curl 'https://api.example.com/users?page=2&size=10#top'`,
+			method:  "GET", url: "https://api.example.com/users",
+			urlQuery: []string{"page=2", "size=10"},
+		},
+		{
+			name:    "缺少协议时补全http",
+			command: `curl example.com`,
+			method:  "GET", url: "http://example.com",
+		},
+		{
+			name:    "短选项连写",
+			command: `curl -XPUT -H'Accept: */*' http://a/b`,
+			method:  "PUT", url: "http://a/b",
+			headers: []string{"Accept=*/*"},
+		},
+		{
+			name:    "JSON对象拆分为Body参数",
+			command: `curl http://a/b -H 'Content-Type: application/json' -d '{"name":"n","age":3,"ok":true}'`,
+			method:  "POST", url: "http://a/b",
+			postType: "application/json", bodyMode: "PARAMS",
+			body: []string{"age=3", "name=n", "ok=true"},
+		},
+		{
+			name:    "未指定类型时识别JSON",
+			command: `curl http://a/b --data-raw '{"a":1.5}'`,
+			method:  "POST", url: "http://a/b",
+			postType: "application/json", bodyMode: "PARAMS",
+			body: []string{"a=1.5"},
+		},
+		{
+			name:    "JSON数组使用RAW",
+			command: `curl http://a/b -H 'Content-Type: application/json' -d '[1,2]'`,
+			method:  "POST", url: "http://a/b",
+			postType: "application/json", bodyMode: "RAW", rawBody: "[1,2]",
+		},
+		{
+			name:    "默认表单编码",
+			command: `curl http://a/b -d 'x=1' -d 'y=2' --data-urlencode 'z=a b'`,
+			method:  "POST", url: "http://a/b",
+			postType: "application/x-www-form-urlencoded", bodyMode: "PARAMS",
+			body: []string{"x=1", "y=2", "z=a b"},
+		},
+		{
+			name:    "-G将数据追加到URL参数",
+			command: `curl -G http://a/b?x=1 -d 'y=2'`,
+			method:  "GET", url: "http://a/b",
+			urlQuery: []string{"x=1", "y=2"},
+		},
+		{
+			name:    "HEAD请求",
+			command: `curl -I http://a/b`,
+			method:  "HEAD", url: "http://a/b",
+		},
+		{
+			name:    "multipart表单与文件",
+			command: `curl http://a/upload -F 'name=n;type=text/plain' -F 'file=@/tmp/a.png'`,
+			method:  "POST", url: "http://a/upload",
+			postType: "multipart/form-data", bodyMode: "PARAMS",
+			body: []string{"name=n", "file=<nil>"},
+		},
+		{
+			name:    "认证、Cookie等转为请求头",
+			command: `curl -u user:pass -b 'a=1' -A ua -e http://r --oauth2-bearer tk http://a/b`,
+			method:  "GET", url: "http://a/b",
+			headers: []string{"Cookie=a=1", "User-Agent=ua", "Referer=http://r", "Authorization=Bearer tk", "Authorization=Basic dXNlcjpwYXNz"},
+		},
+		{
+			name:    "XML请求体使用RAW",
+			command: `curl http://a/b -H 'Content-Type: application/xml' -d '<a/>'`,
+			method:  "POST", url: "http://a/b",
+			postType: "application/xml", bodyMode: "RAW", rawBody: "<a/>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, err := parseCurlCommand(tt.command)
+			if err != nil {
+				t.Fatalf("parseCurlCommand() error: %v", err)
+			}
+			if got := stringValue(form.Method); got != tt.method {
+				t.Errorf("method = %q, want %q", got, tt.method)
+			}
+			if got := stringValue(form.URL); got != tt.url {
+				t.Errorf("url = %q, want %q", got, tt.url)
+			}
+			assertDraftParams(t, "headers", form.HeaderParams, tt.headers)
+			assertDraftParams(t, "urlParams", form.URLParams, tt.urlQuery)
+			assertDraftParams(t, "bodyParams", form.BodyParams, tt.body)
+			if got := stringValue(form.PostType); got != tt.postType {
+				t.Errorf("postType = %q, want %q", got, tt.postType)
+			}
+			if got := stringValue(form.BodyMode); got != tt.bodyMode {
+				t.Errorf("bodyMode = %q, want %q", got, tt.bodyMode)
+			}
+			if got := stringValue(form.RawBody); got != tt.rawBody {
+				t.Errorf("rawBody = %q, want %q", got, tt.rawBody)
+			}
+		})
+	}
+}
+
+func TestParseCurlCommandOptions(t *testing.T) {
+	form, err := parseCurlCommand(`curl -L --max-redirs 3 -m 1.5 --http2 -u u:p http://a/b`)
+	if err != nil {
+		t.Fatalf("parseCurlCommand() error: %v", err)
+	}
+	if form.FollowRedirects == nil || !*form.FollowRedirects {
+		t.Errorf("followRedirects = %v, want true", form.FollowRedirects)
+	}
+	if form.MaxRedirects == nil || *form.MaxRedirects != 3 {
+		t.Errorf("maxRedirects = %v, want 3", form.MaxRedirects)
+	}
+	if form.Timeout == nil || *form.Timeout != 2 {
+		t.Errorf("timeout = %v, want 2", form.Timeout)
+	}
+	if got := stringValue(form.HttpVersion); got != "HTTP/2" {
+		t.Errorf("httpVersion = %q, want HTTP/2", got)
+	}
+	if len(form.HeaderParams) != 1 || stringValue(form.HeaderParams[0].InputType) != "PASSWORD" {
+		t.Errorf("-u 生成的 Authorization 请求头应为 PASSWORD 类型")
+	}
+}
+
+func TestParseCurlCommandInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+	}{
+		{"不以curl开头", `wget http://a`},
+		{"缺少请求地址", `curl -X GET`},
+		{"选项缺少取值", `curl http://a -H`},
+		{"不支持的方法", `curl -X FETCH http://a`},
+		{"引号未闭合", `curl 'http://a`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseCurlCommand(tt.command); err == nil {
+				t.Errorf("parseCurlCommand(%q) 期望返回错误", tt.command)
+			}
+		})
+	}
+}
+
+// assertDraftParams 按 name=默认值 的顺序比较草稿参数
+func assertDraftParams(t *testing.T, field string, params []dto.ApiParamDto, want []string) {
+	t.Helper()
+	got := make([]string, 0, len(params))
+	for _, param := range params {
+		got = append(got, fmt.Sprintf("%s=%v", stringValue(param.Name), param.DefaultValue))
+	}
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %q, want %q", field, got, want)
+	}
+}
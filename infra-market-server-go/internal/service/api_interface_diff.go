@@ -0,0 +1,245 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bucketheadv/infra-market/internal/config"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+// ResponseDiffer 执行记录响应对比器
+// 规则：对比状态码、响应头与响应体，忽略配置的易变响应头与响应体JSONPath
+type ResponseDiffer struct {
+	ignoreHeaders   []string
+	ignoreBodyPaths []string
+}
+
+func NewResponseDiffer(cfg *config.Config) *ResponseDiffer {
+	return &ResponseDiffer{
+		ignoreHeaders:   cfg.Replay.IgnoreHeaders,
+		ignoreBodyPaths: cfg.Replay.IgnoreBodyPaths,
+	}
+}
+
+// Diff 对比两条执行记录的响应，extraHeaders、extraPaths 为本次额外忽略的内容
+func (d *ResponseDiffer) Diff(original, current *entity.ApiInterfaceExecutionRecord, extraHeaders, extraPaths []string) (dto.ApiResponseDiffDto, error) {
+	ignoredHeaders := make(map[string]bool)
+	for _, name := range append(append([]string{}, d.ignoreHeaders...), extraHeaders...) {
+		if name = strings.TrimSpace(name); name != "" {
+			ignoredHeaders[strings.ToLower(name)] = true
+		}
+	}
+	ignoredPaths := make([][]redactionPathToken, 0)
+	for _, path := range append(append([]string{}, d.ignoreBodyPaths...), extraPaths...) {
+		tokens, err := parseRedactionPath(path)
+		if err != nil {
+			return dto.ApiResponseDiffDto{}, fmt.Errorf("无效的忽略路径 %s: %w", path, err)
+		}
+		ignoredPaths = append(ignoredPaths, tokens)
+	}
+
+	result := dto.ApiResponseDiffDto{
+		Headers: make([]dto.ApiDiffItemDto, 0),
+		Body:    make([]dto.ApiDiffItemDto, 0),
+	}
+
+	originalStatus, currentStatus := 0, 0
+	if original.ResponseStatus != nil {
+		originalStatus = *original.ResponseStatus
+	}
+	if current.ResponseStatus != nil {
+		currentStatus = *current.ResponseStatus
+	}
+	if originalStatus != currentStatus {
+		result.Status = &dto.ApiDiffItemDto{Path: "status", Type: enums.DiffTypeChanged.Code(), Original: originalStatus, Current: currentStatus}
+	}
+
+	result.Headers = diffHeaders(original.ResponseHeaders, current.ResponseHeaders, ignoredHeaders)
+	result.Body = diffBody(original.ResponseBody, current.ResponseBody, ignoredPaths)
+	result.Identical = result.Status == nil && len(result.Headers) == 0 && len(result.Body) == 0
+	return result, nil
+}
+
+// diffHeaders 对比响应头，名称不区分大小写
+func diffHeaders(originalJSON, currentJSON *string, ignored map[string]bool) []dto.ApiDiffItemDto {
+	normalize := func(headersJSON *string) map[string]string {
+		var headers map[string]string
+		unmarshalRecordJSON(headersJSON, &headers)
+		normalized := make(map[string]string, len(headers))
+		for name, value := range headers {
+			if !ignored[strings.ToLower(name)] {
+				normalized[strings.ToLower(name)] = value
+			}
+		}
+		return normalized
+	}
+	original, current := normalize(originalJSON), normalize(currentJSON)
+
+	names := make([]string, 0, len(original)+len(current))
+	for name := range original {
+		names = append(names, name)
+	}
+	for name := range current {
+		if _, ok := original[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	items := make([]dto.ApiDiffItemDto, 0)
+	for _, name := range names {
+		originalValue, inOriginal := original[name]
+		currentValue, inCurrent := current[name]
+		switch {
+		case !inOriginal:
+			items = append(items, dto.ApiDiffItemDto{Path: name, Type: enums.DiffTypeAdded.Code(), Current: currentValue})
+		case !inCurrent:
+			items = append(items, dto.ApiDiffItemDto{Path: name, Type: enums.DiffTypeRemoved.Code(), Original: originalValue})
+		case originalValue != currentValue:
+			items = append(items, dto.ApiDiffItemDto{Path: name, Type: enums.DiffTypeChanged.Code(), Original: originalValue, Current: currentValue})
+		}
+	}
+	return items
+}
+
+// diffBody 对比响应体：均为JSON时逐字段对比，否则按文本整体对比（忽略路径不生效）
+func diffBody(originalBody, currentBody *string, ignored [][]redactionPathToken) []dto.ApiDiffItemDto {
+	items := make([]dto.ApiDiffItemDto, 0)
+	originalText, currentText := stringValue(originalBody), stringValue(currentBody)
+	if originalText == currentText {
+		return items
+	}
+
+	var original, current any
+	if json.Unmarshal([]byte(originalText), &original) != nil || json.Unmarshal([]byte(currentText), &current) != nil {
+		return append(items, dto.ApiDiffItemDto{Path: "$", Type: enums.DiffTypeChanged.Code(), Original: originalText, Current: currentText})
+	}
+	diffJSONNode(original, current, nil, ignored, &items)
+	return items
+}
+
+// diffJSONNode 递归对比JSON节点，path 为当前节点的路径片段（字段名为 string，下标为 int）
+func diffJSONNode(original, current any, path []any, ignored [][]redactionPathToken, items *[]dto.ApiDiffItemDto) {
+	if isIgnoredJSONPath(path, ignored) {
+		return
+	}
+
+	switch originalValue := original.(type) {
+	case map[string]any:
+		if currentValue, ok := current.(map[string]any); ok {
+			keys := make([]string, 0, len(originalValue)+len(currentValue))
+			for key := range originalValue {
+				keys = append(keys, key)
+			}
+			for key := range currentValue {
+				if _, exists := originalValue[key]; !exists {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				childPath := append(append([]any{}, path...), key)
+				originalChild, inOriginal := originalValue[key]
+				currentChild, inCurrent := currentValue[key]
+				switch {
+				case !inOriginal:
+					appendDiffItem(items, childPath, ignored, enums.DiffTypeAdded, nil, currentChild)
+				case !inCurrent:
+					appendDiffItem(items, childPath, ignored, enums.DiffTypeRemoved, originalChild, nil)
+				default:
+					diffJSONNode(originalChild, currentChild, childPath, ignored, items)
+				}
+			}
+			return
+		}
+	case []any:
+		if currentValue, ok := current.([]any); ok {
+			for i := 0; i < len(originalValue) || i < len(currentValue); i++ {
+				childPath := append(append([]any{}, path...), i)
+				switch {
+				case i >= len(originalValue):
+					appendDiffItem(items, childPath, ignored, enums.DiffTypeAdded, nil, currentValue[i])
+				case i >= len(currentValue):
+					appendDiffItem(items, childPath, ignored, enums.DiffTypeRemoved, originalValue[i], nil)
+				default:
+					diffJSONNode(originalValue[i], currentValue[i], childPath, ignored, items)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(original, current) {
+		appendDiffItem(items, path, ignored, enums.DiffTypeChanged, original, current)
+	}
+}
+
+// appendDiffItem 追加差异项，命中忽略路径时跳过
+func appendDiffItem(items *[]dto.ApiDiffItemDto, path []any, ignored [][]redactionPathToken, diffType enums.DiffType, original, current any) {
+	if isIgnoredJSONPath(path, ignored) {
+		return
+	}
+	*items = append(*items, dto.ApiDiffItemDto{Path: formatJSONPath(path), Type: diffType.Code(), Original: original, Current: current})
+}
+
+// isIgnoredJSONPath 路径或其上级路径是否命中任一忽略规则
+func isIgnoredJSONPath(path []any, ignored [][]redactionPathToken) bool {
+	for _, tokens := range ignored {
+		if matchJSONPath(tokens, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchJSONPath 判断规则是否匹配路径的前缀
+func matchJSONPath(tokens []redactionPathToken, path []any) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	if len(path) == 0 {
+		return false
+	}
+	token := tokens[0]
+	if token.recursive {
+		direct := redactionPathToken{key: token.key, wildcard: token.wildcard}
+		return (matchPathSegment(direct, path[0]) && matchJSONPath(tokens[1:], path[1:])) || matchJSONPath(tokens, path[1:])
+	}
+	return matchPathSegment(token, path[0]) && matchJSONPath(tokens[1:], path[1:])
+}
+
+// matchPathSegment 判断单个路径片段是否匹配
+func matchPathSegment(token redactionPathToken, segment any) bool {
+	switch value := segment.(type) {
+	case string:
+		return !token.isIndex && (token.wildcard || token.key == value)
+	case int:
+		return token.wildcard || (token.isIndex && token.index == value)
+	}
+	return false
+}
+
+// formatJSONPath 将路径片段格式化为JSONPath
+func formatJSONPath(path []any) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, segment := range path {
+		switch value := segment.(type) {
+		case int:
+			b.WriteString(fmt.Sprintf("[%d]", value))
+		case string:
+			if value != "" && !strings.ContainsAny(value, ".[]'\" ") {
+				b.WriteString("." + value)
+			} else {
+				b.WriteString("['" + value + "']")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/config"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+func TestResponseDifferDiff(t *testing.T) {
+	differ := NewResponseDiffer(&config.Config{Replay: config.ReplayConfig{
+		IgnoreHeaders:   []string{"Date"},
+		IgnoreBodyPaths: []string{"$..timestamp"},
+	}})
+	original := &entity.ApiInterfaceExecutionRecord{
+		ResponseStatus:  basic.Ptr(200),
+		ResponseHeaders: basic.Ptr(`{"Date":"Mon","Content-Type":"application/json","X-Old":"1","X-Request-Id":"a"}`),
+		ResponseBody:    basic.Ptr(`{"name":"tom","timestamp":1,"items":[{"id":1,"timestamp":1},{"id":2}],"removed":true}`),
+	}
+	current := &entity.ApiInterfaceExecutionRecord{
+		ResponseStatus:  basic.Ptr(500),
+		ResponseHeaders: basic.Ptr(`{"date":"Tue","content-type":"text/plain","X-New":"2","X-Request-Id":"b"}`),
+		ResponseBody:    basic.Ptr(`{"name":"jerry","timestamp":2,"items":[{"id":1,"timestamp":2}],"added":null}`),
+	}
+
+	result, err := differ.Diff(original, current, []string{"x-request-id"}, nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if result.Identical || result.Status == nil || result.Status.Original != 200 || result.Status.Current != 500 {
+		t.Errorf("状态码差异 = %+v", result.Status)
+	}
+	assertDiffItems(t, "响应头", result.Headers, map[string]enums.DiffType{
+		"content-type": enums.DiffTypeChanged,
+		"x-new":        enums.DiffTypeAdded,
+		"x-old":        enums.DiffTypeRemoved,
+	})
+	assertDiffItems(t, "响应体", result.Body, map[string]enums.DiffType{
+		"$.added":    enums.DiffTypeAdded,
+		"$.items[1]": enums.DiffTypeRemoved,
+		"$.name":     enums.DiffTypeChanged,
+		"$.removed":  enums.DiffTypeRemoved,
+	})
+
+	// 额外忽略路径
+	result, err = differ.Diff(original, current, nil, []string{"$.items", "$.name", "$.added", "$.removed"})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(result.Body) != 0 {
+		t.Errorf("忽略后的响应体差异 = %+v", result.Body)
+	}
+}
+
+func TestResponseDifferDiffIdentical(t *testing.T) {
+	differ := NewResponseDiffer(&config.Config{})
+	record := &entity.ApiInterfaceExecutionRecord{
+		ResponseStatus:  basic.Ptr(200),
+		ResponseHeaders: basic.Ptr(`{"Content-Type":"application/json"}`),
+		ResponseBody:    basic.Ptr(`{"a":[1,2]}`),
+	}
+	result, err := differ.Diff(record, &entity.ApiInterfaceExecutionRecord{
+		ResponseStatus:  basic.Ptr(200),
+		ResponseHeaders: basic.Ptr(`{"content-type":"application/json"}`),
+		ResponseBody:    basic.Ptr(`{ "a": [1, 2] }`),
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !result.Identical {
+		t.Errorf("格式不同但内容相同的响应应视为一致: %+v", result)
+	}
+
+	if _, err := differ.Diff(record, record, nil, []string{"a.b"}); err == nil {
+		t.Error("无效的忽略路径应返回错误")
+	}
+}
+
+func TestDiffBodyText(t *testing.T) {
+	items := diffBody(basic.Ptr("hello"), basic.Ptr(`{"a":1}`), nil)
+	if len(items) != 1 || items[0].Path != "$" || items[0].Original != "hello" {
+		t.Errorf("非JSON响应体应整体对比: %+v", items)
+	}
+	if items := diffBody(nil, basic.Ptr(""), nil); len(items) != 0 {
+		t.Errorf("空响应体不应有差异: %+v", items)
+	}
+}
+
+func TestFormatJSONPath(t *testing.T) {
+	tests := []struct {
+		path []any
+		want string
+	}{
+		{nil, "$"},
+		{[]any{"data", 0, "id"}, "$.data[0].id"},
+		{[]any{"a.b", ""}, "$['a.b']['']"},
+	}
+	for _, tt := range tests {
+		if got := formatJSONPath(tt.path); got != tt.want {
+			t.Errorf("formatJSONPath(%v) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func assertDiffItems(t *testing.T, name string, items []dto.ApiDiffItemDto, want map[string]enums.DiffType) {
+	t.Helper()
+	got := make(map[string]string, len(items))
+	for _, item := range items {
+		got[item.Path] = item.Type
+	}
+	if len(got) != len(want) {
+		t.Errorf("%s差异 = %v, want %v", name, got, want)
+		return
+	}
+	for path, diffType := range want {
+		if got[path] != diffType.Code() {
+			t.Errorf("%s差异 %s = %s, want %s", name, path, got[path], diffType.Code())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+)
+
+// newTestExecuteService 创建只用于发送请求的接口服务
+func newTestExecuteService(redactor *SecretRedactor) *ApiInterfaceService {
+	return &ApiInterfaceService{
+		transportService:   &ApiTransportService{},
+		authProfileService: &ApiAuthProfileService{},
+		responseBodyStore:  &ResponseBodyStore{maxBodySize: 1 << 20},
+		redactor:           redactor,
+	}
+}
+
+func TestExecuteHTTPRequestRedactsFailedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/final?api_key="+r.URL.Query().Get("api_key"), http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"boom","token":"secret-token"}`))
+	}))
+	defer server.Close()
+
+	redactor := &SecretRedactor{responseBodyPaths: []string{"$.token"}, cipher: newTestSecretCipher(t)}
+	s := newTestExecuteService(redactor)
+	apiInterface := &entity.ApiInterface{Method: "GET", URL: server.URL + "/start?api_key=secret-key"}
+	response, err := s.executeHTTPRequest(apiInterface, nil, &dto.ApiExecuteRequestDto{})
+	if err != nil {
+		t.Fatalf("executeHTTPRequest() error: %v", err)
+	}
+	if response.Success || response.Status != http.StatusInternalServerError {
+		t.Fatalf("executeHTTPRequest() status = %d, success = %v", response.Status, response.Success)
+	}
+	if got := stringValue(response.Error); got != "HTTP 500" {
+		t.Errorf("错误信息 = %q, want HTTP 500", got)
+	}
+	if len(response.RedirectChain) != 1 {
+		t.Fatalf("重定向链 = %+v, want 1 跳", response.RedirectChain)
+	}
+
+	chainJSON, _ := json.Marshal(response.RedirectChain)
+	record := &entity.ApiInterfaceExecutionRecord{
+		ResponseBody:  response.Body,
+		ErrorMessage:  response.Error,
+		RedirectChain: stringPtr(string(chainJSON)),
+	}
+	redactor.RedactRecord(record, apiInterface)
+	for field, value := range map[string]*string{
+		"responseBody":  record.ResponseBody,
+		"errorMessage":  record.ErrorMessage,
+		"redirectChain": record.RedirectChain,
+	} {
+		if strings.Contains(stringValue(value), "secret") {
+			t.Errorf("执行记录 %s 未脱敏: %s", field, stringValue(value))
+		}
+	}
+	if record.RawPayload == nil {
+		t.Errorf("脱敏后应保存原始内容")
+	}
+
+	redactor.RedactResponse(response)
+	if hop := response.RedirectChain[0]; strings.Contains(hop.URL+hop.Location, "secret") {
+		t.Errorf("返回的重定向链未脱敏: %+v", hop)
+	}
+}
+
+func TestMaskURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"查询参数", "https://a/b?api_key=k&page=1", "https://a/b?api_key=******&page=******"},
+		{"用户密码", "http://user:pass@a/b", "http://user:%2A%2A%2A%2A%2A%2A@a/b"},
+		{"没有敏感信息", "https://a/b", "https://a/b"},
+		{"无法解析", "http://a b/%zz", redactionMask},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskURL(tt.url); got != tt.want {
+				t.Errorf("maskURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskURLsInText(t *testing.T) {
+	text := `Get "https://a/b?api_key=k": dial tcp: connection refused`
+	want := `Get "https://a/b?api_key=******": dial tcp: connection refused`
+	if got := stringValue(maskURLsInText(&text)); got != want {
+		t.Errorf("maskURLsInText() = %q, want %q", got, want)
+	}
+}
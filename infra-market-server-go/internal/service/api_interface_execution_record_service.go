@@ -1,56 +1,330 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
 	"github.com/bucketheadv/infra-market/internal/dto"
 	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
 	"github.com/bucketheadv/infra-market/internal/repository"
 	"github.com/bucketheadv/infra-market/internal/util"
 )
 
+// harExportMaxRecords 单次导出HAR的最大记录数
+const harExportMaxRecords = 1000
+
+const (
+	responseBlobCleanupInterval = time.Hour // 清理未引用响应体文件的间隔
+	responseBlobCleanupGrace    = time.Hour // 保存后尚未写入执行记录的文件在该时长内不清理
+	responseBlobCleanupBatch    = 500       // 每次查询引用的文件数
+)
+
 type ApiInterfaceExecutionRecordService struct {
-	repo *repository.ApiInterfaceExecutionRecordRepository
+	repo                *repository.ApiInterfaceExecutionRecordRepository
+	apiInterfaceRepo    *repository.ApiInterfaceRepository
+	apiEnvironmentRepo  *repository.ApiEnvironmentRepository
+	authService         *AuthService
+	redactor            *SecretRedactor
+	differ              *ResponseDiffer
+	responseBodyStore   *ResponseBodyStore
+	apiInterfaceService *ApiInterfaceService
+	aclService          *ApiInterfaceAclService
+	cleanupOnce         sync.Once
 }
 
-func NewApiInterfaceExecutionRecordService(repo *repository.ApiInterfaceExecutionRecordRepository) *ApiInterfaceExecutionRecordService {
-	return &ApiInterfaceExecutionRecordService{repo: repo}
+func NewApiInterfaceExecutionRecordService(
+	repo *repository.ApiInterfaceExecutionRecordRepository,
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	apiEnvironmentRepo *repository.ApiEnvironmentRepository,
+	authService *AuthService,
+	redactor *SecretRedactor,
+	differ *ResponseDiffer,
+	responseBodyStore *ResponseBodyStore,
+	apiInterfaceService *ApiInterfaceService,
+	aclService *ApiInterfaceAclService,
+) *ApiInterfaceExecutionRecordService {
+	return &ApiInterfaceExecutionRecordService{
+		repo:                repo,
+		apiInterfaceRepo:    apiInterfaceRepo,
+		apiEnvironmentRepo:  apiEnvironmentRepo,
+		authService:         authService,
+		redactor:            redactor,
+		differ:              differ,
+		responseBodyStore:   responseBodyStore,
+		apiInterfaceService: apiInterfaceService,
+		aclService:          aclService,
+	}
 }
 
-// FindPage 分页查询
-func (s *ApiInterfaceExecutionRecordService) FindPage(query dto.ApiInterfaceExecutionRecordQueryDto) dto.ApiData[dto.PageResult[dto.ApiInterfaceExecutionRecordDto]] {
+// FindPage 分页查询，只返回当前用户可见接口的执行记录
+func (s *ApiInterfaceExecutionRecordService) FindPage(query dto.ApiInterfaceExecutionRecordQueryDto, uid uint64) dto.ApiData[dto.PageResult[dto.ApiInterfaceExecutionRecordDto]] {
+	unmasked := query.Unmasked != nil && *query.Unmasked
+	if unmasked && !s.canViewUnmasked(uid) {
+		return dto.Error[dto.PageResult[dto.ApiInterfaceExecutionRecordDto]]("无权查看未脱敏的执行记录", http.StatusForbidden)
+	}
+	s.aclService.ApplyRecordVisibility(&query, uid)
+
 	records, total, err := s.repo.Page(query)
-	return PageResultBuilder(records, total, err, s.convertToDto, &query)
+	if err == nil {
+		s.prepareRecords(records, unmasked)
+	}
+	return PageResultBuilder(records, total, err, func(record *entity.ApiInterfaceExecutionRecord) dto.ApiInterfaceExecutionRecordDto {
+		return s.convertToDto(record, !unmasked)
+	}, &query)
 }
 
-// GetByID 根据ID查询
-func (s *ApiInterfaceExecutionRecordService) GetByID(id uint64) dto.ApiData[dto.ApiInterfaceExecutionRecordDto] {
+// GetByID 根据ID查询，需要接口的查看权限
+func (s *ApiInterfaceExecutionRecordService) GetByID(id uint64, uid uint64, unmasked bool) dto.ApiData[dto.ApiInterfaceExecutionRecordDto] {
+	if unmasked && !s.canViewUnmasked(uid) {
+		return dto.Error[dto.ApiInterfaceExecutionRecordDto]("无权查看未脱敏的执行记录", http.StatusForbidden)
+	}
+
 	record, err := s.repo.FindByID(id)
 	if err != nil {
 		return dto.Error[dto.ApiInterfaceExecutionRecordDto]("执行记录不存在", http.StatusNotFound)
 	}
+	if err := s.checkRecordPermission(record, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiInterfaceExecutionRecordDto](err.Error(), http.StatusForbidden)
+	}
 
-	recordDto := s.convertToDto(record)
+	records := []entity.ApiInterfaceExecutionRecord{*record}
+	s.prepareRecords(records, unmasked)
+	recordDto := s.convertToDto(&records[0], !unmasked)
 	return dto.Success(recordDto)
 }
 
-// FindByExecutorID 根据执行人ID查询
-func (s *ApiInterfaceExecutionRecordService) FindByExecutorID(executorID uint64, limit int) dto.ApiData[[]dto.ApiInterfaceExecutionRecordDto] {
-	records, err := s.repo.FindByExecutorID(executorID, limit)
+// GetSnippet 将执行记录的请求生成代码片段，脱敏规则与查看记录详情一致，需要接口的查看权限
+func (s *ApiInterfaceExecutionRecordService) GetSnippet(id uint64, uid uint64, unmasked bool) dto.ApiData[dto.ApiInterfaceSnippetDto] {
+	if unmasked && !s.canViewUnmasked(uid) {
+		return dto.Error[dto.ApiInterfaceSnippetDto]("无权查看未脱敏的执行记录", http.StatusForbidden)
+	}
+
+	record, err := s.repo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto]("执行记录不存在", http.StatusNotFound)
+	}
+
+	records := []entity.ApiInterfaceExecutionRecord{*record}
+	s.prepareRecords(records, unmasked)
+	return s.apiInterfaceService.BuildRecordSnippet(&records[0], uid)
+}
+
+// DownloadResponseBody 下载执行记录的响应体
+// 优先读取本地保存的完整响应体，否则返回执行记录中保存的内容（可能已截断、脱敏）；
+// 本地保存的响应体未经脱敏（二进制内容同样可能包含敏感信息），需要查看未脱敏记录的权限；均需要接口的查看权限
+func (s *ApiInterfaceExecutionRecordService) DownloadResponseBody(id uint64, uid uint64) dto.ApiData[dto.ApiResponseBodyDownloadDto] {
+	record, err := s.repo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiResponseBodyDownloadDto]("执行记录不存在", http.StatusNotFound)
+	}
+	if err := s.checkRecordPermission(record, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiResponseBodyDownloadDto](err.Error(), http.StatusForbidden)
+	}
+
+	var info dto.ApiResponseBodyInfoDto
+	unmarshalRecordJSON(record.ResponseBodyInfo, &info)
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	download := dto.ApiResponseBodyDownloadDto{
+		FileName:    responseBodyFileName(record.ID, contentType),
+		ContentType: contentType,
+	}
+
+	if record.ResponseBlobKey != nil && *record.ResponseBlobKey != "" {
+		if !s.canViewUnmasked(uid) {
+			return dto.Error[dto.ApiResponseBodyDownloadDto]("无权下载未脱敏的响应体", http.StatusForbidden)
+		}
+		file, err := s.responseBodyStore.Open(*record.ResponseBlobKey)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "打开响应体文件失败，记录ID: %d, 错误: %v\n", id, err)
+			return dto.Error[dto.ApiResponseBodyDownloadDto]("响应体文件不存在", http.StatusNotFound)
+		}
+		if stat, err := file.Stat(); err == nil {
+			download.Size = stat.Size()
+		}
+		download.Reader = file
+		return dto.Success(download)
+	}
+
+	if record.ResponseBody == nil {
+		return dto.Error[dto.ApiResponseBodyDownloadDto]("执行记录未保存响应体", http.StatusNotFound)
+	}
+	records := []entity.ApiInterfaceExecutionRecord{*record}
+	s.prepareRecords(records, false)
+	body := stringValue(records[0].ResponseBody)
+	download.Size = int64(len(body))
+	download.Reader = io.NopCloser(strings.NewReader(body))
+	return dto.Success(download)
+}
+
+// responseBodyFileName 按内容类型生成下载文件名
+func responseBodyFileName(id uint64, contentType string) string {
+	extension := ".bin"
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json":
+		extension = ".json"
+	case strings.HasPrefix(mediaType, "text/"):
+		extension = ".txt"
+	default:
+		if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+			extension = extensions[0]
+		}
+	}
+	return fmt.Sprintf("response-%d%s", id, extension)
+}
+
+// Replay 按当前接口定义重新执行记录中的请求，新记录关联原记录，并对比两次响应
+// 请求使用脱敏前的原始内容；响应对比基于脱敏后的内容，避免在差异中暴露敏感信息；需要接口的执行权限
+func (s *ApiInterfaceExecutionRecordService) Replay(id uint64, req dto.ApiExecutionReplayRequestDto, uid uint64, clientIP, userAgent string) dto.ApiData[dto.ApiExecutionReplayResultDto] {
+	for _, path := range req.IgnorePaths {
+		if _, err := parseRedactionPath(path); err != nil {
+			return dto.Error[dto.ApiExecutionReplayResultDto](fmt.Sprintf("无效的忽略路径 %s: %v", path, err), http.StatusBadRequest)
+		}
+	}
+
+	original, err := s.repo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiExecutionReplayResultDto]("执行记录不存在", http.StatusNotFound)
+	}
+	if original.InterfaceID == nil {
+		return dto.Error[dto.ApiExecutionReplayResultDto]("接口不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(*original.InterfaceID, uid, enums.InterfacePermissionExecute); err != nil {
+		return dto.Error[dto.ApiExecutionReplayResultDto](err.Error(), http.StatusForbidden)
+	}
+	if original.RequestFiles != nil && *original.RequestFiles != "" && *original.RequestFiles != "[]" {
+		return dto.Error[dto.ApiExecutionReplayResultDto]("执行记录未保存上传文件内容，无法重放", http.StatusBadRequest)
+	}
+	apiInterface, err := s.apiInterfaceRepo.FindByID(*original.InterfaceID)
+	if err != nil {
+		return dto.Error[dto.ApiExecutionReplayResultDto]("接口不存在", http.StatusNotFound)
+	}
+
+	source := *original
+	if err := s.redactor.RestoreRecord(&source); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "还原执行记录失败，记录ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiExecutionReplayResultDto]("还原执行记录失败", http.StatusInternalServerError)
+	}
+	_, executeReq := restoreRecordRequest(apiInterface, nil, &source)
+	executeReq.InterfaceID = basic.Ptr(apiInterface.ID)
+	executeReq.EnvironmentID = original.EnvironmentID
+	if req.EnvironmentID != nil {
+		executeReq.EnvironmentID = req.EnvironmentID
+	}
+	executeReq.ReplayOfID = basic.Ptr(original.ID)
+	executeReq.Remark = req.Remark
+	if executeReq.Remark == nil {
+		executeReq.Remark = basic.Ptr(fmt.Sprintf("重放执行记录 #%d", original.ID))
+	}
+
+	executed := s.apiInterfaceService.Execute(*executeReq, uid, clientIP, userAgent)
+	if executed.Code != http.StatusOK {
+		return dto.Error[dto.ApiExecutionReplayResultDto](executed.Message, executed.Code)
+	}
+	result := dto.ApiExecutionReplayResultDto{OriginalRecordID: original.ID, Response: executed.Data}
+	if executed.Data.RecordID == nil {
+		return dto.Success(result)
+	}
+
+	replayed, err := s.repo.FindByID(*executed.Data.RecordID)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询重放执行记录失败，记录ID: %d, 错误: %v\n", *executed.Data.RecordID, err)
+		return dto.Success(result)
+	}
+	records := []entity.ApiInterfaceExecutionRecord{*original, *replayed}
+	s.prepareRecords(records, false)
+	diff, err := s.differ.Diff(&records[0], &records[1], req.IgnoreHeaders, req.IgnorePaths)
+	if err != nil {
+		return dto.Error[dto.ApiExecutionReplayResultDto](err.Error(), http.StatusBadRequest)
+	}
+	result.Diff = &diff
+	return dto.Success(result)
+}
+
+// ExportHar 将查询条件选中的执行记录导出为 HAR 1.2 文件，忽略分页参数，脱敏规则与可见范围与列表查询一致
+func (s *ApiInterfaceExecutionRecordService) ExportHar(query dto.ApiInterfaceExecutionRecordQueryDto, uid uint64) dto.ApiData[[]byte] {
+	unmasked := query.Unmasked != nil && *query.Unmasked
+	if unmasked && !s.canViewUnmasked(uid) {
+		return dto.Error[[]byte]("无权查看未脱敏的执行记录", http.StatusForbidden)
+	}
+	s.aclService.ApplyRecordVisibility(&query, uid)
+
+	records, err := s.repo.FindAll(query, harExportMaxRecords)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询导出执行记录失败: %v\n", err)
+		return dto.Error[[]byte]("查询失败", http.StatusInternalServerError)
+	}
+	if len(records) == 0 {
+		return dto.Error[[]byte]("没有符合条件的执行记录", http.StatusNotFound)
+	}
+	s.prepareRecords(records, unmasked)
+
+	interfaceIDs := make([]uint64, 0)
+	environmentIDs := make([]uint64, 0)
+	for _, record := range records {
+		if record.InterfaceID != nil {
+			interfaceIDs = append(interfaceIDs, *record.InterfaceID)
+		}
+		if record.EnvironmentID != nil {
+			environmentIDs = append(environmentIDs, *record.EnvironmentID)
+		}
+	}
+	interfaces, err := s.apiInterfaceRepo.FindAllByIDs(interfaceIDs)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询接口信息失败: %v\n", err)
+		return dto.Error[[]byte]("查询接口失败", http.StatusInternalServerError)
+	}
+	environments, err := s.apiEnvironmentRepo.FindByIDs(environmentIDs)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询环境信息失败: %v\n", err)
+		return dto.Error[[]byte]("查询环境失败", http.StatusInternalServerError)
+	}
+
+	document := newHarBuilder(s.apiInterfaceService, interfaces, environments).build(records)
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return dto.Error[[]byte]("生成HAR文件失败", http.StatusInternalServerError)
+	}
+	return dto.Success(data)
+}
+
+// FindByExecutorID 根据执行人ID查询，只返回当前用户可见接口的执行记录
+func (s *ApiInterfaceExecutionRecordService) FindByExecutorID(executorID uint64, limit int, uid uint64) dto.ApiData[[]dto.ApiInterfaceExecutionRecordDto] {
+	query := dto.ApiInterfaceExecutionRecordQueryDto{ExecutorID: &executorID}
+	s.aclService.ApplyRecordVisibility(&query, uid)
+	records, err := s.repo.FindAll(query, limit)
 	if err != nil {
 		return dto.Error[[]dto.ApiInterfaceExecutionRecordDto]("查询失败", http.StatusInternalServerError)
 	}
 
+	s.prepareRecords(records, false)
 	recordDtos := make([]dto.ApiInterfaceExecutionRecordDto, len(records))
 	for i, record := range records {
-		recordDtos[i] = s.convertToDto(&record)
+		recordDtos[i] = s.convertToDto(&record, true)
 	}
 
 	return dto.Success(recordDtos)
 }
 
-// GetExecutionStats 获取执行统计信息
-func (s *ApiInterfaceExecutionRecordService) GetExecutionStats(interfaceID uint64) dto.ApiData[dto.ApiInterfaceExecutionRecordStatsDto] {
+// GetExecutionStats 获取执行统计信息，需要接口的查看权限
+func (s *ApiInterfaceExecutionRecordService) GetExecutionStats(interfaceID uint64, uid uint64) dto.ApiData[dto.ApiInterfaceExecutionRecordStatsDto] {
+	if err := s.aclService.CheckPermission(interfaceID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiInterfaceExecutionRecordStatsDto](err.Error(), http.StatusForbidden)
+	}
+
 	// 查询所有执行记录
 	query := dto.ApiInterfaceExecutionRecordQueryDto{
 		InterfaceID: &interfaceID,
@@ -146,29 +420,149 @@ func (s *ApiInterfaceExecutionRecordService) DeleteByTimeBefore(beforeTime int64
 	return dto.Success(deletedCount)
 }
 
+// checkRecordPermission 校验用户对执行记录所属接口拥有指定权限
+func (s *ApiInterfaceExecutionRecordService) checkRecordPermission(record *entity.ApiInterfaceExecutionRecord, uid uint64, required enums.InterfacePermission) error {
+	if record.InterfaceID == nil {
+		return fmt.Errorf("接口不存在")
+	}
+	return s.aclService.CheckPermission(*record.InterfaceID, uid, required)
+}
+
+// canViewUnmasked 判断用户是否有权查看未脱敏的执行记录
+func (s *ApiInterfaceExecutionRecordService) canViewUnmasked(uid uint64) bool {
+	return uid > 0 && s.authService.HasPermission(uid, enums.RecordUnmaskedPermissionCode)
+}
+
+// prepareRecords 返回前处理执行记录：有权限时还原原始内容，否则按当前规则再次脱敏（兼容历史记录）
+func (s *ApiInterfaceExecutionRecordService) prepareRecords(records []entity.ApiInterfaceExecutionRecord, unmasked bool) {
+	if unmasked {
+		for i := range records {
+			if err := s.redactor.RestoreRecord(&records[i]); err != nil {
+				logx.Errorf(context.Background(), logx.NameApp, "还原执行记录失败: %v\n", err)
+				s.redactor.maskRecord(&records[i], nil)
+			}
+		}
+		return
+	}
+
+	interfaceIDs := make([]uint64, 0)
+	for _, record := range records {
+		if record.InterfaceID != nil {
+			interfaceIDs = append(interfaceIDs, *record.InterfaceID)
+		}
+	}
+	secretParams := make(map[uint64]map[string]bool)
+	if len(interfaceIDs) > 0 {
+		interfaces, err := s.apiInterfaceRepo.FindByIDs(interfaceIDs)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "查询接口信息失败: %v\n", err)
+		}
+		for i := range interfaces {
+			secretParams[interfaces[i].ID] = passwordParamNames(&interfaces[i])
+		}
+	}
+	for i := range records {
+		var names map[string]bool
+		if records[i].InterfaceID != nil {
+			names = secretParams[*records[i].InterfaceID]
+		}
+		s.redactor.maskRecord(&records[i], names)
+	}
+}
+
 // convertToDto 转换实体为DTO
-func (s *ApiInterfaceExecutionRecordService) convertToDto(record *entity.ApiInterfaceExecutionRecord) dto.ApiInterfaceExecutionRecordDto {
+func (s *ApiInterfaceExecutionRecordService) convertToDto(record *entity.ApiInterfaceExecutionRecord, masked bool) dto.ApiInterfaceExecutionRecordDto {
 	createTime := util.Format(&record.CreateTime)
 	updateTime := util.Format(&record.UpdateTime)
 
 	return dto.ApiInterfaceExecutionRecordDto{
-		ID:              &record.ID,
-		InterfaceID:     record.InterfaceID,
-		ExecutorID:      record.ExecutorID,
-		ExecutorName:    &record.ExecutorName,
-		RequestParams:   record.RequestParams,
-		RequestHeaders:  record.RequestHeaders,
-		RequestBody:     record.RequestBody,
-		ResponseStatus:  record.ResponseStatus,
-		ResponseHeaders: record.ResponseHeaders,
-		ResponseBody:    record.ResponseBody,
-		ExecutionTime:   record.ExecutionTime,
-		Success:         record.Success,
-		ErrorMessage:    record.ErrorMessage,
-		Remark:          record.Remark,
-		ClientIP:        record.ClientIP,
-		UserAgent:       record.UserAgent,
-		CreateTime:      &createTime,
-		UpdateTime:      &updateTime,
+		ID:                &record.ID,
+		InterfaceID:       record.InterfaceID,
+		InterfaceVersion:  record.InterfaceVersion,
+		EnvironmentID:     record.EnvironmentID,
+		WorkflowRunID:     record.WorkflowRunID,
+		ScheduleID:        record.ScheduleID,
+		BatchID:           record.BatchID,
+		ReplayOfID:        record.ReplayOfID,
+		ExecutorID:        record.ExecutorID,
+		ExecutorName:      &record.ExecutorName,
+		RequestParams:     record.RequestParams,
+		RequestPathParams: record.RequestPathParams,
+		RequestHeaders:    record.RequestHeaders,
+		RequestBody:       record.RequestBody,
+		RequestFiles:      record.RequestFiles,
+		ResponseStatus:    record.ResponseStatus,
+		ResponseHeaders:   record.ResponseHeaders,
+		ResponseBody:      record.ResponseBody,
+		ResponseBodyInfo:  record.ResponseBodyInfo,
+		RedirectChain:     record.RedirectChain,
+		Attempts:          record.Attempts,
+		ParamSources:      record.ParamSources,
+		AssertionResults:  record.AssertionResults,
+		ExecutionTime:     record.ExecutionTime,
+		Success:           record.Success,
+		ErrorMessage:      record.ErrorMessage,
+		Remark:            record.Remark,
+		ClientIP:          record.ClientIP,
+		UserAgent:         record.UserAgent,
+		CreateTime:        &createTime,
+		UpdateTime:        &updateTime,
+		Masked:            masked,
+	}
+}
+
+// StartBlobCleanup 启动未引用响应体文件的定期清理：执行记录删除后对应的文件不再被引用，
+// 内容相同的响应体共用一个文件，只有没有任何执行记录引用时才删除
+func (s *ApiInterfaceExecutionRecordService) StartBlobCleanup() {
+	s.cleanupOnce.Do(func() {
+		go func() {
+			s.cleanupBlobs()
+			ticker := time.NewTicker(responseBlobCleanupInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				s.cleanupBlobs()
+			}
+		}()
+	})
+}
+
+// cleanupBlobs 删除保存超过 responseBlobCleanupGrace 且没有执行记录引用的响应体文件
+func (s *ApiInterfaceExecutionRecordService) cleanupBlobs() {
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "清理响应体文件异常: %v\n", r)
+		}
+	}()
+
+	keys, err := s.responseBodyStore.ListBlobs(time.Now().Add(-responseBlobCleanupGrace))
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询响应体文件失败: %v\n", err)
+		return
+	}
+	removed := 0
+	for start := 0; start < len(keys); start += responseBlobCleanupBatch {
+		batch := keys[start:min(start+responseBlobCleanupBatch, len(keys))]
+		referenced, err := s.repo.FindBlobKeys(batch)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "查询响应体文件引用失败: %v\n", err)
+			return
+		}
+		inUse := make(map[string]bool, len(referenced))
+		for _, key := range referenced {
+			inUse[key] = true
+		}
+		for _, key := range batch {
+			if inUse[key] {
+				continue
+			}
+			if err := s.responseBodyStore.Remove(key); err != nil {
+				logx.Errorf(context.Background(), logx.NameApp, "删除响应体文件失败，文件: %s, 错误: %v\n", key, err)
+				continue
+			}
+			removed++
+		}
+	}
+	if removed > 0 {
+		logx.Infof(context.Background(), logx.NameApp, "已清理未引用的响应体文件 %d 个", removed)
 	}
 }
@@ -0,0 +1,611 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+	"gorm.io/gorm"
+)
+
+// maxTagNameLength 标签名称的最大长度
+const maxTagNameLength = 50
+
+// ApiInterfaceGroupService 接口分组与标签管理
+// 规则：分组归属团队（以角色表示），未设置团队的分组继承上级分组的团队；
+// 管理员与所属团队的成员可以管理分组及其中的接口，不属于任何团队的分组所有人都可以管理
+type ApiInterfaceGroupService struct {
+	db               *gorm.DB
+	groupRepo        *repository.ApiInterfaceGroupRepository
+	apiInterfaceRepo *repository.ApiInterfaceRepository
+	tagRepo          *repository.ApiInterfaceTagRepository
+	tagRelationRepo  *repository.ApiInterfaceTagRelationRepository
+	roleRepo         *repository.RoleRepository
+	authService      *AuthService
+	aclService       *ApiInterfaceAclService
+}
+
+func NewApiInterfaceGroupService(
+	db *gorm.DB,
+	groupRepo *repository.ApiInterfaceGroupRepository,
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	tagRepo *repository.ApiInterfaceTagRepository,
+	tagRelationRepo *repository.ApiInterfaceTagRelationRepository,
+	roleRepo *repository.RoleRepository,
+	authService *AuthService,
+	aclService *ApiInterfaceAclService,
+) *ApiInterfaceGroupService {
+	return &ApiInterfaceGroupService{
+		db:               db,
+		groupRepo:        groupRepo,
+		apiInterfaceRepo: apiInterfaceRepo,
+		tagRepo:          tagRepo,
+		tagRelationRepo:  tagRelationRepo,
+		roleRepo:         roleRepo,
+		authService:      authService,
+		aclService:       aclService,
+	}
+}
+
+// groupTree 全部分组构成的树，用于计算子分组与所属团队
+type groupTree struct {
+	groups   map[uint64]*entity.ApiInterfaceGroup
+	children map[uint64][]uint64 // 上级分组ID -> 子分组ID，顶级分组的上级为0
+	roots    []uint64
+}
+
+// Tree 获取分组树，包含各分组的接口数量
+func (s *ApiInterfaceGroupService) Tree() dto.ApiData[[]dto.ApiInterfaceGroupDto] {
+	tree, err := s.loadTree()
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询接口分组失败: %v\n", err)
+		return dto.Error[[]dto.ApiInterfaceGroupDto]("查询分组失败", http.StatusInternalServerError)
+	}
+
+	groupIDs := make([]uint64, 0, len(tree.groups))
+	ownerIDs := make([]uint64, 0)
+	for id, group := range tree.groups {
+		groupIDs = append(groupIDs, id)
+		if group.OwnerRoleID != nil {
+			ownerIDs = append(ownerIDs, *group.OwnerRoleID)
+		}
+	}
+	counts, err := s.apiInterfaceRepo.CountByGroupIDs(groupIDs)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "统计分组接口数量失败: %v\n", err)
+		return dto.Error[[]dto.ApiInterfaceGroupDto]("查询分组失败", http.StatusInternalServerError)
+	}
+	roleNames := s.roleNames(ownerIDs)
+
+	var build func(id uint64) dto.ApiInterfaceGroupDto
+	build = func(id uint64) dto.ApiInterfaceGroupDto {
+		groupDto := s.convertToDto(tree, tree.groups[id], roleNames)
+		groupDto.InterfaceCount = counts[id]
+		groupDto.TotalCount = counts[id]
+		for _, childID := range tree.children[id] {
+			child := build(childID)
+			groupDto.TotalCount += child.TotalCount
+			groupDto.Children = append(groupDto.Children, child)
+		}
+		return groupDto
+	}
+
+	result := make([]dto.ApiInterfaceGroupDto, 0, len(tree.roots))
+	for _, id := range tree.roots {
+		result = append(result, build(id))
+	}
+	return dto.Success(result)
+}
+
+// FindByID 根据ID查询分组，不包含子分组
+func (s *ApiInterfaceGroupService) FindByID(id uint64) dto.ApiData[dto.ApiInterfaceGroupDto] {
+	tree, err := s.loadTree()
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceGroupDto]("查询分组失败", http.StatusInternalServerError)
+	}
+	group, ok := tree.groups[id]
+	if !ok {
+		return dto.Error[dto.ApiInterfaceGroupDto]("分组不存在", http.StatusNotFound)
+	}
+
+	ownerIDs := make([]uint64, 0, 1)
+	if ownerID := tree.ownerRoleID(id); ownerID != nil {
+		ownerIDs = append(ownerIDs, *ownerID)
+	}
+	groupDto := s.convertToDto(tree, group, s.roleNames(ownerIDs))
+	if counts, err := s.apiInterfaceRepo.CountByGroupIDs(tree.descendants(id)); err == nil {
+		groupDto.InterfaceCount = counts[id]
+		for _, count := range counts {
+			groupDto.TotalCount += count
+		}
+	}
+	return dto.Success(groupDto)
+}
+
+// Save 创建分组，uid 为操作人
+func (s *ApiInterfaceGroupService) Save(form dto.ApiInterfaceGroupFormDto, uid uint64) dto.ApiData[dto.ApiInterfaceGroupDto] {
+	tree, err := s.loadTree()
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceGroupDto]("查询分组失败", http.StatusInternalServerError)
+	}
+	group := &entity.ApiInterfaceGroup{}
+	if status, err := s.applyForm(tree, group, &form, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceGroupDto](err.Error(), status)
+	}
+
+	if err := s.groupRepo.Create(group); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "创建接口分组失败，分组名称: %s, 错误: %v\n", group.Name, err)
+		return dto.Error[dto.ApiInterfaceGroupDto]("创建分组失败", http.StatusInternalServerError)
+	}
+	return s.FindByID(group.ID)
+}
+
+// Update 更新分组，修改上级分组即移动分组，uid 为操作人
+func (s *ApiInterfaceGroupService) Update(id uint64, form dto.ApiInterfaceGroupFormDto, uid uint64) dto.ApiData[dto.ApiInterfaceGroupDto] {
+	tree, err := s.loadTree()
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceGroupDto]("查询分组失败", http.StatusInternalServerError)
+	}
+	existing, ok := tree.groups[id]
+	if !ok {
+		return dto.Error[dto.ApiInterfaceGroupDto]("分组不存在", http.StatusNotFound)
+	}
+	if !s.canManage(tree, &id, uid) {
+		return dto.Error[dto.ApiInterfaceGroupDto]("无权管理该分组", http.StatusForbidden)
+	}
+
+	group := *existing
+	if status, err := s.applyForm(tree, &group, &form, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceGroupDto](err.Error(), status)
+	}
+	group.UpdateTime = time.Now().UnixMilli()
+
+	if err := s.groupRepo.Update(&group); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新接口分组失败，分组ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiInterfaceGroupDto]("更新分组失败", http.StatusInternalServerError)
+	}
+	return s.FindByID(id)
+}
+
+// Delete 删除分组，strategy 决定子分组与接口移动到上级分组还是一并删除，uid 为操作人
+func (s *ApiInterfaceGroupService) Delete(id uint64, strategy string, uid uint64) dto.ApiData[any] {
+	deleteStrategy := enums.GroupDeleteStrategyFromCode(strings.ToUpper(strategy))
+	if deleteStrategy == nil {
+		return dto.Error[any](fmt.Sprintf("不支持的删除策略: %s", strategy), http.StatusBadRequest)
+	}
+	tree, err := s.loadTree()
+	if err != nil {
+		return dto.Error[any]("查询分组失败", http.StatusInternalServerError)
+	}
+	group, ok := tree.groups[id]
+	if !ok {
+		return dto.Error[any]("分组不存在", http.StatusNotFound)
+	}
+
+	switch *deleteStrategy {
+	case enums.GroupDeleteMoveUp:
+		// 内容移动到上级分组，需要同时有上级分组的管理权限
+		if !s.canManage(tree, &id, uid) || !s.canManage(tree, group.ParentID, uid) {
+			return dto.Error[any]("无权管理该分组或其上级分组", http.StatusForbidden)
+		}
+		err = WithTransaction(s.db, func(tx *gorm.DB) error {
+			if err := repository.NewApiInterfaceGroupRepository(tx).UpdateParentID(id, group.ParentID); err != nil {
+				return err
+			}
+			if err := repository.NewApiInterfaceRepository(tx).MoveGroupInterfaces(id, group.ParentID); err != nil {
+				return err
+			}
+			return repository.NewApiInterfaceGroupRepository(tx).DeleteByIDs([]uint64{id})
+		})
+	case enums.GroupDeleteAll:
+		// 子分组可能属于其他团队，需要对每个子分组都有管理权限
+		groupIDs := tree.descendants(id)
+		for _, groupID := range groupIDs {
+			if !s.canManage(tree, basic.Ptr(groupID), uid) {
+				return dto.Error[any](fmt.Sprintf("无权管理子分组 %s，无法删除", tree.groups[groupID].Name), http.StatusForbidden)
+			}
+		}
+		interfaceIDs, findErr := s.apiInterfaceRepo.FindIDsByGroupIDs(groupIDs)
+		if findErr != nil {
+			return dto.Error[any]("查询分组接口失败", http.StatusInternalServerError)
+		}
+		// 分组中的接口需要有编辑权限才能删除
+		for _, interfaceID := range interfaceIDs {
+			if err := s.aclService.CheckPermission(interfaceID, uid, enums.InterfacePermissionEdit); err != nil {
+				return dto.Error[any](fmt.Sprintf("分组中包含无权删除的接口: %d", interfaceID), http.StatusForbidden)
+			}
+		}
+		err = WithTransaction(s.db, func(tx *gorm.DB) error {
+			if err := repository.NewApiInterfaceTagRelationRepository(tx).DeleteByInterfaceIDs(interfaceIDs); err != nil {
+				return err
+			}
+			if err := repository.NewApiInterfaceAclRepository(tx).DeleteByInterfaceIDs(interfaceIDs); err != nil {
+				return err
+			}
+			if err := repository.NewApiInterfaceRepository(tx).DeleteByIDs(interfaceIDs); err != nil {
+				return err
+			}
+			return repository.NewApiInterfaceGroupRepository(tx).DeleteByIDs(groupIDs)
+		})
+	}
+
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "删除接口分组失败，分组ID: %d, 策略: %s, 错误: %v\n", id, deleteStrategy.Code(), err)
+		return dto.Error[any]("删除分组失败", http.StatusInternalServerError)
+	}
+	return dto.Success[any](nil)
+}
+
+// MoveInterfaces 批量移动接口到指定分组，需要有原分组与目标分组的管理权限以及接口的编辑权限，uid 为操作人
+func (s *ApiInterfaceGroupService) MoveInterfaces(form dto.ApiInterfaceMoveDto, uid uint64) dto.ApiData[any] {
+	tree, err := s.loadTree()
+	if err != nil {
+		return dto.Error[any]("查询分组失败", http.StatusInternalServerError)
+	}
+	if form.GroupID != nil {
+		if _, ok := tree.groups[*form.GroupID]; !ok {
+			return dto.Error[any]("目标分组不存在", http.StatusNotFound)
+		}
+	}
+	if !s.canManage(tree, form.GroupID, uid) {
+		return dto.Error[any]("无权管理目标分组", http.StatusForbidden)
+	}
+
+	interfaces, err := s.apiInterfaceRepo.FindAllByIDs(form.InterfaceIDs)
+	if err != nil {
+		return dto.Error[any]("查询接口失败", http.StatusInternalServerError)
+	}
+	found := make(map[uint64]bool, len(interfaces))
+	for _, apiInterface := range interfaces {
+		found[apiInterface.ID] = true
+		if !s.canManage(tree, apiInterface.GroupID, uid) {
+			return dto.Error[any](fmt.Sprintf("无权移动接口 %s", apiInterface.Name), http.StatusForbidden)
+		}
+		if err := s.aclService.CheckPermission(apiInterface.ID, uid, enums.InterfacePermissionEdit); err != nil {
+			return dto.Error[any](fmt.Sprintf("无权移动接口 %s", apiInterface.Name), http.StatusForbidden)
+		}
+	}
+	for _, id := range form.InterfaceIDs {
+		if !found[id] {
+			return dto.Error[any](fmt.Sprintf("接口不存在: %d", id), http.StatusNotFound)
+		}
+	}
+
+	if err := s.apiInterfaceRepo.UpdateGroupID(form.InterfaceIDs, form.GroupID); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "移动接口失败，接口ID: %v, 错误: %v\n", form.InterfaceIDs, err)
+		return dto.Error[any]("移动接口失败", http.StatusInternalServerError)
+	}
+	return dto.Success[any](nil)
+}
+
+// ListTags 获取全部标签及使用的接口数量
+func (s *ApiInterfaceGroupService) ListTags() dto.ApiData[[]dto.ApiInterfaceTagDto] {
+	tags, err := s.tagRepo.FindAll()
+	if err != nil {
+		return dto.Error[[]dto.ApiInterfaceTagDto]("查询标签失败", http.StatusInternalServerError)
+	}
+	tagIDs := make([]uint64, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	counts, err := s.tagRelationRepo.CountByTagIDs(tagIDs)
+	if err != nil {
+		return dto.Error[[]dto.ApiInterfaceTagDto]("查询标签失败", http.StatusInternalServerError)
+	}
+
+	result := make([]dto.ApiInterfaceTagDto, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, dto.ApiInterfaceTagDto{ID: tag.ID, Name: tag.Name, InterfaceCount: counts[tag.ID]})
+	}
+	return dto.Success(result)
+}
+
+// DeleteTag 删除标签及其与接口的关联
+// 标签会从所有关联的接口上移除，非管理员需要对每个关联接口都有编辑权限
+func (s *ApiInterfaceGroupService) DeleteTag(id uint64, uid uint64) dto.ApiData[any] {
+	if !s.authService.IsAdmin(uid) {
+		interfaceIDs, err := s.tagRelationRepo.FindInterfaceIDsByTagID(id)
+		if err != nil {
+			return dto.Error[any]("查询标签关联的接口失败", http.StatusInternalServerError)
+		}
+		for _, interfaceID := range interfaceIDs {
+			if err := s.aclService.CheckPermission(interfaceID, uid, enums.InterfacePermissionEdit); err != nil {
+				return dto.Error[any](fmt.Sprintf("标签关联了无权编辑的接口: %d", interfaceID), http.StatusForbidden)
+			}
+		}
+	}
+
+	err := WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := repository.NewApiInterfaceTagRelationRepository(tx).DeleteByTagID(id); err != nil {
+			return err
+		}
+		return repository.NewApiInterfaceTagRepository(tx).Delete(id)
+	})
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "删除接口标签失败，标签ID: %d, 错误: %v\n", id, err)
+		return dto.Error[any]("删除标签失败", http.StatusInternalServerError)
+	}
+	return dto.Success[any](nil)
+}
+
+// ExpandGroupIDs 获取分组及其全部子分组的ID
+func (s *ApiInterfaceGroupService) ExpandGroupIDs(id uint64) ([]uint64, error) {
+	tree, err := s.loadTree()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := tree.groups[id]; !ok {
+		return nil, fmt.Errorf("分组不存在")
+	}
+	return tree.descendants(id), nil
+}
+
+// CheckPermission 校验分组存在且用户可以将接口放入该分组，groupID 为空表示未分组
+func (s *ApiInterfaceGroupService) CheckPermission(groupID *uint64, uid uint64) (int, error) {
+	if groupID == nil {
+		return http.StatusOK, nil
+	}
+	tree, err := s.loadTree()
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("查询分组失败")
+	}
+	if _, ok := tree.groups[*groupID]; !ok {
+		return http.StatusBadRequest, fmt.Errorf("分组不存在")
+	}
+	if !s.canManage(tree, groupID, uid) {
+		return http.StatusForbidden, fmt.Errorf("无权管理该分组")
+	}
+	return http.StatusOK, nil
+}
+
+// TagNames 批量获取接口的标签名称
+func (s *ApiInterfaceGroupService) TagNames(interfaceIDs []uint64) map[uint64][]string {
+	result := make(map[uint64][]string)
+	relations, err := s.tagRelationRepo.FindByInterfaceIDs(interfaceIDs)
+	if err != nil || len(relations) == 0 {
+		return result
+	}
+	tagIDs := make([]uint64, 0, len(relations))
+	for _, relation := range relations {
+		tagIDs = append(tagIDs, relation.TagID)
+	}
+	tags, err := s.tagRepo.FindByIDs(tagIDs)
+	if err != nil {
+		return result
+	}
+	names := make(map[uint64]string, len(tags))
+	for _, tag := range tags {
+		names[tag.ID] = tag.Name
+	}
+	for _, relation := range relations {
+		if name, ok := names[relation.TagID]; ok {
+			result[relation.InterfaceID] = append(result[relation.InterfaceID], name)
+		}
+	}
+	return result
+}
+
+// applyForm 校验表单并写入分组，返回校验失败时的状态码
+func (s *ApiInterfaceGroupService) applyForm(tree *groupTree, group *entity.ApiInterfaceGroup, form *dto.ApiInterfaceGroupFormDto, uid uint64) (int, error) {
+	name := strings.TrimSpace(*form.Name)
+	if name == "" {
+		return http.StatusBadRequest, fmt.Errorf("分组名称不能为空")
+	}
+
+	// 移动到新的上级分组时需要有其管理权限，且不能移动到自身或子分组下
+	parentChanged := group.ID == 0 || !equalUint64Ptr(group.ParentID, form.ParentID)
+	if form.ParentID != nil {
+		if _, ok := tree.groups[*form.ParentID]; !ok {
+			return http.StatusBadRequest, fmt.Errorf("上级分组不存在")
+		}
+		if group.ID != 0 {
+			for _, id := range tree.descendants(group.ID) {
+				if id == *form.ParentID {
+					return http.StatusBadRequest, fmt.Errorf("不能移动到自身或子分组下")
+				}
+			}
+		}
+	}
+	if parentChanged && !s.canManage(tree, form.ParentID, uid) {
+		return http.StatusForbidden, fmt.Errorf("无权管理上级分组")
+	}
+
+	exists, err := s.groupRepo.ExistsByParentAndName(form.ParentID, name, group.ID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("查询分组失败")
+	}
+	if exists {
+		return http.StatusBadRequest, fmt.Errorf("同一上级分组下已存在同名分组")
+	}
+
+	// 只能将分组归属到自己所在的团队，管理员不受限制
+	if form.OwnerRoleID != nil && !equalUint64Ptr(group.OwnerRoleID, form.OwnerRoleID) {
+		if _, err := s.roleRepo.FindByID(*form.OwnerRoleID); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("所属团队不存在")
+		}
+		if !s.authService.IsAdmin(uid) && !s.authService.HasRole(uid, *form.OwnerRoleID) {
+			return http.StatusForbidden, fmt.Errorf("只能将分组归属到自己所在的团队")
+		}
+	}
+
+	group.Name = name
+	group.ParentID = form.ParentID
+	group.Description = form.Description
+	group.OwnerRoleID = form.OwnerRoleID
+	if form.Sort != nil {
+		group.Sort = *form.Sort
+	}
+	return http.StatusOK, nil
+}
+
+// canManage 判断用户是否可以管理分组，groupID 为空表示未分组，所有人都可以管理
+func (s *ApiInterfaceGroupService) canManage(tree *groupTree, groupID *uint64, uid uint64) bool {
+	if groupID == nil {
+		return true
+	}
+	ownerID := tree.ownerRoleID(*groupID)
+	if ownerID == nil {
+		return true
+	}
+	return s.authService.IsAdmin(uid) || s.authService.HasRole(uid, *ownerID)
+}
+
+// loadTree 加载全部分组
+func (s *ApiInterfaceGroupService) loadTree() (*groupTree, error) {
+	groups, err := s.groupRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	tree := &groupTree{
+		groups:   make(map[uint64]*entity.ApiInterfaceGroup, len(groups)),
+		children: make(map[uint64][]uint64),
+	}
+	for i := range groups {
+		tree.groups[groups[i].ID] = &groups[i]
+	}
+	for i := range groups {
+		group := &groups[i]
+		// 上级分组不存在的分组视为顶级分组
+		if group.ParentID != nil && tree.groups[*group.ParentID] != nil {
+			tree.children[*group.ParentID] = append(tree.children[*group.ParentID], group.ID)
+		} else {
+			tree.roots = append(tree.roots, group.ID)
+		}
+	}
+	return tree, nil
+}
+
+// descendants 获取分组及其全部子分组的ID
+func (t *groupTree) descendants(id uint64) []uint64 {
+	result := []uint64{id}
+	for i := 0; i < len(result); i++ {
+		result = append(result, t.children[result[i]]...)
+	}
+	return result
+}
+
+// ownerRoleID 获取分组所属团队，未设置时向上查找
+func (t *groupTree) ownerRoleID(id uint64) *uint64 {
+	visited := make(map[uint64]bool)
+	for group := t.groups[id]; group != nil && !visited[group.ID]; {
+		if group.OwnerRoleID != nil {
+			return group.OwnerRoleID
+		}
+		visited[group.ID] = true
+		if group.ParentID == nil {
+			break
+		}
+		group = t.groups[*group.ParentID]
+	}
+	return nil
+}
+
+// roleNames 批量获取角色名称
+func (s *ApiInterfaceGroupService) roleNames(roleIDs []uint64) map[uint64]string {
+	names := make(map[uint64]string, len(roleIDs))
+	roles, err := s.roleRepo.FindByIDs(roleIDs)
+	if err != nil {
+		return names
+	}
+	for _, role := range roles {
+		names[role.ID] = role.Name
+	}
+	return names
+}
+
+// convertToDto 转换分组为DTO，团队名称为分组实际所属（含继承）的团队
+func (s *ApiInterfaceGroupService) convertToDto(tree *groupTree, group *entity.ApiInterfaceGroup, roleNames map[uint64]string) dto.ApiInterfaceGroupDto {
+	groupDto := dto.ApiInterfaceGroupDto{
+		ID:          group.ID,
+		Name:        group.Name,
+		ParentID:    group.ParentID,
+		Description: group.Description,
+		Sort:        group.Sort,
+		OwnerRoleID: group.OwnerRoleID,
+		Children:    make([]dto.ApiInterfaceGroupDto, 0),
+		CreateTime:  util.Format(&group.CreateTime),
+		UpdateTime:  util.Format(&group.UpdateTime),
+	}
+	if ownerID := tree.ownerRoleID(group.ID); ownerID != nil {
+		if name, ok := roleNames[*ownerID]; ok {
+			groupDto.OwnerRoleName = basic.Ptr(name)
+		}
+	}
+	return groupDto
+}
+
+// replaceInterfaceTags 在事务中替换接口的标签，不存在的标签自动创建
+func replaceInterfaceTags(tx *gorm.DB, interfaceID uint64, names []string) error {
+	tagRepo := repository.NewApiInterfaceTagRepository(tx)
+	relationRepo := repository.NewApiInterfaceTagRelationRepository(tx)
+	if err := relationRepo.DeleteByInterfaceIDs([]uint64{interfaceID}); err != nil {
+		return err
+	}
+
+	names = normalizeTagNames(names)
+	existing, err := tagRepo.FindByNames(names)
+	if err != nil {
+		return err
+	}
+	tagIDs := make(map[string]uint64, len(existing))
+	for _, tag := range existing {
+		tagIDs[tag.Name] = tag.ID
+	}
+	for _, name := range names {
+		tagID, ok := tagIDs[name]
+		if !ok {
+			tag := &entity.ApiInterfaceTag{Name: name}
+			if err := tagRepo.Create(tag); err != nil {
+				return err
+			}
+			tagID = tag.ID
+		}
+		if err := relationRepo.Create(&entity.ApiInterfaceTagRelation{InterfaceID: interfaceID, TagID: tagID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeTagNames 去除标签名称首尾空白、空名称与重复名称
+func normalizeTagNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// validateTagNames 校验标签名称长度
+func validateTagNames(names []string) error {
+	for _, name := range normalizeTagNames(names) {
+		if utf8.RuneCountInString(name) > maxTagNameLength {
+			return fmt.Errorf("标签名称不能超过 %d 个字符: %s", maxTagNameLength, name)
+		}
+	}
+	return nil
+}
+
+// equalUint64Ptr 判断两个可空ID是否相同
+func equalUint64Ptr(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
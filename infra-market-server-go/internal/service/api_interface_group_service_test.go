@@ -0,0 +1,129 @@
+package service
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+)
+
+// newTestGroupTree 创建分组树：1(团队10) -> 2 -> 3，4 无团队，5 与 6 互为上级
+func newTestGroupTree() *groupTree {
+	groups := []entity.ApiInterfaceGroup{
+		{BaseEntity: entity.BaseEntity{ID: 1}, Name: "研发", OwnerRoleID: basic.Ptr(uint64(10))},
+		{BaseEntity: entity.BaseEntity{ID: 2}, Name: "用户", ParentID: basic.Ptr(uint64(1))},
+		{BaseEntity: entity.BaseEntity{ID: 3}, Name: "登录", ParentID: basic.Ptr(uint64(2))},
+		{BaseEntity: entity.BaseEntity{ID: 4}, Name: "公共"},
+		{BaseEntity: entity.BaseEntity{ID: 5}, Name: "循环A", ParentID: basic.Ptr(uint64(6))},
+		{BaseEntity: entity.BaseEntity{ID: 6}, Name: "循环B", ParentID: basic.Ptr(uint64(5))},
+	}
+	tree := &groupTree{groups: make(map[uint64]*entity.ApiInterfaceGroup), children: make(map[uint64][]uint64)}
+	for i := range groups {
+		group := &groups[i]
+		tree.groups[group.ID] = group
+		if group.ParentID != nil {
+			tree.children[*group.ParentID] = append(tree.children[*group.ParentID], group.ID)
+		} else {
+			tree.roots = append(tree.roots, group.ID)
+		}
+	}
+	return tree
+}
+
+func TestGroupTreeDescendants(t *testing.T) {
+	tree := newTestGroupTree()
+	if got := tree.descendants(1); !reflect.DeepEqual(got, []uint64{1, 2, 3}) {
+		t.Errorf("descendants(1) = %v, want [1 2 3]", got)
+	}
+	if got := tree.descendants(3); !reflect.DeepEqual(got, []uint64{3}) {
+		t.Errorf("descendants(3) = %v, want [3]", got)
+	}
+}
+
+func TestGroupTreeOwnerRoleID(t *testing.T) {
+	tree := newTestGroupTree()
+	for _, id := range []uint64{1, 2, 3} {
+		if owner := tree.ownerRoleID(id); owner == nil || *owner != 10 {
+			t.Errorf("ownerRoleID(%d) = %v, want 继承团队 10", id, owner)
+		}
+	}
+	for _, id := range []uint64{4, 5, 99} {
+		if owner := tree.ownerRoleID(id); owner != nil {
+			t.Errorf("ownerRoleID(%d) = %d, want nil", id, *owner)
+		}
+	}
+}
+
+func TestGroupCanManageWithoutOwner(t *testing.T) {
+	s := &ApiInterfaceGroupService{}
+	tree := newTestGroupTree()
+	if !s.canManage(tree, nil, 1) {
+		t.Error("未分组所有人都可以管理")
+	}
+	if !s.canManage(tree, basic.Ptr(uint64(4)), 1) {
+		t.Error("不属于任何团队的分组所有人都可以管理")
+	}
+}
+
+func TestGroupApplyFormValidation(t *testing.T) {
+	s := &ApiInterfaceGroupService{}
+	tests := []struct {
+		name     string
+		group    entity.ApiInterfaceGroup
+		form     dto.ApiInterfaceGroupFormDto
+		wantCode int
+		wantErr  string
+	}{
+		{"名称为空", entity.ApiInterfaceGroup{}, dto.ApiInterfaceGroupFormDto{Name: basic.Ptr("  ")}, http.StatusBadRequest, "分组名称不能为空"},
+		{"上级分组不存在", entity.ApiInterfaceGroup{}, dto.ApiInterfaceGroupFormDto{Name: basic.Ptr("新分组"), ParentID: basic.Ptr(uint64(99))}, http.StatusBadRequest, "上级分组不存在"},
+		{"移动到自身下", *newTestGroupTree().groups[2], dto.ApiInterfaceGroupFormDto{Name: basic.Ptr("用户"), ParentID: basic.Ptr(uint64(2))}, http.StatusBadRequest, "不能移动到自身或子分组下"},
+		{"移动到子分组下", *newTestGroupTree().groups[1], dto.ApiInterfaceGroupFormDto{Name: basic.Ptr("研发"), ParentID: basic.Ptr(uint64(3))}, http.StatusBadRequest, "不能移动到自身或子分组下"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := tt.group
+			code, err := s.applyForm(newTestGroupTree(), &group, &tt.form, 1)
+			if err == nil || code != tt.wantCode || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("applyForm() = %d, %v, want %d %s", code, err, tt.wantCode, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeTagNames(t *testing.T) {
+	got := normalizeTagNames([]string{" 用户 ", "", "登录", "用户", "  "})
+	if !reflect.DeepEqual(got, []string{"用户", "登录"}) {
+		t.Errorf("normalizeTagNames() = %q, want [用户 登录]", got)
+	}
+}
+
+func TestValidateTagNames(t *testing.T) {
+	if err := validateTagNames([]string{strings.Repeat("标", maxTagNameLength)}); err != nil {
+		t.Errorf("validateTagNames() 长度等于上限时 error = %v", err)
+	}
+	if err := validateTagNames([]string{strings.Repeat("标", maxTagNameLength+1)}); err == nil {
+		t.Error("validateTagNames() 超过长度上限应返回错误")
+	}
+}
+
+func TestEqualUint64Ptr(t *testing.T) {
+	tests := []struct {
+		a, b *uint64
+		want bool
+	}{
+		{nil, nil, true},
+		{basic.Ptr(uint64(1)), nil, false},
+		{nil, basic.Ptr(uint64(1)), false},
+		{basic.Ptr(uint64(1)), basic.Ptr(uint64(1)), true},
+		{basic.Ptr(uint64(1)), basic.Ptr(uint64(2)), false},
+	}
+	for _, tt := range tests {
+		if got := equalUint64Ptr(tt.a, tt.b); got != tt.want {
+			t.Errorf("equalUint64Ptr(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+// harCreatorName HAR 文件中的生成工具名称
+const harCreatorName = "infra-market"
+
+// harDocument HAR 1.2 文件
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            int64       `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []harPostParam `json:"params,omitempty"`
+}
+
+type harPostParam struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// harTimings 执行记录只保存总耗时，全部计入等待时间
+type harTimings struct {
+	Blocked int   `json:"blocked"`
+	DNS     int   `json:"dns"`
+	Connect int   `json:"connect"`
+	Send    int64 `json:"send"`
+	Wait    int64 `json:"wait"`
+	Receive int64 `json:"receive"`
+	SSL     int   `json:"ssl"`
+}
+
+// harBuilder 将执行记录转换为 HAR 条目，缓存记录关联的接口与环境
+type harBuilder struct {
+	apiInterfaceService *ApiInterfaceService
+	interfaces          map[uint64]*entity.ApiInterface
+	environments        map[uint64]*entity.ApiEnvironment
+}
+
+// newHarBuilder 创建 HAR 构建器
+func newHarBuilder(apiInterfaceService *ApiInterfaceService, interfaces []entity.ApiInterface, environments []entity.ApiEnvironment) *harBuilder {
+	builder := &harBuilder{
+		apiInterfaceService: apiInterfaceService,
+		interfaces:          make(map[uint64]*entity.ApiInterface, len(interfaces)),
+		environments:        make(map[uint64]*entity.ApiEnvironment, len(environments)),
+	}
+	for i := range interfaces {
+		builder.interfaces[interfaces[i].ID] = &interfaces[i]
+	}
+	for i := range environments {
+		builder.environments[environments[i].ID] = &environments[i]
+	}
+	return builder
+}
+
+// build 生成 HAR 文件，无法还原请求的记录会被跳过
+func (b *harBuilder) build(records []entity.ApiInterfaceExecutionRecord) harDocument {
+	document := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: harCreatorName, Version: "1.0"},
+		Entries: make([]harEntry, 0, len(records)),
+	}}
+	for i := range records {
+		entry, err := b.entry(&records[i])
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "执行记录转换HAR失败，记录ID: %d, 错误: %v\n", records[i].ID, err)
+			continue
+		}
+		document.Log.Entries = append(document.Log.Entries, entry)
+	}
+	return document
+}
+
+// entry 将单条执行记录转换为 HAR 条目
+func (b *harBuilder) entry(record *entity.ApiInterfaceExecutionRecord) (harEntry, error) {
+	if record.InterfaceID == nil || b.interfaces[*record.InterfaceID] == nil {
+		return harEntry{}, fmt.Errorf("接口不存在")
+	}
+	apiInterface := b.interfaces[*record.InterfaceID]
+	var environment *entity.ApiEnvironment
+	if record.EnvironmentID != nil {
+		if environment = b.environments[*record.EnvironmentID]; environment == nil {
+			return harEntry{}, fmt.Errorf("执行环境不存在")
+		}
+	}
+
+	renderedInterface, req := restoreRecordRequest(apiInterface, environment, record)
+	request, err := b.request(renderedInterface, environment, req)
+	if err != nil {
+		return harEntry{}, err
+	}
+
+	elapsed := int64(0)
+	if record.ExecutionTime != nil {
+		elapsed = *record.ExecutionTime
+	}
+	entry := harEntry{
+		StartedDateTime: time.UnixMilli(record.CreateTime).Format("2006-01-02T15:04:05.000Z07:00"),
+		Time:            elapsed,
+		Request:         request,
+		Response:        harResponseFromRecord(record, request.HTTPVersion),
+		Timings:         harTimings{Blocked: -1, DNS: -1, Connect: -1, Wait: elapsed, SSL: -1},
+	}
+	if record.ErrorMessage != nil {
+		entry.Comment = *record.ErrorMessage
+	}
+	return entry, nil
+}
+
+// request 还原请求行、请求头、查询参数与请求体
+func (b *harBuilder) request(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment, req *dto.ApiExecuteRequestDto) (harRequest, error) {
+	finalURL, err := b.apiInterfaceService.resolveRequestURL(apiInterface, environment, req)
+	if err != nil {
+		return harRequest{}, err
+	}
+	request := harRequest{
+		Method:      strings.ToUpper(apiInterface.Method),
+		URL:         finalURL,
+		HTTPVersion: harHTTPVersion(apiInterface),
+		Cookies:     make([]harNameValue, 0),
+		Headers:     harNameValues(req.Headers),
+		QueryString: make([]harNameValue, 0),
+		HeadersSize: -1,
+	}
+	if parsedURL, err := url.Parse(finalURL); err == nil {
+		query := parsedURL.Query()
+		for _, key := range sortedQueryKeys(query) {
+			for _, value := range query[key] {
+				request.QueryString = append(request.QueryString, harNameValue{Name: key, Value: value})
+			}
+		}
+	}
+
+	postType := requestPostType(apiInterface)
+	if !hasRequestBody(apiInterface, postType, req) {
+		return request, nil
+	}
+	if !isRawBodyMode(apiInterface) && postType == enums.PostTypeMultipartFormData.Code() {
+		postData := &harPostData{MimeType: postType, Params: make([]harPostParam, 0)}
+		names := make([]string, 0, len(req.BodyParams))
+		for name := range req.BodyParams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value := req.BodyParams[name]; value != nil {
+				for _, item := range paramValues(value) {
+					postData.Params = append(postData.Params, harPostParam{Name: name, Value: item})
+				}
+			}
+		}
+		for _, file := range req.Files {
+			postData.Params = append(postData.Params, harPostParam{Name: file.ParamName, FileName: file.FileName, ContentType: file.ContentType})
+		}
+		request.PostData = postData
+		request.BodySize = -1
+		return request, nil
+	}
+
+	contentType, body, err := encodeRequestBody(apiInterface, postType, req)
+	if err != nil {
+		return harRequest{}, err
+	}
+	headers := request.Headers[:0]
+	for _, header := range request.Headers {
+		if !strings.EqualFold(header.Name, "Content-Type") {
+			headers = append(headers, header)
+		}
+	}
+	request.Headers = append(headers, harNameValue{Name: "Content-Type", Value: contentType})
+	request.PostData = &harPostData{MimeType: contentType, Text: body}
+	if contentType == enums.PostTypeApplicationXWWWFormURLEncoded.Code() {
+		if values, err := url.ParseQuery(body); err == nil {
+			for _, key := range sortedQueryKeys(values) {
+				for _, value := range values[key] {
+					request.PostData.Params = append(request.PostData.Params, harPostParam{Name: key, Value: value})
+				}
+			}
+		}
+	}
+	request.BodySize = len(body)
+	return request, nil
+}
+
+// harResponseFromRecord 还原响应状态、响应头与响应体，请求未完成时状态码为0
+func harResponseFromRecord(record *entity.ApiInterfaceExecutionRecord, httpVersion string) harResponse {
+	var headers map[string]string
+	unmarshalRecordJSON(record.ResponseHeaders, &headers)
+	response := harResponse{
+		HTTPVersion: httpVersion,
+		Cookies:     make([]harNameValue, 0),
+		Headers:     harNameValues(headers),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if record.ResponseStatus != nil {
+		response.Status = *record.ResponseStatus
+		response.StatusText = http.StatusText(response.Status)
+	}
+	for name, value := range headers {
+		switch {
+		case strings.EqualFold(name, "Content-Type"):
+			response.Content.MimeType = value
+		case strings.EqualFold(name, "Location"):
+			response.RedirectURL = value
+		}
+	}
+	if record.ResponseBody != nil {
+		response.Content.Text = *record.ResponseBody
+		response.Content.Size = len(*record.ResponseBody)
+		response.BodySize = response.Content.Size
+	}
+	return response
+}
+
+// harHTTPVersion 接口配置的HTTP协议版本，自动协商时按 HTTP/1.1 记录
+func harHTTPVersion(apiInterface *entity.ApiInterface) string {
+	if apiInterface.HttpVersion != nil && *apiInterface.HttpVersion == enums.HttpVersionHTTP2.Code() {
+		return "HTTP/2.0"
+	}
+	return "HTTP/1.1"
+}
+
+// harNameValues 将键值对按名称排序转换为 HAR 列表
+func harNameValues(values map[string]string) []harNameValue {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]harNameValue, 0, len(names))
+	for _, name := range names {
+		list = append(list, harNameValue{Name: name, Value: values[name]})
+	}
+	return list
+}
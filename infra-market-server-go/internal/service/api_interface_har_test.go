@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+func TestHarBuilderBuild(t *testing.T) {
+	params, _ := json.Marshal([]dto.ApiParamDto{
+		{Name: basic.Ptr("id"), ParamType: basic.Ptr(enums.ParamTypePath.Code())},
+	})
+	apiInterface := entity.ApiInterface{
+		BaseEntity:  entity.BaseEntity{ID: 1},
+		Name:        "登录",
+		Method:      "POST",
+		URL:         "/users/{id}/login",
+		PostType:    basic.Ptr(enums.PostTypeApplicationXWWWFormURLEncoded.Code()),
+		Params:      basic.Ptr(string(params)),
+		HttpVersion: basic.Ptr(enums.HttpVersionHTTP2.Code()),
+	}
+	environment := entity.ApiEnvironment{BaseEntity: entity.BaseEntity{ID: 2}, BaseURL: "https://api.example.com/"}
+	records := []entity.ApiInterfaceExecutionRecord{
+		{
+			BaseEntity:        entity.BaseEntity{ID: 10, CreateTime: 1700000000000},
+			InterfaceID:       basic.Ptr(uint64(1)),
+			EnvironmentID:     basic.Ptr(uint64(2)),
+			RequestParams:     basic.Ptr(`{"lang":"zh"}`),
+			RequestPathParams: basic.Ptr(`{"id":"42"}`),
+			RequestHeaders:    basic.Ptr(`{"X-Trace-Id":"abc","Content-Type":"text/plain"}`),
+			RequestBody:       basic.Ptr(`{"username":"admin"}`),
+			ResponseStatus:    basic.Ptr(302),
+			ResponseHeaders:   basic.Ptr(`{"Content-Type":"application/json","Location":"/home"}`),
+			ResponseBody:      basic.Ptr(`{"ok":true}`),
+			ExecutionTime:     basic.Ptr(int64(35)),
+			ErrorMessage:      basic.Ptr("HTTP 302"),
+		},
+		// 接口已删除的记录被跳过
+		{BaseEntity: entity.BaseEntity{ID: 11}, InterfaceID: basic.Ptr(uint64(99))},
+		// 执行环境已删除的记录被跳过
+		{BaseEntity: entity.BaseEntity{ID: 12}, InterfaceID: basic.Ptr(uint64(1)), EnvironmentID: basic.Ptr(uint64(99))},
+	}
+
+	builder := newHarBuilder(&ApiInterfaceService{}, []entity.ApiInterface{apiInterface}, []entity.ApiEnvironment{environment})
+	document := builder.build(records)
+	if document.Log.Version != "1.2" || len(document.Log.Entries) != 1 {
+		t.Fatalf("HAR = %+v", document.Log)
+	}
+
+	entry := document.Log.Entries[0]
+	if entry.Time != 35 || entry.Timings.Wait != 35 || entry.Comment != "HTTP 302" {
+		t.Errorf("条目 = %+v", entry)
+	}
+	request := entry.Request
+	if request.Method != "POST" || request.URL != "https://api.example.com/users/42/login?lang=zh" || request.HTTPVersion != "HTTP/2.0" {
+		t.Errorf("请求行 = %s %s %s", request.Method, request.URL, request.HTTPVersion)
+	}
+	if len(request.QueryString) != 1 || request.QueryString[0] != (harNameValue{Name: "lang", Value: "zh"}) {
+		t.Errorf("查询参数 = %+v", request.QueryString)
+	}
+	contentTypes := 0
+	for _, header := range request.Headers {
+		if header.Name == "Content-Type" {
+			contentTypes++
+			if header.Value != enums.PostTypeApplicationXWWWFormURLEncoded.Code() {
+				t.Errorf("Content-Type = %s", header.Value)
+			}
+		}
+	}
+	if contentTypes != 1 {
+		t.Errorf("Content-Type 请求头数量 = %d, want 1", contentTypes)
+	}
+	if request.PostData == nil || request.PostData.Text != "username=admin" || len(request.PostData.Params) != 1 {
+		t.Errorf("请求体 = %+v", request.PostData)
+	}
+
+	response := entry.Response
+	if response.Status != 302 || response.StatusText != "Found" || response.RedirectURL != "/home" {
+		t.Errorf("响应 = %+v", response)
+	}
+	if response.Content.MimeType != "application/json" || response.Content.Text != `{"ok":true}` || response.BodySize != 11 {
+		t.Errorf("响应体 = %+v", response.Content)
+	}
+}
+
+func TestHarResponseFromRecordNotCompleted(t *testing.T) {
+	response := harResponseFromRecord(&entity.ApiInterfaceExecutionRecord{}, "HTTP/1.1")
+	if response.Status != 0 || response.BodySize != -1 || response.Headers == nil || response.Cookies == nil {
+		t.Errorf("未完成请求的响应 = %+v", response)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/go-resty/resty/v2"
+)
+
+// defaultMaxRedirects 默认最大重定向次数
+const defaultMaxRedirects = 10
+
+// redirectRecorder 记录请求过程中的重定向链
+type redirectRecorder struct {
+	follow       bool
+	maxRedirects int
+	hops         []dto.ApiRedirectHopDto
+	exceeded     bool
+}
+
+// newRedirectRecorder 根据接口配置创建重定向记录器
+func newRedirectRecorder(apiInterface *entity.ApiInterface) *redirectRecorder {
+	recorder := &redirectRecorder{
+		follow:       true,
+		maxRedirects: defaultMaxRedirects,
+	}
+	if apiInterface.FollowRedirects != nil {
+		recorder.follow = *apiInterface.FollowRedirects
+	}
+	if apiInterface.MaxRedirects != nil && *apiInterface.MaxRedirects >= 0 {
+		recorder.maxRedirects = *apiInterface.MaxRedirects
+	}
+	return recorder
+}
+
+// policy 返回resty重定向策略
+// 不跟随或超过最大次数时返回最后一次的3xx响应，而不是错误，便于查看重定向链
+func (r *redirectRecorder) policy() resty.RedirectPolicy {
+	return resty.RedirectPolicyFunc(func(req *http.Request, via []*http.Request) error {
+		hop := dto.ApiRedirectHopDto{
+			URL:      via[len(via)-1].URL.String(),
+			Location: req.URL.String(),
+		}
+		if req.Response != nil {
+			hop.Status = req.Response.StatusCode
+		}
+		r.hops = append(r.hops, hop)
+
+		if !r.follow {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > r.maxRedirects {
+			r.exceeded = true
+			return http.ErrUseLastResponse
+		}
+		return nil
+	})
+}
+
+// reset 重试前清空上一次请求的重定向链
+func (r *redirectRecorder) reset() {
+	r.hops = nil
+	r.exceeded = false
+}
+
+// error 超过最大重定向次数时的错误信息
+func (r *redirectRecorder) error() error {
+	if r.exceeded {
+		return fmt.Errorf("重定向次数超过上限 %d，请检查是否存在重定向循环", r.maxRedirects)
+	}
+	return nil
+}
+
+// newHTTPTransport 根据接口配置的HTTP版本与传输层配置创建传输层
+func newHTTPTransport(apiInterface *entity.ApiInterface, settings *transportSettings) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if err := settings.apply(transport); err != nil {
+		return nil, err
+	}
+	if apiInterface.HttpVersion == nil {
+		return transport, nil
+	}
+
+	protocols := new(http.Protocols)
+	switch enums.HttpVersion(*apiInterface.HttpVersion) {
+	case enums.HttpVersionHTTP11:
+		protocols.SetHTTP1(true)
+	case enums.HttpVersionHTTP2:
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		return transport, nil
+	}
+	transport.Protocols = protocols
+	return transport, nil
+}
+
+// describeTransportError 将TLS握手与代理连接错误转换为便于排查的提示，保留原始错误信息
+func describeTransportError(err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var recordHeader tls.RecordHeaderError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return fmt.Errorf("TLS握手失败：服务端证书不受信任，请配置CA证书: %w", err)
+	case errors.As(err, &hostname):
+		return fmt.Errorf("TLS握手失败：服务端证书与主机名 %s 不匹配，请检查地址或配置SNI: %w", hostname.Host, err)
+	case errors.As(err, &invalid):
+		return fmt.Errorf("TLS握手失败：服务端证书无效（可能已过期或用途不符）: %w", err)
+	case errors.As(err, &recordHeader), strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		return fmt.Errorf("TLS握手失败：服务端未使用TLS，请检查协议与端口: %w", err)
+	case errors.As(err, &opErr) && opErr.Op == "remote error":
+		return fmt.Errorf("TLS握手失败：服务端拒绝握手，请检查客户端证书: %w", err)
+	case errors.As(err, &opErr) && (opErr.Op == "proxyconnect" || strings.HasPrefix(opErr.Op, "socks")):
+		return fmt.Errorf("连接代理失败，请检查代理地址与认证信息: %w", err)
+	}
+	return err
+}
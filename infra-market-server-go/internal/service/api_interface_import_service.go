@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/go-resty/resty/v2"
+)
+
+// importSourceTimeout 下载规范文件的超时时间
+const importSourceTimeout = 30 * time.Second
+
+// postmanExportDefaultName 导出集合的默认名称
+const postmanExportDefaultName = "接口集合"
+
+// ApiInterfaceImportService 接口导入导出服务
+type ApiInterfaceImportService struct {
+	apiInterfaceRepo    *repository.ApiInterfaceRepository
+	apiEnvironmentRepo  *repository.ApiEnvironmentRepository
+	apiInterfaceService *ApiInterfaceService
+	aclService          *ApiInterfaceAclService
+}
+
+func NewApiInterfaceImportService(
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	apiEnvironmentRepo *repository.ApiEnvironmentRepository,
+	apiInterfaceService *ApiInterfaceService,
+	aclService *ApiInterfaceAclService,
+) *ApiInterfaceImportService {
+	return &ApiInterfaceImportService{
+		apiInterfaceRepo:    apiInterfaceRepo,
+		apiEnvironmentRepo:  apiEnvironmentRepo,
+		apiInterfaceService: apiInterfaceService,
+		aclService:          aclService,
+	}
+}
+
+// ImportOpenAPI 从 OpenAPI 3 / Swagger 2 规范导入接口，uid 为导入人
+func (s *ApiInterfaceImportService) ImportOpenAPI(req dto.ApiInterfaceImportRequestDto, uid uint64) dto.ApiData[dto.ApiInterfaceImportResultDto] {
+	strategy, err := parseImportConflictStrategy(req.ConflictStrategy)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceImportResultDto](err.Error(), http.StatusBadRequest)
+	}
+
+	content, err := s.loadContent(req)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceImportResultDto](err.Error(), http.StatusBadRequest)
+	}
+
+	doc, err := parseOpenAPIDocument(content)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceImportResultDto](err.Error(), http.StatusBadRequest)
+	}
+
+	baseURL := ""
+	if req.BaseURL != nil {
+		baseURL = strings.TrimSpace(*req.BaseURL)
+	}
+	forms := doc.convertToForms(baseURL, req.EnvironmentID != nil)
+	if len(forms) == 0 {
+		return dto.Error[dto.ApiInterfaceImportResultDto]("规范中没有可导入的接口", http.StatusBadRequest)
+	}
+	for i := range forms {
+		forms[i].EnvironmentID = req.EnvironmentID
+	}
+
+	dryRun := req.DryRun != nil && *req.DryRun
+	return dto.Success(s.importForms(forms, strategy, dryRun, uid))
+}
+
+// ParseCurl 将 curl 命令解析为接口表单草稿，不写入数据库
+func (s *ApiInterfaceImportService) ParseCurl(req dto.ApiCurlImportRequestDto) dto.ApiData[dto.ApiInterfaceFormDto] {
+	form, err := parseCurlCommand(*req.Command)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceFormDto](err.Error(), http.StatusBadRequest)
+	}
+	return dto.Success(form)
+}
+
+// ImportPostman 从 Postman Collection v2.1 导入接口，uid 为导入人
+func (s *ApiInterfaceImportService) ImportPostman(req dto.ApiInterfaceImportRequestDto, uid uint64) dto.ApiData[dto.ApiInterfaceImportResultDto] {
+	strategy, err := parseImportConflictStrategy(req.ConflictStrategy)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceImportResultDto](err.Error(), http.StatusBadRequest)
+	}
+
+	content, err := s.loadContent(req)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceImportResultDto](err.Error(), http.StatusBadRequest)
+	}
+
+	collection, err := parsePostmanCollection(content)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceImportResultDto](err.Error(), http.StatusBadRequest)
+	}
+
+	forms := collection.convertToForms()
+	if len(forms) == 0 {
+		return dto.Error[dto.ApiInterfaceImportResultDto]("集合中没有可导入的请求", http.StatusBadRequest)
+	}
+	for i := range forms {
+		forms[i].EnvironmentID = req.EnvironmentID
+	}
+
+	dryRun := req.DryRun != nil && *req.DryRun
+	return dto.Success(s.importForms(forms, strategy, dryRun, uid))
+}
+
+// ExportPostman 将选中的接口导出为 Postman Collection v2.1，需要每个接口的查看权限
+func (s *ApiInterfaceImportService) ExportPostman(req dto.ApiPostmanExportRequestDto, uid uint64) dto.ApiData[[]byte] {
+	interfaces, err := s.apiInterfaceRepo.FindAllByIDs(req.InterfaceIDs)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询导出接口失败，接口ID: %v, 错误: %v\n", req.InterfaceIDs, err)
+		return dto.Error[[]byte]("查询接口失败", http.StatusInternalServerError)
+	}
+	if len(interfaces) == 0 {
+		return dto.Error[[]byte]("接口不存在", http.StatusNotFound)
+	}
+	for _, apiInterface := range interfaces {
+		if err := s.aclService.CheckPermission(apiInterface.ID, uid, enums.InterfacePermissionView); err != nil {
+			return dto.Error[[]byte](fmt.Sprintf("无权导出接口 %s", apiInterface.Name), http.StatusForbidden)
+		}
+	}
+
+	environmentIDs := make([]uint64, 0)
+	for _, apiInterface := range interfaces {
+		if apiInterface.EnvironmentID != nil {
+			environmentIDs = append(environmentIDs, *apiInterface.EnvironmentID)
+		}
+	}
+	environments := make(map[uint64]entity.ApiEnvironment)
+	if len(environmentIDs) > 0 {
+		list, err := s.apiEnvironmentRepo.FindByIDs(environmentIDs)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "查询接口环境失败，环境ID: %v, 错误: %v\n", environmentIDs, err)
+			return dto.Error[[]byte]("查询环境失败", http.StatusInternalServerError)
+		}
+		for _, environment := range list {
+			environments[environment.ID] = environment
+		}
+	}
+
+	name := postmanExportDefaultName
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		name = strings.TrimSpace(*req.Name)
+	}
+	data, err := json.MarshalIndent(buildPostmanCollection(name, interfaces, environments), "", "  ")
+	if err != nil {
+		return dto.Error[[]byte]("生成集合文件失败", http.StatusInternalServerError)
+	}
+	return dto.Success(data)
+}
+
+// loadContent 获取规范文件内容：优先使用请求中的内容，否则从地址下载
+func (s *ApiInterfaceImportService) loadContent(req dto.ApiInterfaceImportRequestDto) ([]byte, error) {
+	if req.Content != nil && strings.TrimSpace(*req.Content) != "" {
+		return []byte(*req.Content), nil
+	}
+	if req.SourceURL == nil || strings.TrimSpace(*req.SourceURL) == "" {
+		return nil, fmt.Errorf("请提供规范文件内容或地址")
+	}
+
+	sourceURL := strings.TrimSpace(*req.SourceURL)
+	if !isAbsoluteURL(sourceURL) {
+		return nil, fmt.Errorf("规范文件地址必须以 http:// 或 https:// 开头")
+	}
+	resp, err := resty.New().SetTimeout(importSourceTimeout).R().Get(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("下载规范文件失败: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("下载规范文件失败，状态码: %d", resp.StatusCode())
+	}
+	return resp.Body(), nil
+}
+
+// importForms 按冲突策略逐个导入接口表单，dryRun 时只计算导入结果不写入数据库
+// 方法与URL均相同的已有接口视为冲突
+func (s *ApiInterfaceImportService) importForms(forms []dto.ApiInterfaceFormDto, strategy enums.ImportConflictStrategy, dryRun bool, uid uint64) dto.ApiInterfaceImportResultDto {
+	result := dto.ApiInterfaceImportResultDto{
+		DryRun: dryRun,
+		Total:  len(forms),
+		Items:  make([]dto.ApiInterfaceImportItemDto, 0, len(forms)),
+	}
+	// 本次导入中已占用的名称，避免重命名时彼此冲突
+	reservedNames := make(map[string]bool)
+
+	for _, form := range forms {
+		item := s.importForm(form, strategy, dryRun, reservedNames, uid)
+		switch enums.ImportAction(item.Action) {
+		case enums.ImportActionCreate:
+			result.Created++
+		case enums.ImportActionOverwrite:
+			result.Overwritten++
+		case enums.ImportActionRename:
+			result.Renamed++
+		case enums.ImportActionSkip:
+			result.Skipped++
+		case enums.ImportActionFailed:
+			result.Failed++
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result
+}
+
+// importForm 导入单个接口表单
+func (s *ApiInterfaceImportService) importForm(form dto.ApiInterfaceFormDto, strategy enums.ImportConflictStrategy, dryRun bool, reservedNames map[string]bool, uid uint64) dto.ApiInterfaceImportItemDto {
+	item := dto.ApiInterfaceImportItemDto{
+		Name:      stringValue(form.Name),
+		Method:    stringValue(form.Method),
+		URL:       stringValue(form.URL),
+		Action:    enums.ImportActionCreate.Code(),
+		Interface: form,
+	}
+	fail := func(message string) dto.ApiInterfaceImportItemDto {
+		item.Action = enums.ImportActionFailed.Code()
+		item.Error = basic.Ptr(message)
+		return item
+	}
+
+	if err := s.apiInterfaceService.validateForm(&form, nil, uid); err != nil {
+		return fail(err.Error())
+	}
+
+	existing, err := s.apiInterfaceRepo.FindByMethodAndURL(item.Method, item.URL)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询已有接口失败，方法: %s, URL: %s, 错误: %v\n", item.Method, item.URL, err)
+		return fail("查询已有接口失败")
+	}
+	if len(existing) > 0 {
+		item.ExistingID = basic.Ptr(existing[0].ID)
+		switch strategy {
+		case enums.ImportConflictSkip:
+			item.Action = enums.ImportActionSkip.Code()
+			return item
+		case enums.ImportConflictOverwrite:
+			item.Action = enums.ImportActionOverwrite.Code()
+		case enums.ImportConflictRename:
+			name, err := s.uniqueName(item.Name, reservedNames)
+			if err != nil {
+				return fail("生成接口名称失败")
+			}
+			item.Action = enums.ImportActionRename.Code()
+			item.Name = name
+			form.Name = basic.Ptr(name)
+		}
+	}
+	reservedNames[item.Name] = true
+	item.Interface = form
+
+	if dryRun {
+		return item
+	}
+
+	// 导入的接口不包含传输层配置，以导入人身份保存，版本记录中的保存人为导入人
+	var saved dto.ApiData[dto.ApiInterfaceDto]
+	if item.Action == enums.ImportActionOverwrite.Code() {
+		saved = s.apiInterfaceService.Update(*item.ExistingID, form, uid)
+	} else {
+		saved = s.apiInterfaceService.Save(form, uid)
+	}
+	if saved.Code != http.StatusOK {
+		return fail(saved.Message)
+	}
+	item.InterfaceID = saved.Data.ID
+	return item
+}
+
+// uniqueName 生成不与已有接口重复的名称
+func (s *ApiInterfaceImportService) uniqueName(name string, reservedNames map[string]bool) (string, error) {
+	for i := 1; ; i++ {
+		candidate := name + "_导入"
+		if i > 1 {
+			candidate = fmt.Sprintf("%s_导入%d", name, i)
+		}
+		if reservedNames[candidate] {
+			continue
+		}
+		exists, err := s.apiInterfaceRepo.ExistsByName(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+}
+
+// parseImportConflictStrategy 解析冲突策略，默认跳过
+func parseImportConflictStrategy(code *string) (enums.ImportConflictStrategy, error) {
+	if code == nil || *code == "" {
+		return enums.ImportConflictSkip, nil
+	}
+	strategy := enums.ImportConflictStrategyFromCode(strings.ToUpper(*code))
+	if strategy == nil {
+		return "", fmt.Errorf("不支持的冲突策略: %s", *code)
+	}
+	return *strategy, nil
+}
+
+// stringValue 字符串指针取值，nil 时返回空字符串
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
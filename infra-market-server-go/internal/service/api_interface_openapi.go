@@ -0,0 +1,582 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/goccy/go-yaml"
+)
+
+// openAPIMaxRefDepth 解析 $ref 与生成示例时的最大嵌套深度，防止循环引用
+const openAPIMaxRefDepth = 16
+
+// openAPIMethods 规范中可出现的操作方法（按导入顺序）
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// openAPIDocument 解析后的 OpenAPI 3 / Swagger 2 文档，保留原始结构以解析 $ref
+type openAPIDocument struct {
+	root    map[string]any
+	swagger bool // Swagger 2.0
+}
+
+// parseOpenAPIDocument 解析 JSON 或 YAML 格式的规范文件
+func parseOpenAPIDocument(content []byte) (*openAPIDocument, error) {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 {
+		return nil, fmt.Errorf("规范文件内容为空")
+	}
+	if content[0] != '{' {
+		jsonContent, err := yaml.YAMLToJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("解析YAML失败: %w", err)
+		}
+		content = jsonContent
+	}
+
+	var root map[string]any
+	if err := json.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("解析规范文件失败: %w", err)
+	}
+
+	doc := &openAPIDocument{root: root}
+	if version := openAPIString(root, "openapi"); strings.HasPrefix(version, "3.") {
+		return doc, nil
+	}
+	if version := openAPIString(root, "swagger"); strings.HasPrefix(version, "2.") {
+		doc.swagger = true
+		return doc, nil
+	}
+	return nil, fmt.Errorf("不支持的规范格式，仅支持 OpenAPI 3 与 Swagger 2")
+}
+
+// convertToForms 将规范中的每个操作转换为接口表单
+// baseURL 非空时覆盖规范声明的服务地址；relative 为 true 时只保留服务地址中的路径部分（由执行环境提供域名）
+func (d *openAPIDocument) convertToForms(baseURL string, relative bool) []dto.ApiInterfaceFormDto {
+	if baseURL == "" {
+		baseURL = d.serverURL()
+	}
+	if relative && isAbsoluteURL(baseURL) {
+		if parsed, err := url.Parse(baseURL); err == nil {
+			baseURL = parsed.Path
+		}
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	paths := d.resolve(d.root["paths"])
+	pathKeys := make([]string, 0, len(paths))
+	for path := range paths {
+		pathKeys = append(pathKeys, path)
+	}
+	sort.Strings(pathKeys)
+
+	forms := make([]dto.ApiInterfaceFormDto, 0)
+	for _, path := range pathKeys {
+		pathItem := d.resolve(paths[path])
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range openAPIMethods {
+			operation := d.resolve(pathItem[method])
+			if operation == nil {
+				continue
+			}
+			forms = append(forms, d.convertOperation(baseURL, path, strings.ToUpper(method), pathItem, operation))
+		}
+	}
+	return forms
+}
+
+// serverURL 规范中声明的服务地址
+func (d *openAPIDocument) serverURL() string {
+	if d.swagger {
+		host := openAPIString(d.root, "host")
+		basePath := openAPIString(d.root, "basePath")
+		if host == "" {
+			return basePath
+		}
+		scheme := "https"
+		if schemes, ok := d.root["schemes"].([]any); ok && len(schemes) > 0 {
+			scheme = fmt.Sprintf("%v", schemes[0])
+		}
+		return scheme + "://" + host + basePath
+	}
+
+	servers, ok := d.root["servers"].([]any)
+	if !ok || len(servers) == 0 {
+		return ""
+	}
+	server := d.resolve(servers[0])
+	serverURL := openAPIString(server, "url")
+	// 服务地址变量使用默认值替换
+	variables := d.resolve(server["variables"])
+	for name, variable := range variables {
+		if value := openAPIString(d.resolve(variable), "default"); value != "" {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", value)
+		}
+	}
+	return serverURL
+}
+
+// convertOperation 将单个操作转换为接口表单
+func (d *openAPIDocument) convertOperation(baseURL, path, method string, pathItem, operation map[string]any) dto.ApiInterfaceFormDto {
+	name := openAPIString(operation, "summary")
+	if name == "" {
+		name = openAPIString(operation, "operationId")
+	}
+	if name == "" {
+		name = method + " " + path
+	}
+	description := openAPIString(operation, "description")
+	if description == "" {
+		description = openAPIString(operation, "summary")
+	}
+
+	form := dto.ApiInterfaceFormDto{
+		Name:         basic.Ptr(name),
+		Method:       basic.Ptr(method),
+		URL:          basic.Ptr(baseURL + path),
+		URLParams:    make([]dto.ApiParamDto, 0),
+		PathParams:   make([]dto.ApiParamDto, 0),
+		HeaderParams: make([]dto.ApiParamDto, 0),
+		BodyParams:   make([]dto.ApiParamDto, 0),
+	}
+	if description != "" {
+		form.Description = basic.Ptr(description)
+	}
+
+	var bodySchema map[string]any
+	formDataFile := false
+	for _, param := range d.mergeParameters(pathItem["parameters"], operation["parameters"]) {
+		switch openAPIString(param, "in") {
+		case "query":
+			form.URLParams = append(form.URLParams, d.convertParameter(param, enums.ParamTypeURL, len(form.URLParams)))
+		case "path":
+			form.PathParams = append(form.PathParams, d.convertParameter(param, enums.ParamTypePath, len(form.PathParams)))
+		case "header":
+			form.HeaderParams = append(form.HeaderParams, d.convertParameter(param, enums.ParamTypeHeader, len(form.HeaderParams)))
+		case "formData":
+			// Swagger 2 表单参数
+			if openAPIString(param, "type") == "file" {
+				formDataFile = true
+			}
+			form.BodyParams = append(form.BodyParams, d.convertParameter(param, enums.ParamTypeBody, len(form.BodyParams)))
+		case "body":
+			// Swagger 2 请求体参数
+			bodySchema = d.resolve(param["schema"])
+		}
+	}
+
+	if d.swagger {
+		consumes := d.consumes(operation)
+		if len(form.BodyParams) > 0 {
+			postType := enums.PostTypeApplicationXWWWFormURLEncoded
+			if formDataFile || containsString(consumes, enums.PostTypeMultipartFormData.Code()) {
+				postType = enums.PostTypeMultipartFormData
+			}
+			form.PostType = basic.Ptr(postType.Code())
+			form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+		} else if bodySchema != nil {
+			mediaType := enums.PostTypeApplicationJSON.Code()
+			if len(consumes) > 0 {
+				mediaType = consumes[0]
+			}
+			d.applyRequestBody(&form, mediaType, bodySchema, nil)
+		}
+		return form
+	}
+
+	requestBody := d.resolve(operation["requestBody"])
+	if content := d.resolve(requestBody["content"]); len(content) > 0 {
+		mediaType := selectOpenAPIMediaType(content)
+		media := d.resolve(content[mediaType])
+		d.applyRequestBody(&form, mediaType, d.resolve(media["schema"]), media["example"])
+	}
+	return form
+}
+
+// applyRequestBody 根据请求体结构设置表单：对象结构拆分为Body参数，其余使用RAW模式的示例请求体
+func (d *openAPIDocument) applyRequestBody(form *dto.ApiInterfaceFormDto, mediaType string, schema map[string]any, example any) {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	postType := enums.PostTypeFromCode(mediaType)
+	if postType == nil && strings.HasSuffix(mediaType, "+json") {
+		postType = basic.Ptr(enums.PostTypeApplicationJSON)
+	}
+	if postType == nil {
+		postType = basic.Ptr(enums.PostTypeTextPlain)
+	}
+	form.PostType = basic.Ptr(postType.Code())
+
+	properties, required := d.schemaProperties(schema, 0)
+	isForm := *postType == enums.PostTypeApplicationXWWWFormURLEncoded || *postType == enums.PostTypeMultipartFormData
+	if len(properties) > 0 && (isForm || *postType == enums.PostTypeApplicationJSON) {
+		names := make([]string, 0, len(properties))
+		for propertyName := range properties {
+			names = append(names, propertyName)
+		}
+		sort.Strings(names)
+		for _, propertyName := range names {
+			propertySchema := d.resolve(properties[propertyName])
+			param := d.paramFromSchema(propertyName, propertySchema, required[propertyName], enums.ParamTypeBody, len(form.BodyParams))
+			form.BodyParams = append(form.BodyParams, param)
+		}
+		form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+		return
+	}
+	if isForm {
+		form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+		return
+	}
+
+	// 非对象结构或非表单类型：生成示例请求体
+	if example == nil {
+		example = d.exampleFromSchema(schema, 0)
+	}
+	rawBody := ""
+	switch value := example.(type) {
+	case nil:
+	case string:
+		rawBody = value
+	default:
+		if *postType == enums.PostTypeApplicationJSON {
+			if jsonBytes, err := json.MarshalIndent(value, "", "  "); err == nil {
+				rawBody = string(jsonBytes)
+			}
+		}
+	}
+	form.BodyMode = basic.Ptr(enums.BodyModeRaw.Code())
+	form.RawBody = basic.Ptr(rawBody)
+	form.RawDataType = basic.Ptr(postType.DataType().Code())
+}
+
+// convertParameter 将 parameters 中的单个参数转换为接口参数
+func (d *openAPIDocument) convertParameter(param map[string]any, paramType enums.ParamType, sortIndex int) dto.ApiParamDto {
+	schema := d.resolve(param["schema"])
+	if d.swagger || schema == nil {
+		// Swagger 2 的非 body 参数直接在参数上声明类型
+		schema = param
+	}
+	required, _ := param["required"].(bool)
+	result := d.paramFromSchema(openAPIString(param, "name"), schema, required, paramType, sortIndex)
+	if description := openAPIString(param, "description"); description != "" {
+		result.Description = basic.Ptr(description)
+	}
+	if result.DefaultValue == nil && param["example"] != nil {
+		result.DefaultValue = param["example"]
+	}
+	return result
+}
+
+// paramFromSchema 根据结构定义生成接口参数
+func (d *openAPIDocument) paramFromSchema(name string, schema map[string]any, required bool, paramType enums.ParamType, sortIndex int) dto.ApiParamDto {
+	param := dto.ApiParamDto{
+		Name:       basic.Ptr(name),
+		ParamType:  basic.Ptr(paramType.Code()),
+		InputType:  basic.Ptr(d.inputType(schema).Code()),
+		DataType:   basic.Ptr(d.dataType(schema).Code()),
+		Required:   basic.Ptr(required),
+		Changeable: basic.Ptr(true),
+		Sort:       basic.Ptr(sortIndex),
+	}
+	if title := openAPIString(schema, "title"); title != "" {
+		param.ChineseName = basic.Ptr(title)
+	}
+	if description := openAPIString(schema, "description"); description != "" {
+		param.Description = basic.Ptr(description)
+	}
+	if value, ok := schema["default"]; ok {
+		param.DefaultValue = value
+	}
+
+	enumValues, _ := schema["enum"].([]any)
+	if items := d.resolve(schema["items"]); len(enumValues) == 0 && items != nil {
+		enumValues, _ = items["enum"].([]any)
+	}
+	if len(enumValues) > 0 {
+		param.Options = make([]dto.SelectOptionDto, 0, len(enumValues))
+		for _, value := range enumValues {
+			text := fmt.Sprintf("%v", value)
+			param.Options = append(param.Options, dto.SelectOptionDto{Value: basic.Ptr(text), Label: basic.Ptr(text)})
+		}
+	}
+	return param
+}
+
+// dataType 结构定义对应的参数数据类型
+func (d *openAPIDocument) dataType(schema map[string]any) enums.DataType {
+	switch openAPISchemaType(schema) {
+	case "integer":
+		if openAPIString(schema, "format") == "int64" {
+			return enums.DataTypeLONG
+		}
+		return enums.DataTypeINTEGER
+	case "number":
+		return enums.DataTypeDOUBLE
+	case "boolean":
+		return enums.DataTypeBOOLEAN
+	case "array":
+		return enums.DataTypeARRAY
+	case "object":
+		return enums.DataTypeJSONObject
+	case "string":
+		switch openAPIString(schema, "format") {
+		case "date":
+			return enums.DataTypeDATE
+		case "date-time":
+			return enums.DataTypeDATETIME
+		}
+	}
+	return enums.DataTypeSTRING
+}
+
+// inputType 结构定义对应的参数输入类型
+func (d *openAPIDocument) inputType(schema map[string]any) enums.InputType {
+	schemaType := openAPISchemaType(schema)
+	if enumValues, ok := schema["enum"].([]any); ok && len(enumValues) > 0 {
+		return enums.InputTypeSELECT
+	}
+	if schemaType == "array" {
+		if items := d.resolve(schema["items"]); items != nil {
+			if enumValues, ok := items["enum"].([]any); ok && len(enumValues) > 0 {
+				return enums.InputTypeMULTISELECT
+			}
+		}
+		return enums.InputTypeTEXTAREA
+	}
+	switch schemaType {
+	case "file":
+		return enums.InputTypeFILE
+	case "integer", "number":
+		return enums.InputTypeNUMBER
+	case "object":
+		return enums.InputTypeTEXTAREA
+	}
+	switch openAPIString(schema, "format") {
+	case "password":
+		return enums.InputTypePASSWORD
+	case "email":
+		return enums.InputTypeEMAIL
+	case "uri", "url":
+		return enums.InputTypeURL
+	case "binary":
+		return enums.InputTypeFILE
+	case "date":
+		return enums.InputTypeDATE
+	case "date-time":
+		return enums.InputTypeDATETIME
+	}
+	return enums.InputTypeTEXT
+}
+
+// schemaProperties 收集对象结构的属性及必填属性（合并 allOf）
+func (d *openAPIDocument) schemaProperties(schema map[string]any, depth int) (map[string]any, map[string]bool) {
+	properties := make(map[string]any)
+	required := make(map[string]bool)
+	if schema == nil || depth > openAPIMaxRefDepth {
+		return properties, required
+	}
+	for _, part := range openAPIList(schema["allOf"]) {
+		partProperties, partRequired := d.schemaProperties(d.resolve(part), depth+1)
+		for k, v := range partProperties {
+			properties[k] = v
+		}
+		for k := range partRequired {
+			required[k] = true
+		}
+	}
+	for k, v := range d.resolve(schema["properties"]) {
+		properties[k] = v
+	}
+	for _, name := range openAPIList(schema["required"]) {
+		required[fmt.Sprintf("%v", name)] = true
+	}
+	return properties, required
+}
+
+// exampleFromSchema 根据结构定义生成示例值
+func (d *openAPIDocument) exampleFromSchema(schema map[string]any, depth int) any {
+	if schema == nil || depth > openAPIMaxRefDepth {
+		return nil
+	}
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if enumValues := openAPIList(schema["enum"]); len(enumValues) > 0 {
+		return enumValues[0]
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if variants := openAPIList(schema[key]); len(variants) > 0 {
+			return d.exampleFromSchema(d.resolve(variants[0]), depth+1)
+		}
+	}
+
+	switch openAPISchemaType(schema) {
+	case "object":
+		properties, _ := d.schemaProperties(schema, depth)
+		result := make(map[string]any, len(properties))
+		for name, property := range properties {
+			result[name] = d.exampleFromSchema(d.resolve(property), depth+1)
+		}
+		return result
+	case "array":
+		item := d.exampleFromSchema(d.resolve(schema["items"]), depth+1)
+		if item == nil {
+			return []any{}
+		}
+		return []any{item}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		return ""
+	}
+	return nil
+}
+
+// mergeParameters 合并路径级与操作级参数，操作级参数按 name+in 覆盖路径级参数
+func (d *openAPIDocument) mergeParameters(pathParams, operationParams any) []map[string]any {
+	merged := make([]map[string]any, 0)
+	index := make(map[string]int)
+	for _, list := range []any{pathParams, operationParams} {
+		for _, item := range openAPIList(list) {
+			param := d.resolve(item)
+			if param == nil {
+				continue
+			}
+			key := openAPIString(param, "in") + ":" + openAPIString(param, "name")
+			if i, ok := index[key]; ok {
+				merged[i] = param
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, param)
+		}
+	}
+	return merged
+}
+
+// consumes Swagger 2 操作可接受的请求体类型
+func (d *openAPIDocument) consumes(operation map[string]any) []string {
+	list := openAPIList(operation["consumes"])
+	if len(list) == 0 {
+		list = openAPIList(d.root["consumes"])
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		result = append(result, fmt.Sprintf("%v", item))
+	}
+	return result
+}
+
+// resolve 将节点转换为对象并解析本地 $ref 引用（#/components/...、#/definitions/...）
+func (d *openAPIDocument) resolve(node any) map[string]any {
+	for depth := 0; depth < openAPIMaxRefDepth; depth++ {
+		value, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		ref, ok := value["$ref"].(string)
+		if !ok {
+			return value
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil
+		}
+		var current any = d.root
+		for _, segment := range strings.Split(ref[2:], "/") {
+			segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil
+			}
+			current = object[segment]
+		}
+		node = current
+	}
+	return nil
+}
+
+// selectOpenAPIMediaType 从请求体内容类型中选择最合适的一种
+func selectOpenAPIMediaType(content map[string]any) string {
+	preferred := []string{
+		enums.PostTypeApplicationJSON.Code(),
+		enums.PostTypeApplicationXWWWFormURLEncoded.Code(),
+		enums.PostTypeMultipartFormData.Code(),
+		enums.PostTypeApplicationXML.Code(),
+		enums.PostTypeTextXML.Code(),
+		enums.PostTypeTextPlain.Code(),
+	}
+	for _, mediaType := range preferred {
+		if _, ok := content[mediaType]; ok {
+			return mediaType
+		}
+	}
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		if strings.HasSuffix(mediaType, "+json") {
+			return mediaType
+		}
+	}
+	return mediaTypes[0]
+}
+
+// openAPISchemaType 结构定义的类型，未声明类型但含属性时视为对象
+func openAPISchemaType(schema map[string]any) string {
+	switch value := schema["type"].(type) {
+	case string:
+		return value
+	case []any:
+		// OpenAPI 3.1 允许类型数组，取第一个非 null 类型
+		for _, item := range value {
+			if text, ok := item.(string); ok && text != "null" {
+				return text
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["allOf"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+// openAPIString 读取对象中的字符串字段
+func openAPIString(object map[string]any, key string) string {
+	if object == nil {
+		return ""
+	}
+	value, _ := object[key].(string)
+	return value
+}
+
+// openAPIList 读取数组节点
+func openAPIList(node any) []any {
+	list, _ := node.([]any)
+	return list
+}
+
+// containsString 判断字符串切片是否包含指定值
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
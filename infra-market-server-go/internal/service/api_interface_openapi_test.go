@@ -0,0 +1,195 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+const testOpenAPI3Spec = `
+openapi: 3.0.1
+servers:
+  - url: https://{region}.example.com/v1
+    variables:
+      region:
+        default: cn
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      summary: 查询用户
+      parameters:
+        - name: verbose
+          in: query
+          schema:
+            type: boolean
+            default: false
+        - name: X-Trace-Id
+          in: header
+          schema:
+            type: string
+    put:
+      operationId: updateUser
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        role:
+          type: string
+          enum: [admin, guest]
+`
+
+func TestParseOpenAPIDocumentOpenAPI3(t *testing.T) {
+	doc, err := parseOpenAPIDocument([]byte(testOpenAPI3Spec))
+	if err != nil {
+		t.Fatalf("parseOpenAPIDocument() error = %v", err)
+	}
+	forms := doc.convertToForms("", false)
+	if len(forms) != 2 {
+		t.Fatalf("convertToForms() 返回 %d 个接口, want 2", len(forms))
+	}
+
+	get := forms[0]
+	if *get.Method != "GET" || *get.Name != "查询用户" || *get.URL != "https://cn.example.com/v1/users/{id}" {
+		t.Errorf("GET 接口 = %s %s %s", *get.Method, *get.Name, *get.URL)
+	}
+	if len(get.PathParams) != 1 || *get.PathParams[0].Name != "id" || !*get.PathParams[0].Required {
+		t.Errorf("路径参数 = %+v", get.PathParams)
+	}
+	if len(get.URLParams) != 1 || *get.URLParams[0].Name != "verbose" || get.URLParams[0].DefaultValue != false {
+		t.Errorf("查询参数 = %+v", get.URLParams)
+	}
+	if len(get.HeaderParams) != 1 || *get.HeaderParams[0].Name != "X-Trace-Id" {
+		t.Errorf("请求头参数 = %+v", get.HeaderParams)
+	}
+
+	put := forms[1]
+	if *put.Name != "updateUser" || *put.PostType != enums.PostTypeApplicationJSON.Code() || *put.BodyMode != enums.BodyModeParams.Code() {
+		t.Errorf("PUT 接口 = %s %v %v", *put.Name, *put.PostType, *put.BodyMode)
+	}
+	params := paramsByName(put.BodyParams)
+	if name, ok := params["name"]; !ok || !*name.Required {
+		t.Errorf("Body参数 name = %+v", name)
+	}
+	if role, ok := params["role"]; !ok || *role.Required || len(role.Options) != 2 {
+		t.Errorf("Body参数 role = %+v", role)
+	}
+}
+
+func TestParseOpenAPIDocumentSwagger2(t *testing.T) {
+	spec := `{
+		"swagger": "2.0",
+		"host": "api.example.com",
+		"basePath": "/v2",
+		"schemes": ["http"],
+		"paths": {
+			"/files": {
+				"post": {
+					"parameters": [
+						{"name": "file", "in": "formData", "type": "file", "required": true},
+						{"name": "tag", "in": "formData", "type": "string"}
+					]
+				}
+			},
+			"/orders": {
+				"post": {
+					"consumes": ["text/plain"],
+					"parameters": [{"name": "body", "in": "body", "schema": {"type": "string", "example": "hello"}}]
+				}
+			}
+		}
+	}`
+	doc, err := parseOpenAPIDocument([]byte(spec))
+	if err != nil {
+		t.Fatalf("parseOpenAPIDocument() error = %v", err)
+	}
+
+	forms := doc.convertToForms("", false)
+	if len(forms) != 2 {
+		t.Fatalf("convertToForms() 返回 %d 个接口, want 2", len(forms))
+	}
+	upload := forms[0]
+	if *upload.URL != "http://api.example.com/v2/files" || *upload.Name != "POST /files" {
+		t.Errorf("上传接口 = %s %s", *upload.Name, *upload.URL)
+	}
+	if *upload.PostType != enums.PostTypeMultipartFormData.Code() || len(upload.BodyParams) != 2 {
+		t.Errorf("上传接口请求体 = %v %+v", *upload.PostType, upload.BodyParams)
+	}
+	order := forms[1]
+	if *order.BodyMode != enums.BodyModeRaw.Code() || *order.RawBody != "hello" {
+		t.Errorf("RAW 请求体 = %v %v", *order.BodyMode, order.RawBody)
+	}
+
+	relative := doc.convertToForms("", true)
+	if *relative[0].URL != "/v2/files" {
+		t.Errorf("相对地址 = %s, want /v2/files", *relative[0].URL)
+	}
+	override := doc.convertToForms("https://gateway.example.com/", false)
+	if *override[0].URL != "https://gateway.example.com/files" {
+		t.Errorf("覆盖服务地址 = %s", *override[0].URL)
+	}
+}
+
+func TestParseOpenAPIDocumentCircularRef(t *testing.T) {
+	spec := `{
+		"openapi": "3.0.0",
+		"paths": {
+			"/nodes": {
+				"post": {
+					"requestBody": {"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}}
+				}
+			}
+		},
+		"components": {"schemas": {"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}}}
+	}`
+	doc, err := parseOpenAPIDocument([]byte(spec))
+	if err != nil {
+		t.Fatalf("parseOpenAPIDocument() error = %v", err)
+	}
+	forms := doc.convertToForms("", false)
+	if len(forms) != 1 || *forms[0].BodyMode != enums.BodyModeRaw.Code() || forms[0].RawBody == nil {
+		t.Fatalf("循环引用的请求体应生成示例: %+v", forms)
+	}
+}
+
+func TestParseOpenAPIDocumentInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"空内容", "  "},
+		{"不支持的版本", `{"swagger": "1.2"}`},
+		{"格式错误", `{"openapi":`},
+		{"YAML错误", "openapi: [3.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseOpenAPIDocument([]byte(tt.content)); err == nil {
+				t.Error("parseOpenAPIDocument() 应返回错误")
+			}
+		})
+	}
+}
+
+func paramsByName(params []dto.ApiParamDto) map[string]dto.ApiParamDto {
+	result := make(map[string]dto.ApiParamDto, len(params))
+	for _, param := range params {
+		result[*param.Name] = param
+	}
+	return result
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+const (
+	// paramDateFormat DATE 类型参数发送时的格式
+	paramDateFormat = "2006-01-02"
+	// paramDateTimeFormat DATETIME 类型参数发送时的格式
+	paramDateTimeFormat = "2006-01-02 15:04:05"
+)
+
+// paramDateLayouts DATE 类型参数可解析的格式
+var paramDateLayouts = []string{"2006-01-02", "2006/01/02", "20060102", time.RFC3339}
+
+// paramDateTimeLayouts DATETIME 类型参数可解析的格式
+var paramDateTimeLayouts = []string{
+	"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006/01/02 15:04:05",
+	"2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339,
+}
+
+// paramTypeLabels 参数类型在错误信息中的名称
+var paramTypeLabels = map[enums.ParamType]string{
+	enums.ParamTypeURL:    "URL参数",
+	enums.ParamTypePath:   "Path参数",
+	enums.ParamTypeHeader: "Header参数",
+	enums.ParamTypeBody:   "Body参数",
+}
+
+// coerceParams 按参数定义的数据类型转换并校验请求参数，转换后的值原地写回请求
+// 规则：必填参数不能为空；数值、布尔、日期按类型解析并统一格式；数组可传JSON数组或逗号分隔的字符串；
+// 单选、多选参数的取值必须在可选项中；RAW请求体模式不校验Body参数。返回全部校验错误
+func coerceParams(params []dto.ApiParamDto, rawBody bool, req *dto.ApiExecuteRequestDto) []dto.ApiParamErrorDto {
+	paramErrors := make([]dto.ApiParamErrorDto, 0)
+	for _, param := range params {
+		if param.Name == nil || param.ParamType == nil || (rawBody && *param.ParamType == enums.ParamTypeBody.Code()) {
+			continue
+		}
+		name := *param.Name
+		paramType := enums.ParamType(*param.ParamType)
+		required := param.Required != nil && *param.Required
+		if isFileParam(param) {
+			if required && !hasUploadFile(req.Files, name) {
+				paramErrors = append(paramErrors, newParamError(param, fmt.Sprintf("Body参数 %s 为必填项，请上传文件", name)))
+			}
+			continue
+		}
+
+		value, exists := lookupParamValue(req, paramType, name)
+		if !exists || isEmptyParamValue(value) {
+			if required {
+				paramErrors = append(paramErrors, newParamError(param, fmt.Sprintf("%s %s 为必填项，不能为空", paramTypeLabels[paramType], name)))
+			}
+			continue
+		}
+
+		coerced, message := coerceParamValue(param, value)
+		if message != "" {
+			paramErrors = append(paramErrors, newParamError(param, message))
+			continue
+		}
+		storeParamValue(req, paramType, name, coerced)
+	}
+	return paramErrors
+}
+
+// applyParamDefaults 为未传入的参数填充默认值，并校验不可修改的参数，返回参数取值的来源
+// 规则：Changeable 为 false 的参数固定使用默认值，传入与默认值不同的取值时返回错误；
+// 空字符串视为未传入；RAW请求体模式的Body参数与文件参数不处理
+func applyParamDefaults(params []dto.ApiParamDto, rawBody bool, req *dto.ApiExecuteRequestDto) ([]dto.ApiParamSourceDto, []dto.ApiParamErrorDto) {
+	sources := make([]dto.ApiParamSourceDto, 0)
+	paramErrors := make([]dto.ApiParamErrorDto, 0)
+	for _, param := range params {
+		if param.Name == nil || param.ParamType == nil || isFileParam(param) || (rawBody && *param.ParamType == enums.ParamTypeBody.Code()) {
+			continue
+		}
+		name := *param.Name
+		paramType := enums.ParamType(*param.ParamType)
+		if paramTypeLabels[paramType] == "" {
+			continue
+		}
+
+		value, exists := lookupParamValue(req, paramType, name)
+		supplied := exists && !isEmptyParamValue(value)
+		hasDefault := !isEmptyParamValue(param.DefaultValue)
+		source := dto.ApiParamSourceDto{Name: name, ParamType: paramType.Code()}
+		switch {
+		case param.Changeable != nil && !*param.Changeable:
+			if supplied && (!hasDefault || !sameParamValue(param, value, param.DefaultValue)) {
+				paramErrors = append(paramErrors, newParamError(param, fmt.Sprintf("参数 '%s' 不允许修改", name)))
+				continue
+			}
+			if !hasDefault {
+				continue
+			}
+			storeParamValue(req, paramType, name, param.DefaultValue)
+			source.Source = enums.ParamSourceLocked.Code()
+		case supplied:
+			source.Source = enums.ParamSourceUser.Code()
+		case hasDefault:
+			storeParamValue(req, paramType, name, param.DefaultValue)
+			source.Source = enums.ParamSourceDefault.Code()
+		default:
+			continue
+		}
+		sources = append(sources, source)
+	}
+	return sources, paramErrors
+}
+
+// sameParamValue 判断传入值与默认值是否相同，按参数的数据类型转换后比较
+func sameParamValue(param dto.ApiParamDto, value, defaultValue any) bool {
+	if coerced, message := coerceParamValue(param, value); message == "" {
+		value = coerced
+	}
+	if coerced, message := coerceParamValue(param, defaultValue); message == "" {
+		defaultValue = coerced
+	}
+	return formatParamValue(value) == formatParamValue(defaultValue)
+}
+
+// newParamError 创建参数校验错误
+func newParamError(param dto.ApiParamDto, message string) dto.ApiParamErrorDto {
+	return dto.ApiParamErrorDto{
+		Name:      *param.Name,
+		ParamType: *param.ParamType,
+		DataType:  paramDataType(param).Code(),
+		Message:   message,
+	}
+}
+
+// isFileParam 判断是否为文件上传参数
+func isFileParam(param dto.ApiParamDto) bool {
+	return param.InputType != nil && *param.InputType == enums.InputTypeFILE.Code()
+}
+
+// lookupParamValue 按参数类型读取请求中的参数值
+func lookupParamValue(req *dto.ApiExecuteRequestDto, paramType enums.ParamType, name string) (any, bool) {
+	switch paramType {
+	case enums.ParamTypeURL:
+		value, ok := req.URLParams[name]
+		return value, ok
+	case enums.ParamTypePath:
+		value, ok := req.PathParams[name]
+		return value, ok
+	case enums.ParamTypeHeader:
+		value, ok := req.Headers[name]
+		return value, ok
+	case enums.ParamTypeBody:
+		value, ok := req.BodyParams[name]
+		return value, ok
+	}
+	return nil, false
+}
+
+// storeParamValue 按参数类型写入请求参数，Header参数的数组取值以逗号拼接
+func storeParamValue(req *dto.ApiExecuteRequestDto, paramType enums.ParamType, name string, value any) {
+	switch paramType {
+	case enums.ParamTypeURL:
+		if req.URLParams == nil {
+			req.URLParams = make(map[string]any)
+		}
+		req.URLParams[name] = value
+	case enums.ParamTypePath:
+		if req.PathParams == nil {
+			req.PathParams = make(map[string]any)
+		}
+		req.PathParams[name] = value
+	case enums.ParamTypeHeader:
+		if req.Headers == nil {
+			req.Headers = make(map[string]string)
+		}
+		req.Headers[name] = strings.Join(paramValues(value), ",")
+	case enums.ParamTypeBody:
+		if req.BodyParams == nil {
+			req.BodyParams = make(map[string]any)
+		}
+		req.BodyParams[name] = value
+	}
+}
+
+// paramErrorMessage 将参数校验错误合并为一条错误信息
+func paramErrorMessage(paramErrors []dto.ApiParamErrorDto) string {
+	messages := make([]string, 0, len(paramErrors))
+	for _, paramError := range paramErrors {
+		messages = append(messages, paramError.Message)
+	}
+	return strings.Join(messages, "；")
+}
+
+// paramDataType 参数的数据类型，未配置时为 STRING
+func paramDataType(param dto.ApiParamDto) enums.DataType {
+	if param.DataType == nil || *param.DataType == "" {
+		return enums.DataTypeSTRING
+	}
+	return enums.DataType(*param.DataType)
+}
+
+// coerceParamValue 转换单个参数值，失败时返回错误信息
+func coerceParamValue(param dto.ApiParamDto, value any) (any, string) {
+	name := *param.Name
+	dataType := paramDataType(param)
+	inputType := ""
+	if param.InputType != nil {
+		inputType = *param.InputType
+	}
+
+	// 多选参数按数组处理，数据类型作用于每个元素
+	if inputType == enums.InputTypeMULTISELECT.Code() {
+		items, ok := splitArrayValue(value)
+		if !ok {
+			return nil, enums.GetParamTypeErrorMessage(name, enums.DataTypeARRAY.Code())
+		}
+		elementType := dataType
+		if elementType == enums.DataTypeARRAY {
+			elementType = enums.DataTypeSTRING
+		}
+		result := make([]any, 0, len(items))
+		for _, item := range items {
+			coerced, message := coerceByDataType(name, elementType, item)
+			if message != "" {
+				return nil, message
+			}
+			if !inParamOptions(param.Options, coerced) {
+				return nil, fmt.Sprintf("参数 '%s' 的取值 '%s' 不在可选项中", name, formatParamValue(coerced))
+			}
+			result = append(result, coerced)
+		}
+		return result, ""
+	}
+
+	coerced, message := coerceByDataType(name, dataType, value)
+	if message != "" {
+		return nil, message
+	}
+	if inputType == enums.InputTypeSELECT.Code() && !inParamOptions(param.Options, coerced) {
+		return nil, fmt.Sprintf("参数 '%s' 的取值 '%s' 不在可选项中", name, formatParamValue(coerced))
+	}
+	return coerced, ""
+}
+
+// coerceByDataType 按数据类型转换参数值，代码编辑器类型与 STRING 原样保留
+func coerceByDataType(name string, dataType enums.DataType, value any) (any, string) {
+	typeError := enums.GetParamTypeErrorMessage(name, dataType.Code())
+	switch dataType {
+	case enums.DataTypeINTEGER, enums.DataTypeLONG:
+		bitSize := 64
+		if dataType == enums.DataTypeINTEGER {
+			bitSize = 32
+		}
+		if _, ok := value.(bool); ok {
+			return nil, typeError
+		}
+		number, err := strconv.ParseInt(strings.TrimSpace(formatParamValue(value)), 10, bitSize)
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, fmt.Sprintf("参数 '%s' 超出 %s 的取值范围", name, dataType.Code())
+		}
+		if err != nil {
+			return nil, typeError
+		}
+		return number, ""
+	case enums.DataTypeDOUBLE:
+		if _, ok := value.(bool); ok {
+			return nil, typeError
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(formatParamValue(value)), 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, fmt.Sprintf("参数 '%s' 超出 %s 的取值范围", name, dataType.Code())
+		}
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, typeError
+		}
+		return number, ""
+	case enums.DataTypeBOOLEAN:
+		if b, ok := value.(bool); ok {
+			return b, ""
+		}
+		switch strings.ToLower(strings.TrimSpace(formatParamValue(value))) {
+		case "true", "1", "yes", "y", "on":
+			return true, ""
+		case "false", "0", "no", "n", "off":
+			return false, ""
+		}
+		return nil, typeError
+	case enums.DataTypeDATE:
+		if t, ok := parseParamTime(value, paramDateLayouts); ok {
+			return t.Format(paramDateFormat), ""
+		}
+		return nil, typeError
+	case enums.DataTypeDATETIME:
+		if t, ok := parseParamTime(value, paramDateTimeLayouts); ok {
+			return t.Format(paramDateTimeFormat), ""
+		}
+		return nil, typeError
+	case enums.DataTypeJSON:
+		if text, ok := value.(string); ok {
+			var parsed any
+			if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+				return nil, typeError
+			}
+			return parsed, ""
+		}
+		return value, ""
+	case enums.DataTypeJSONObject:
+		if text, ok := value.(string); ok {
+			var parsed map[string]any
+			if err := json.Unmarshal([]byte(text), &parsed); err != nil || parsed == nil {
+				return nil, typeError
+			}
+			return parsed, ""
+		}
+		if _, ok := value.(map[string]any); !ok {
+			return nil, typeError
+		}
+		return value, ""
+	case enums.DataTypeARRAY:
+		items, ok := splitArrayValue(value)
+		if !ok {
+			return nil, typeError
+		}
+		return items, ""
+	}
+	return value, ""
+}
+
+// parseParamTime 按格式依次解析时间，数值按毫秒时间戳解析
+func parseParamTime(value any, layouts []string) (time.Time, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) {
+			return time.UnixMilli(int64(v)), true
+		}
+		return time.Time{}, false
+	case int64:
+		return time.UnixMilli(v), true
+	case int:
+		return time.UnixMilli(int64(v)), true
+	case string:
+		text := strings.TrimSpace(v)
+		for _, layout := range layouts {
+			if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+				return t.In(time.Local), true
+			}
+		}
+		if millis, err := strconv.ParseInt(text, 10, 64); err == nil && len(text) >= 10 {
+			return time.UnixMilli(millis), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// splitArrayValue 将参数值转换为数组：支持数组、JSON数组字符串与逗号分隔的字符串
+func splitArrayValue(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		return v, true
+	case []string:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return items, true
+	case map[string]any:
+		return nil, false
+	case string:
+		text := strings.TrimSpace(v)
+		if strings.HasPrefix(text, "[") {
+			var items []any
+			if err := json.Unmarshal([]byte(text), &items); err != nil {
+				return nil, false
+			}
+			return items, true
+		}
+		items := make([]any, 0)
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, true
+	}
+	return []any{value}, true
+}
+
+// inParamOptions 判断取值是否在可选项中，未配置可选项时不限制
+func inParamOptions(options []dto.SelectOptionDto, value any) bool {
+	if len(options) == 0 {
+		return true
+	}
+	text := formatParamValue(value)
+	for _, option := range options {
+		if option.Value != nil && *option.Value == text {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyParamValue 判断参数值是否为空
+func isEmptyParamValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// formatParamValue 将参数值格式化为字符串：数值不使用科学计数法，对象与数组序列化为JSON
+func formatParamValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case map[string]any, []any:
+		if jsonBytes, err := json.Marshal(v); err == nil {
+			return string(jsonBytes)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// paramValues 将参数值展开为字符串列表，数组的每个元素对应一个值（查询参数与表单中为重复的键）
+func paramValues(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return []string{formatParamValue(value)}
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, formatParamValue(item))
+	}
+	return values
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+func TestCoerceByDataType(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType enums.DataType
+		value    any
+		want     any
+		wantErr  bool
+	}{
+		{"整数字符串", enums.DataTypeINTEGER, " 42 ", int64(42), false},
+		{"JSON数值", enums.DataTypeINTEGER, float64(7), int64(7), false},
+		{"整数不接受小数", enums.DataTypeINTEGER, "1.5", nil, true},
+		{"整数超出范围", enums.DataTypeINTEGER, "2147483648", nil, true},
+		{"长整数", enums.DataTypeLONG, "2147483648", int64(2147483648), false},
+		{"整数不接受布尔", enums.DataTypeLONG, true, nil, true},
+		{"浮点数", enums.DataTypeDOUBLE, "3.25", 3.25, false},
+		{"浮点数不接受NaN", enums.DataTypeDOUBLE, "NaN", nil, true},
+		{"浮点数不接受布尔", enums.DataTypeDOUBLE, false, nil, true},
+		{"布尔值", enums.DataTypeBOOLEAN, true, true, false},
+		{"布尔字符串", enums.DataTypeBOOLEAN, "Yes", true, false},
+		{"布尔数字", enums.DataTypeBOOLEAN, "0", false, false},
+		{"无效布尔", enums.DataTypeBOOLEAN, "maybe", nil, true},
+		{"日期统一格式", enums.DataTypeDATE, "2026/10/17", "2026-10-17", false},
+		{"紧凑日期", enums.DataTypeDATE, "20261017", "2026-10-17", false},
+		{"无效日期", enums.DataTypeDATE, "2026-13-01", nil, true},
+		{"日期时间统一格式", enums.DataTypeDATETIME, "2026-10-17T08:30", "2026-10-17 08:30:00", false},
+		{"无效日期时间", enums.DataTypeDATETIME, "tomorrow", nil, true},
+		{"JSON字符串", enums.DataTypeJSON, `[1,"a"]`, []any{float64(1), "a"}, false},
+		{"无效JSON", enums.DataTypeJSON, `{`, nil, true},
+		{"JSON对象", enums.DataTypeJSONObject, `{"a":1}`, map[string]any{"a": float64(1)}, false},
+		{"JSON对象不接受数组", enums.DataTypeJSONObject, `[1]`, nil, true},
+		{"JSON对象不接受null", enums.DataTypeJSONObject, `null`, nil, true},
+		{"逗号分隔数组", enums.DataTypeARRAY, "a, b,,c", []any{"a", "b", "c"}, false},
+		{"JSON数组", enums.DataTypeARRAY, `["a",1]`, []any{"a", float64(1)}, false},
+		{"单值数组", enums.DataTypeARRAY, float64(3), []any{float64(3)}, false},
+		{"数组不接受对象", enums.DataTypeARRAY, map[string]any{"a": 1}, nil, true},
+		{"字符串原样保留", enums.DataTypeSTRING, " a ", " a ", false},
+		{"代码类型原样保留", enums.DataTypeXML, "<a/>", "<a/>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, message := coerceByDataType("p", tt.dataType, tt.value)
+			if (message != "") != tt.wantErr {
+				t.Fatalf("coerceByDataType(%v, %#v) message = %q, wantErr %v", tt.dataType, tt.value, message, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coerceByDataType(%v, %#v) = %#v, want %#v", tt.dataType, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerceParamValueOptions(t *testing.T) {
+	options := []dto.SelectOptionDto{{Value: basic.Ptr("1")}, {Value: basic.Ptr("2")}}
+	tests := []struct {
+		name      string
+		inputType enums.InputType
+		dataType  enums.DataType
+		value     any
+		want      any
+		wantErr   bool
+	}{
+		{"单选命中", enums.InputTypeSELECT, enums.DataTypeINTEGER, "2", int64(2), false},
+		{"单选未命中", enums.InputTypeSELECT, enums.DataTypeINTEGER, "3", nil, true},
+		{"多选逗号分隔", enums.InputTypeMULTISELECT, enums.DataTypeINTEGER, "1,2", []any{int64(1), int64(2)}, false},
+		{"多选元素类型错误", enums.InputTypeMULTISELECT, enums.DataTypeINTEGER, "1,x", nil, true},
+		{"多选元素不在可选项中", enums.InputTypeMULTISELECT, enums.DataTypeSTRING, []any{"1", "3"}, nil, true},
+		{"多选数组类型按字符串处理", enums.InputTypeMULTISELECT, enums.DataTypeARRAY, `["1"]`, []any{"1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := dto.ApiParamDto{
+				Name:      basic.Ptr("p"),
+				InputType: basic.Ptr(tt.inputType.Code()),
+				DataType:  basic.Ptr(tt.dataType.Code()),
+				Options:   options,
+			}
+			got, message := coerceParamValue(param, tt.value)
+			if (message != "") != tt.wantErr {
+				t.Fatalf("coerceParamValue(%#v) message = %q, wantErr %v", tt.value, message, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coerceParamValue(%#v) = %#v, want %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerceParams(t *testing.T) {
+	params := []dto.ApiParamDto{
+		newTestParam("page", enums.ParamTypeURL, enums.DataTypeINTEGER, true),
+		newTestParam("ids", enums.ParamTypeHeader, enums.DataTypeARRAY, false),
+		newTestParam("id", enums.ParamTypePath, enums.DataTypeLONG, true),
+		newTestParam("enabled", enums.ParamTypeBody, enums.DataTypeBOOLEAN, true),
+	}
+	req := &dto.ApiExecuteRequestDto{
+		URLParams:  map[string]any{"page": "2"},
+		Headers:    map[string]string{"ids": `["a","b"]`},
+		PathParams: map[string]any{"id": " "},
+		BodyParams: map[string]any{"enabled": "nope"},
+	}
+
+	paramErrors := coerceParams(params, false, req)
+	if len(paramErrors) != 2 {
+		t.Fatalf("coerceParams() 返回 %d 个错误，want 2: %+v", len(paramErrors), paramErrors)
+	}
+	if paramErrors[0].Name != "id" || paramErrors[1].Name != "enabled" {
+		t.Errorf("coerceParams() 错误参数 = %s, %s, want id, enabled", paramErrors[0].Name, paramErrors[1].Name)
+	}
+	if req.URLParams["page"] != int64(2) {
+		t.Errorf("URL参数转换后 = %#v, want int64(2)", req.URLParams["page"])
+	}
+	if req.Headers["ids"] != "a,b" {
+		t.Errorf("Header数组参数 = %q, want a,b", req.Headers["ids"])
+	}
+
+	// RAW请求体模式不校验Body参数
+	req.PathParams["id"] = "1"
+	if paramErrors := coerceParams(params, true, req); len(paramErrors) != 0 {
+		t.Errorf("RAW模式 coerceParams() = %+v, want 无错误", paramErrors)
+	}
+}
+
+func TestApplyParamDefaults(t *testing.T) {
+	locked := newTestParam("version", enums.ParamTypeHeader, enums.DataTypeINTEGER, false)
+	locked.DefaultValue = "1"
+	locked.Changeable = basic.Ptr(false)
+	withDefault := newTestParam("size", enums.ParamTypeURL, enums.DataTypeINTEGER, false)
+	withDefault.DefaultValue = float64(20)
+	supplied := newTestParam("page", enums.ParamTypeURL, enums.DataTypeINTEGER, false)
+	supplied.DefaultValue = "1"
+	noDefault := newTestParam("q", enums.ParamTypeURL, enums.DataTypeSTRING, false)
+	params := []dto.ApiParamDto{locked, withDefault, supplied, noDefault}
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantSources []string
+		wantErr     bool
+	}{
+		{"填充默认值与固定值", nil, []string{"version=LOCKED", "size=DEFAULT", "page=USER"}, false},
+		{"固定值传入相同取值", map[string]string{"version": "01"}, []string{"version=LOCKED", "size=DEFAULT", "page=USER"}, false},
+		{"固定值不允许修改", map[string]string{"version": "2"}, []string{"size=DEFAULT", "page=USER"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &dto.ApiExecuteRequestDto{Headers: tt.headers, URLParams: map[string]any{"page": "3", "q": ""}}
+			sources, paramErrors := applyParamDefaults(params, false, req)
+			if (len(paramErrors) > 0) != tt.wantErr {
+				t.Fatalf("applyParamDefaults() errors = %+v, wantErr %v", paramErrors, tt.wantErr)
+			}
+			got := make([]string, 0, len(sources))
+			for _, source := range sources {
+				got = append(got, source.Name+"="+source.Source)
+			}
+			if !reflect.DeepEqual(got, tt.wantSources) {
+				t.Errorf("applyParamDefaults() sources = %q, want %q", got, tt.wantSources)
+			}
+			if req.URLParams["size"] != float64(20) || req.URLParams["page"] != "3" {
+				t.Errorf("applyParamDefaults() URL参数 = %#v", req.URLParams)
+			}
+			if !tt.wantErr && req.Headers["version"] != "1" {
+				t.Errorf("固定值参数 = %q, want 1", req.Headers["version"])
+			}
+		})
+	}
+}
+
+func TestApplyParamDefaultsSkippedParams(t *testing.T) {
+	bodyParam := newTestParam("name", enums.ParamTypeBody, enums.DataTypeSTRING, false)
+	bodyParam.DefaultValue = "tom"
+	fileParam := newTestParam("avatar", enums.ParamTypeBody, enums.DataTypeSTRING, false)
+	fileParam.InputType = basic.Ptr(enums.InputTypeFILE.Code())
+	fileParam.DefaultValue = "a.png"
+	lockedNoDefault := newTestParam("tenant", enums.ParamTypeHeader, enums.DataTypeSTRING, false)
+	lockedNoDefault.Changeable = basic.Ptr(false)
+	tags := newTestParam("X-Tags", enums.ParamTypeHeader, enums.DataTypeARRAY, false)
+	tags.DefaultValue = []any{"a", "b"}
+	params := []dto.ApiParamDto{bodyParam, fileParam, lockedNoDefault, tags}
+
+	// RAW请求体模式不处理Body参数，文件参数不填充默认值
+	req := &dto.ApiExecuteRequestDto{}
+	sources, paramErrors := applyParamDefaults(params, true, req)
+	if len(paramErrors) != 0 || len(sources) != 1 || sources[0].Name != "X-Tags" {
+		t.Fatalf("applyParamDefaults() = %+v, %+v", sources, paramErrors)
+	}
+	if req.BodyParams != nil {
+		t.Errorf("RAW模式不应填充Body参数: %+v", req.BodyParams)
+	}
+	if req.Headers["X-Tags"] != "a,b" {
+		t.Errorf("数组请求头 = %q, want a,b", req.Headers["X-Tags"])
+	}
+	if _, ok := req.Headers["tenant"]; ok {
+		t.Error("无默认值的固定参数不应填充")
+	}
+
+	// 无默认值的固定参数不允许传入取值
+	req = &dto.ApiExecuteRequestDto{Headers: map[string]string{"tenant": "t1"}}
+	if _, paramErrors := applyParamDefaults(params, false, req); len(paramErrors) != 1 || paramErrors[0].Name != "tenant" {
+		t.Errorf("applyParamDefaults() errors = %+v, want tenant 不允许修改", paramErrors)
+	}
+}
+
+func TestApplyParamDefaultsSendsDefaults(t *testing.T) {
+	var received struct {
+		query  string
+		header string
+		body   map[string]any
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.query = r.URL.RawQuery
+		received.header = r.Header.Get("X-Version")
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &received.body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	version := newTestParam("X-Version", enums.ParamTypeHeader, enums.DataTypeSTRING, false)
+	version.DefaultValue = "v1"
+	version.Changeable = basic.Ptr(false)
+	size := newTestParam("size", enums.ParamTypeURL, enums.DataTypeINTEGER, true)
+	size.DefaultValue = "20"
+	name := newTestParam("name", enums.ParamTypeBody, enums.DataTypeSTRING, true)
+	name.DefaultValue = "tom"
+	params := []dto.ApiParamDto{version, size, name}
+	paramsJSON, _ := json.Marshal(params)
+	apiInterface := &entity.ApiInterface{
+		Method:   "POST",
+		URL:      server.URL + "/users",
+		PostType: basic.Ptr(enums.PostTypeApplicationJSON.Code()),
+		Params:   basic.Ptr(string(paramsJSON)),
+	}
+
+	req := &dto.ApiExecuteRequestDto{BodyParams: map[string]any{"name": ""}}
+	if _, paramErrors := applyParamDefaults(parseInterfaceParams(apiInterface), false, req); len(paramErrors) != 0 {
+		t.Fatalf("applyParamDefaults() errors = %+v", paramErrors)
+	}
+	if paramErrors := coerceParams(params, false, req); len(paramErrors) != 0 {
+		t.Fatalf("必填参数使用默认值后应通过校验: %+v", paramErrors)
+	}
+	response, err := newTestExecuteService(&SecretRedactor{cipher: newTestSecretCipher(t)}).executeHTTPRequest(apiInterface, nil, req)
+	if err != nil || !response.Success {
+		t.Fatalf("executeHTTPRequest() = %+v, %v", response, err)
+	}
+	if received.query != "size=20" || received.header != "v1" || received.body["name"] != "tom" {
+		t.Errorf("服务端收到 query=%q header=%q body=%v", received.query, received.header, received.body)
+	}
+}
+
+func TestFormatParamValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{nil, ""},
+		{"a", "a"},
+		{float64(12345678901), "12345678901"},
+		{0.000001, "0.000001"},
+		{true, "true"},
+		{[]any{"a", float64(1)}, `["a",1]`},
+		{map[string]any{"a": "b"}, `{"a":"b"}`},
+	}
+
+	for _, tt := range tests {
+		if got := formatParamValue(tt.value); got != tt.want {
+			t.Errorf("formatParamValue(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// newTestParam 创建测试用参数定义
+func newTestParam(name string, paramType enums.ParamType, dataType enums.DataType, required bool) dto.ApiParamDto {
+	return dto.ApiParamDto{
+		Name:      basic.Ptr(name),
+		ParamType: basic.Ptr(paramType.Code()),
+		DataType:  basic.Ptr(dataType.Code()),
+		InputType: basic.Ptr(enums.InputTypeTEXT.Code()),
+		Required:  basic.Ptr(required),
+	}
+}
@@ -0,0 +1,623 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+// postmanSchemaV21 Postman Collection v2.1 规范地址
+const postmanSchemaV21 = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// postmanBaseURLVariable 导出相对路径接口时使用的集合变量
+const postmanBaseURLVariable = "baseUrl"
+
+var (
+	// postmanPathVariablePattern 匹配 Postman URL 中的 :name 路径变量
+	postmanPathVariablePattern = regexp.MustCompile(`/:([A-Za-z0-9_.\-]+)`)
+	// postmanVariablePattern 匹配 {{name}} 变量
+	postmanVariablePattern = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+)
+
+// postmanCollection Postman Collection v2.1
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth,omitempty"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description any    `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// postmanItem 目录（含 Item）或请求（含 Request）
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description any             `json:"description,omitempty"`
+	Item        []postmanItem   `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+	Auth        *postmanAuth    `json:"auth,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanKeyValue `json:"header"`
+	URL         postmanURL        `json:"url"`
+	Body        *postmanBody      `json:"body,omitempty"`
+	Auth        *postmanAuth      `json:"auth,omitempty"`
+	Description any               `json:"description,omitempty"`
+}
+
+// postmanURL 请求地址，导入时兼容字符串与对象两种格式
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol,omitempty"`
+	Host     postmanStringList `json:"host,omitempty"`
+	Path     postmanStringList `json:"path,omitempty"`
+	Query    []postmanKeyValue `json:"query,omitempty"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+	type alias postmanURL
+	return json.Unmarshal(data, (*alias)(u))
+}
+
+// postmanStringList 字符串数组，导入时兼容单个字符串
+type postmanStringList []string
+
+func (l *postmanStringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = strings.Split(single, ".")
+		return nil
+	}
+	var items []any
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	for _, item := range items {
+		*l = append(*l, fmt.Sprintf("%v", item))
+	}
+	return nil
+}
+
+type postmanKeyValue struct {
+	Key         string `json:"key"`
+	Value       any    `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Src         any    `json:"src,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Description any    `json:"description,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string              `json:"mode"`
+	Raw        string              `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue   `json:"urlencoded,omitempty"`
+	FormData   []postmanKeyValue   `json:"formdata,omitempty"`
+	Options    *postmanBodyOptions `json:"options,omitempty"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Basic  []postmanKeyValue `json:"basic,omitempty"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+	APIKey []postmanKeyValue `json:"apikey,omitempty"`
+}
+
+// parsePostmanCollection 解析 Postman Collection 文件
+func parsePostmanCollection(content []byte) (*postmanCollection, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(content, &collection); err != nil {
+		return nil, fmt.Errorf("解析 Postman Collection 失败: %w", err)
+	}
+	if !strings.Contains(collection.Info.Schema, "collection/v2.") {
+		return nil, fmt.Errorf("仅支持 Postman Collection v2.1")
+	}
+	return &collection, nil
+}
+
+// convertToForms 将集合中的请求转换为接口表单
+// 集合变量按取值替换，未定义取值的 {{name}} 保留给执行环境变量解析；目录路径写入接口描述
+func (c *postmanCollection) convertToForms() []dto.ApiInterfaceFormDto {
+	variables := make(map[string]string)
+	for _, variable := range c.Variable {
+		if variable.Disabled || variable.Value == nil {
+			continue
+		}
+		if value := postmanValue(variable.Value); value != "" {
+			variables[variable.Key] = value
+		}
+	}
+
+	forms := make([]dto.ApiInterfaceFormDto, 0)
+	var walk func(items []postmanItem, folders []string, auth *postmanAuth)
+	walk = func(items []postmanItem, folders []string, auth *postmanAuth) {
+		for _, item := range items {
+			itemAuth := auth
+			if item.Auth != nil {
+				itemAuth = item.Auth
+			}
+			if item.Request == nil {
+				walk(item.Item, append(append([]string{}, folders...), item.Name), itemAuth)
+				continue
+			}
+			forms = append(forms, convertPostmanRequest(item, folders, itemAuth, variables))
+		}
+	}
+	walk(c.Item, nil, c.Auth)
+	return forms
+}
+
+// convertPostmanRequest 将单个请求转换为接口表单
+func convertPostmanRequest(item postmanItem, folders []string, inheritedAuth *postmanAuth, variables map[string]string) dto.ApiInterfaceFormDto {
+	request := item.Request
+	substitute := func(text string) string {
+		return postmanVariablePattern.ReplaceAllStringFunc(text, func(match string) string {
+			name := postmanVariablePattern.FindStringSubmatch(match)[1]
+			if value, ok := variables[name]; ok {
+				return value
+			}
+			return match
+		})
+	}
+
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	rawURL := request.URL.Raw
+	if rawURL == "" {
+		rawURL = strings.Join(request.URL.Host, ".")
+		if len(request.URL.Path) > 0 {
+			rawURL += "/" + strings.Join(request.URL.Path, "/")
+		}
+		if request.URL.Protocol != "" {
+			rawURL = request.URL.Protocol + "://" + rawURL
+		}
+	}
+	rawURL = substitute(rawURL)
+	rawURL, rawQuery, _ := strings.Cut(rawURL, "?")
+	rawURL, _, _ = strings.Cut(rawURL, "#")
+	// Postman 路径变量 :name 转换为 {name}
+	rawURL = postmanPathVariablePattern.ReplaceAllString(rawURL, "/{$1}")
+
+	name := item.Name
+	if name == "" {
+		name = method + " " + rawURL
+	}
+	form := dto.ApiInterfaceFormDto{
+		Name:         basic.Ptr(name),
+		Method:       basic.Ptr(method),
+		URL:          basic.Ptr(rawURL),
+		URLParams:    make([]dto.ApiParamDto, 0),
+		PathParams:   make([]dto.ApiParamDto, 0),
+		HeaderParams: make([]dto.ApiParamDto, 0),
+		BodyParams:   make([]dto.ApiParamDto, 0),
+	}
+	description := postmanText(request.Description)
+	if description == "" {
+		description = postmanText(item.Description)
+	}
+	if len(folders) > 0 {
+		description = strings.TrimSpace("目录: " + strings.Join(folders, " / ") + "\n" + description)
+	}
+	if description != "" {
+		form.Description = basic.Ptr(description)
+	}
+
+	// URL参数：优先使用结构化的 query，否则解析原始地址中的查询串
+	query := request.URL.Query
+	if len(query) == 0 && rawQuery != "" {
+		if values, err := url.ParseQuery(rawQuery); err == nil {
+			for _, key := range sortedQueryKeys(values) {
+				query = append(query, postmanKeyValue{Key: key, Value: values.Get(key)})
+			}
+		}
+	}
+	for _, kv := range query {
+		if kv.Disabled || kv.Key == "" {
+			continue
+		}
+		form.URLParams = append(form.URLParams, postmanDraftParam(kv, enums.ParamTypeURL, len(form.URLParams), substitute))
+	}
+
+	// Path参数：url.variable 中定义的变量与地址中出现的占位符
+	definedPathParams := make(map[string]bool)
+	for _, kv := range request.URL.Variable {
+		if kv.Key == "" {
+			continue
+		}
+		definedPathParams[kv.Key] = true
+		param := postmanDraftParam(kv, enums.ParamTypePath, len(form.PathParams), substitute)
+		param.Required = basic.Ptr(true)
+		form.PathParams = append(form.PathParams, param)
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(postmanVariablePattern.ReplaceAllString(rawURL, ""), -1) {
+		if !definedPathParams[match[1]] {
+			definedPathParams[match[1]] = true
+			param := newDraftParam(match[1], enums.ParamTypePath, nil, enums.DataTypeSTRING, len(form.PathParams))
+			param.Required = basic.Ptr(true)
+			form.PathParams = append(form.PathParams, param)
+		}
+	}
+
+	contentType := ""
+	for _, kv := range request.Header {
+		if kv.Disabled || kv.Key == "" {
+			continue
+		}
+		if strings.EqualFold(kv.Key, "Content-Type") {
+			contentType = substitute(postmanValue(kv.Value))
+			continue
+		}
+		form.HeaderParams = append(form.HeaderParams, postmanDraftParam(kv, enums.ParamTypeHeader, len(form.HeaderParams), substitute))
+	}
+
+	auth := inheritedAuth
+	if request.Auth != nil {
+		auth = request.Auth
+	}
+	applyPostmanAuth(&form, auth, substitute)
+
+	if request.Body != nil && method != "GET" && method != "HEAD" {
+		applyPostmanBody(&form, request.Body, contentType, substitute)
+	}
+	return form
+}
+
+// applyPostmanAuth 将认证信息转换为密码类型的请求头或URL参数
+func applyPostmanAuth(form *dto.ApiInterfaceFormDto, auth *postmanAuth, substitute func(string) string) {
+	if auth == nil {
+		return
+	}
+	value := func(list []postmanKeyValue, key string) string {
+		for _, kv := range list {
+			if kv.Key == key {
+				return substitute(postmanValue(kv.Value))
+			}
+		}
+		return ""
+	}
+	addSecret := func(name, secret string, paramType enums.ParamType) {
+		var param dto.ApiParamDto
+		if paramType == enums.ParamTypeURL {
+			param = newDraftParam(name, paramType, secret, enums.DataTypeSTRING, len(form.URLParams))
+			param.InputType = basic.Ptr(enums.InputTypePASSWORD.Code())
+			form.URLParams = append(form.URLParams, param)
+			return
+		}
+		param = newDraftParam(name, paramType, secret, enums.DataTypeSTRING, len(form.HeaderParams))
+		param.InputType = basic.Ptr(enums.InputTypePASSWORD.Code())
+		form.HeaderParams = append(form.HeaderParams, param)
+	}
+
+	switch auth.Type {
+	case "basic":
+		credentials := value(auth.Basic, "username") + ":" + value(auth.Basic, "password")
+		addSecret("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)), enums.ParamTypeHeader)
+	case "bearer":
+		addSecret("Authorization", "Bearer "+value(auth.Bearer, "token"), enums.ParamTypeHeader)
+	case "apikey":
+		key := value(auth.APIKey, "key")
+		if key == "" {
+			return
+		}
+		if value(auth.APIKey, "in") == "query" {
+			addSecret(key, value(auth.APIKey, "value"), enums.ParamTypeURL)
+		} else {
+			addSecret(key, value(auth.APIKey, "value"), enums.ParamTypeHeader)
+		}
+	}
+}
+
+// applyPostmanBody 按请求体模式设置表单：raw 使用RAW模式，urlencoded 与 formdata 转换为Body参数
+func applyPostmanBody(form *dto.ApiInterfaceFormDto, body *postmanBody, contentType string, substitute func(string) string) {
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return
+		}
+		mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+		postType := enums.PostTypeFromCode(mediaType)
+		if postType == nil || !postType.IsRawBodyType() {
+			postType = basic.Ptr(enums.PostTypeTextPlain)
+			if body.Options != nil {
+				switch body.Options.Raw.Language {
+				case "json":
+					postType = basic.Ptr(enums.PostTypeApplicationJSON)
+				case "xml":
+					postType = basic.Ptr(enums.PostTypeApplicationXML)
+				}
+			}
+		}
+		form.PostType = basic.Ptr(postType.Code())
+		form.BodyMode = basic.Ptr(enums.BodyModeRaw.Code())
+		form.RawBody = basic.Ptr(substitute(body.Raw))
+		form.RawDataType = basic.Ptr(postType.DataType().Code())
+	case "urlencoded":
+		form.PostType = basic.Ptr(enums.PostTypeApplicationXWWWFormURLEncoded.Code())
+		form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+		for _, kv := range body.URLEncoded {
+			if !kv.Disabled && kv.Key != "" {
+				form.BodyParams = append(form.BodyParams, postmanDraftParam(kv, enums.ParamTypeBody, len(form.BodyParams), substitute))
+			}
+		}
+	case "formdata":
+		form.PostType = basic.Ptr(enums.PostTypeMultipartFormData.Code())
+		form.BodyMode = basic.Ptr(enums.BodyModeParams.Code())
+		for _, kv := range body.FormData {
+			if kv.Disabled || kv.Key == "" {
+				continue
+			}
+			if kv.Type == "file" {
+				param := newDraftParam(kv.Key, enums.ParamTypeBody, nil, enums.DataTypeSTRING, len(form.BodyParams))
+				param.InputType = basic.Ptr(enums.InputTypeFILE.Code())
+				if description := postmanText(kv.Description); description != "" {
+					param.Description = basic.Ptr(description)
+				}
+				form.BodyParams = append(form.BodyParams, param)
+				continue
+			}
+			form.BodyParams = append(form.BodyParams, postmanDraftParam(kv, enums.ParamTypeBody, len(form.BodyParams), substitute))
+		}
+	}
+}
+
+// postmanDraftParam 将键值对转换为参数，取值作为默认值
+func postmanDraftParam(kv postmanKeyValue, paramType enums.ParamType, sortIndex int, substitute func(string) string) dto.ApiParamDto {
+	var value any
+	if kv.Value != nil {
+		value = substitute(postmanValue(kv.Value))
+	}
+	param := newDraftParam(kv.Key, paramType, value, enums.DataTypeSTRING, sortIndex)
+	if description := postmanText(kv.Description); description != "" {
+		param.Description = basic.Ptr(description)
+	}
+	return param
+}
+
+// buildPostmanCollection 将接口导出为 Postman Collection v2.1
+// 相对路径的接口使用 {{baseUrl}} 集合变量，取值为接口默认环境的地址
+func buildPostmanCollection(name string, interfaces []entity.ApiInterface, environments map[uint64]entity.ApiEnvironment) postmanCollection {
+	collection := postmanCollection{
+		Info: postmanInfo{Name: name, Schema: postmanSchemaV21},
+		Item: make([]postmanItem, 0, len(interfaces)),
+	}
+
+	baseURL, needBaseURL := "", false
+	for i := range interfaces {
+		apiInterface := &interfaces[i]
+		if !isAbsoluteURL(apiInterface.URL) && !strings.HasPrefix(apiInterface.URL, "{{") {
+			needBaseURL = true
+			if environment, ok := environments[uint64Value(apiInterface.EnvironmentID)]; ok && baseURL == "" {
+				baseURL = environment.BaseURL
+			}
+		}
+		collection.Item = append(collection.Item, postmanItemFromInterface(apiInterface))
+	}
+	if needBaseURL {
+		collection.Variable = []postmanKeyValue{{Key: postmanBaseURLVariable, Value: strings.TrimRight(baseURL, "/"), Type: "string"}}
+	}
+	return collection
+}
+
+// postmanItemFromInterface 将接口转换为 Postman 请求
+func postmanItemFromInterface(apiInterface *entity.ApiInterface) postmanItem {
+	request := &postmanRequest{
+		Method: strings.ToUpper(apiInterface.Method),
+		Header: make([]postmanKeyValue, 0),
+	}
+	if apiInterface.Description != nil && *apiInterface.Description != "" {
+		request.Description = *apiInterface.Description
+	}
+
+	var urlParams, pathParams, bodyParams []dto.ApiParamDto
+	for _, param := range parseInterfaceParams(apiInterface) {
+		if param.Name == nil || param.ParamType == nil {
+			continue
+		}
+		kv := postmanKeyValue{Key: *param.Name, Value: postmanDefaultValue(param.DefaultValue)}
+		if param.Description != nil && *param.Description != "" {
+			kv.Description = *param.Description
+		}
+		switch enums.ParamType(*param.ParamType) {
+		case enums.ParamTypeURL:
+			urlParams = append(urlParams, param)
+			request.URL.Query = append(request.URL.Query, kv)
+		case enums.ParamTypePath:
+			pathParams = append(pathParams, param)
+			request.URL.Variable = append(request.URL.Variable, kv)
+		case enums.ParamTypeHeader:
+			request.Header = append(request.Header, kv)
+		case enums.ParamTypeBody:
+			bodyParams = append(bodyParams, param)
+		}
+	}
+
+	// 地址：相对路径拼接 {{baseUrl}}，{name} 路径占位符转换为 :name
+	rawURL := apiInterface.URL
+	if !isAbsoluteURL(rawURL) && !strings.HasPrefix(rawURL, "{{") {
+		rawURL = "{{" + postmanBaseURLVariable + "}}/" + strings.TrimLeft(rawURL, "/")
+	}
+	for _, param := range pathParams {
+		rawURL = strings.ReplaceAll(rawURL, "/{"+*param.Name+"}", "/:"+*param.Name)
+	}
+	request.URL.fillParts(rawURL)
+	if len(request.URL.Query) > 0 {
+		pairs := make([]string, 0, len(request.URL.Query))
+		for _, kv := range request.URL.Query {
+			pairs = append(pairs, kv.Key+"="+postmanValue(kv.Value))
+		}
+		request.URL.Raw += "?" + strings.Join(pairs, "&")
+	}
+
+	request.Body = postmanBodyFromInterface(apiInterface, bodyParams)
+	if request.Body != nil && request.Body.Mode == "raw" && !hasPostmanHeader(request.Header, "Content-Type") {
+		request.Header = append(request.Header, postmanKeyValue{Key: "Content-Type", Value: requestPostType(apiInterface)})
+	}
+	return postmanItem{Name: apiInterface.Name, Request: request}
+}
+
+// postmanBodyFromInterface 根据接口请求体配置生成 Postman 请求体
+func postmanBodyFromInterface(apiInterface *entity.ApiInterface, bodyParams []dto.ApiParamDto) *postmanBody {
+	method := strings.ToUpper(apiInterface.Method)
+	if method == "GET" || method == "HEAD" {
+		return nil
+	}
+	postType := requestPostType(apiInterface)
+	language := "text"
+	switch enums.PostType(postType).DataType() {
+	case enums.DataTypeJSON:
+		language = "json"
+	case enums.DataTypeXML:
+		language = "xml"
+	}
+
+	if isRawBodyMode(apiInterface) {
+		if apiInterface.RawBody == nil || *apiInterface.RawBody == "" {
+			return nil
+		}
+		body := &postmanBody{Mode: "raw", Raw: *apiInterface.RawBody, Options: &postmanBodyOptions{}}
+		body.Options.Raw.Language = language
+		return body
+	}
+	if len(bodyParams) == 0 {
+		return nil
+	}
+
+	switch postType {
+	case enums.PostTypeApplicationXWWWFormURLEncoded.Code():
+		body := &postmanBody{Mode: "urlencoded"}
+		for _, param := range bodyParams {
+			body.URLEncoded = append(body.URLEncoded, postmanKeyValue{Key: *param.Name, Value: postmanDefaultValue(param.DefaultValue), Type: "text"})
+		}
+		return body
+	case enums.PostTypeMultipartFormData.Code():
+		body := &postmanBody{Mode: "formdata"}
+		for _, param := range bodyParams {
+			if param.InputType != nil && *param.InputType == enums.InputTypeFILE.Code() {
+				body.FormData = append(body.FormData, postmanKeyValue{Key: *param.Name, Type: "file", Src: []string{}})
+				continue
+			}
+			body.FormData = append(body.FormData, postmanKeyValue{Key: *param.Name, Value: postmanDefaultValue(param.DefaultValue), Type: "text"})
+		}
+		return body
+	}
+
+	// JSON：按参数默认值组装请求体，JSON_OBJECT 类型的文本解析为对象
+	object := make(map[string]any, len(bodyParams))
+	for _, param := range bodyParams {
+		value := param.DefaultValue
+		if text, ok := value.(string); ok && param.DataType != nil && *param.DataType == enums.DataTypeJSONObject.Code() {
+			var parsed any
+			if err := json.Unmarshal([]byte(text), &parsed); err == nil {
+				value = parsed
+			}
+		}
+		object[*param.Name] = value
+	}
+	raw, _ := json.MarshalIndent(object, "", "  ")
+	body := &postmanBody{Mode: "raw", Raw: string(raw), Options: &postmanBodyOptions{}}
+	body.Options.Raw.Language = "json"
+	return body
+}
+
+// fillParts 根据完整地址填充 protocol、host、path
+func (u *postmanURL) fillParts(rawURL string) {
+	u.Raw = rawURL
+	rest := rawURL
+	if protocol, remain, ok := strings.Cut(rest, "://"); ok {
+		u.Protocol = protocol
+		rest = remain
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	if strings.HasPrefix(host, "{{") {
+		u.Host = postmanStringList{host}
+	} else {
+		u.Host = strings.Split(host, ".")
+	}
+	if path != "" {
+		u.Path = strings.Split(path, "/")
+	}
+}
+
+// hasPostmanHeader 是否已包含指定请求头
+func hasPostmanHeader(headers []postmanKeyValue, name string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(header.Key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// postmanDefaultValue 参数默认值转换为 Postman 文本
+func postmanDefaultValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	if jsonBytes, err := json.Marshal(value); err == nil {
+		return string(jsonBytes)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// postmanValue 键值对中的取值
+func postmanValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// postmanText 描述字段，兼容字符串与 {content} 对象
+func postmanText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if content, ok := v["content"].(string); ok {
+			return content
+		}
+	}
+	return ""
+}
+
+// uint64Value 无符号整数指针取值，nil 时返回0
+func uint64Value(value *uint64) uint64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
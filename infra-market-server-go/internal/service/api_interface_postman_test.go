@@ -0,0 +1,164 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+const testPostmanCollection = `{
+	"info": {"name": "demo", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+	"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]},
+	"variable": [
+		{"key": "host", "value": "https://api.example.com"},
+		{"key": "token", "value": "secret-token"}
+	],
+	"item": [
+		{
+			"name": "用户",
+			"item": [
+				{
+					"name": "查询用户",
+					"request": {
+						"method": "get",
+						"url": "{{host}}/users/:id?verbose=true&lang={{lang}}",
+						"header": [{"key": "X-Trace-Id", "value": "abc"}, {"key": "X-Disabled", "value": "1", "disabled": true}]
+					}
+				}
+			]
+		},
+		{
+			"name": "登录",
+			"request": {
+				"method": "POST",
+				"auth": {"type": "apikey", "apikey": [{"key": "key", "value": "api_key"}, {"key": "value", "value": "k"}, {"key": "in", "value": "query"}]},
+				"url": {"raw": "{{host}}/login", "host": ["{{host}}"], "path": ["login"]},
+				"body": {"mode": "urlencoded", "urlencoded": [{"key": "username", "value": "admin"}, {"key": "password", "value": "p"}]}
+			}
+		}
+	]
+}`
+
+func TestPostmanCollectionConvertToForms(t *testing.T) {
+	collection, err := parsePostmanCollection([]byte(testPostmanCollection))
+	if err != nil {
+		t.Fatalf("parsePostmanCollection() error = %v", err)
+	}
+	forms := collection.convertToForms()
+	if len(forms) != 2 {
+		t.Fatalf("convertToForms() 返回 %d 个接口, want 2", len(forms))
+	}
+
+	get := forms[0]
+	if *get.Method != "GET" || *get.URL != "https://api.example.com/users/{id}" {
+		t.Errorf("查询接口 = %s %s", *get.Method, *get.URL)
+	}
+	if get.Description == nil || *get.Description != "目录: 用户" {
+		t.Errorf("目录应写入描述: %v", get.Description)
+	}
+	if len(get.PathParams) != 1 || *get.PathParams[0].Name != "id" || !*get.PathParams[0].Required {
+		t.Errorf("路径参数 = %+v", get.PathParams)
+	}
+	query := paramsByName(get.URLParams)
+	if query["verbose"].DefaultValue != "true" || query["lang"].DefaultValue != "{{lang}}" {
+		t.Errorf("URL参数 = %+v", get.URLParams)
+	}
+	headers := paramsByName(get.HeaderParams)
+	if _, ok := headers["X-Disabled"]; ok {
+		t.Error("禁用的请求头不应导入")
+	}
+	if auth, ok := headers["Authorization"]; !ok || auth.DefaultValue != "Bearer secret-token" || *auth.InputType != enums.InputTypePASSWORD.Code() {
+		t.Errorf("继承的 Bearer 认证 = %+v", auth)
+	}
+
+	login := forms[1]
+	if _, ok := paramsByName(login.HeaderParams)["Authorization"]; ok {
+		t.Error("请求自身的认证应覆盖集合认证")
+	}
+	if apiKey, ok := paramsByName(login.URLParams)["api_key"]; !ok || apiKey.DefaultValue != "k" {
+		t.Errorf("API Key 认证 = %+v", login.URLParams)
+	}
+	if *login.PostType != enums.PostTypeApplicationXWWWFormURLEncoded.Code() || len(login.BodyParams) != 2 {
+		t.Errorf("表单请求体 = %v %+v", *login.PostType, login.BodyParams)
+	}
+}
+
+func TestParsePostmanCollectionInvalid(t *testing.T) {
+	if _, err := parsePostmanCollection([]byte(`{"info": {"schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}}`)); err == nil {
+		t.Error("v1 集合应返回错误")
+	}
+	if _, err := parsePostmanCollection([]byte(`{`)); err == nil {
+		t.Error("格式错误应返回错误")
+	}
+}
+
+func TestBuildPostmanCollection(t *testing.T) {
+	params, _ := json.Marshal([]dto.ApiParamDto{
+		{Name: basic.Ptr("id"), ParamType: basic.Ptr(enums.ParamTypePath.Code()), DefaultValue: "1"},
+		{Name: basic.Ptr("page"), ParamType: basic.Ptr(enums.ParamTypeURL.Code()), DefaultValue: float64(2)},
+		{Name: basic.Ptr("name"), ParamType: basic.Ptr(enums.ParamTypeBody.Code()), DefaultValue: "tom"},
+		{Name: basic.Ptr("meta"), ParamType: basic.Ptr(enums.ParamTypeBody.Code()), DataType: basic.Ptr(enums.DataTypeJSONObject.Code()), DefaultValue: `{"a":1}`},
+	})
+	environmentID := uint64(7)
+	interfaces := []entity.ApiInterface{
+		{
+			Name:          "更新用户",
+			Method:        "PUT",
+			URL:           "/users/{id}",
+			PostType:      basic.Ptr(enums.PostTypeApplicationJSON.Code()),
+			Params:        basic.Ptr(string(params)),
+			EnvironmentID: &environmentID,
+		},
+		{Name: "健康检查", Method: "GET", URL: "https://api.example.com/health"},
+	}
+	environments := map[uint64]entity.ApiEnvironment{environmentID: {BaseURL: "https://dev.example.com/"}}
+
+	collection := buildPostmanCollection("demo", interfaces, environments)
+	if collection.Info.Schema != postmanSchemaV21 || len(collection.Item) != 2 {
+		t.Fatalf("集合 = %+v", collection.Info)
+	}
+	if len(collection.Variable) != 1 || collection.Variable[0].Key != postmanBaseURLVariable || collection.Variable[0].Value != "https://dev.example.com" {
+		t.Errorf("baseUrl 变量 = %+v", collection.Variable)
+	}
+
+	update := collection.Item[0].Request
+	if update.URL.Raw != "{{baseUrl}}/users/:id?page=2" {
+		t.Errorf("导出地址 = %s", update.URL.Raw)
+	}
+	if update.Body == nil || update.Body.Mode != "raw" {
+		t.Fatalf("导出请求体 = %+v", update.Body)
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(update.Body.Raw), &body); err != nil {
+		t.Fatalf("请求体不是 JSON: %v", err)
+	}
+	if body["name"] != "tom" || body["meta"].(map[string]any)["a"] != float64(1) {
+		t.Errorf("请求体 = %v", body)
+	}
+	if !hasPostmanHeader(update.Header, "content-type") {
+		t.Error("RAW 请求体应导出 Content-Type 请求头")
+	}
+
+	health := collection.Item[1].Request
+	if health.Body != nil || health.URL.Protocol != "https" || len(health.URL.Host) != 3 {
+		t.Errorf("GET 接口 = %+v", health.URL)
+	}
+
+	// 导出的集合可以重新导入
+	content, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := parsePostmanCollection(content)
+	if err != nil {
+		t.Fatalf("重新导入失败: %v", err)
+	}
+	forms := imported.convertToForms()
+	if len(forms) != 2 || *forms[0].URL != "https://dev.example.com/users/{id}" {
+		t.Errorf("重新导入地址 = %v", *forms[0].URL)
+	}
+}
@@ -0,0 +1,539 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/config"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/util"
+)
+
+// redactionMask 脱敏后的占位值
+const redactionMask = "******"
+
+// redactionURLPattern 匹配错误信息中的请求地址
+var redactionURLPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// executionRecordRawPayload 执行记录脱敏前的原始内容，加密后保存在 raw_payload 字段
+type executionRecordRawPayload struct {
+	RequestParams     *string `json:"requestParams,omitempty"`
+	RequestPathParams *string `json:"requestPathParams,omitempty"`
+	RequestHeaders    *string `json:"requestHeaders,omitempty"`
+	RequestBody       *string `json:"requestBody,omitempty"`
+	ResponseHeaders   *string `json:"responseHeaders,omitempty"`
+	ResponseBody      *string `json:"responseBody,omitempty"`
+	RedirectChain     *string `json:"redirectChain,omitempty"`
+	Attempts          *string `json:"attempts,omitempty"`
+	ErrorMessage      *string `json:"errorMessage,omitempty"`
+}
+
+// SecretRedactor 执行记录脱敏器
+// 规则：PASSWORD类型参数、配置的请求头/响应头名称、配置的请求体/响应体JSONPath；
+// 重定向链、重试记录与错误信息中的请求地址可能带有认证配置的 API Key，查询参数与密码统一脱敏
+type SecretRedactor struct {
+	headers           map[string]bool
+	requestBodyPaths  []string
+	responseBodyPaths []string
+	cipher            *util.SecretCipher
+}
+
+func NewSecretRedactor(cfg *config.Config, cipher *util.SecretCipher) *SecretRedactor {
+	headers := make(map[string]bool)
+	for _, name := range cfg.Redaction.Headers {
+		if name = strings.TrimSpace(name); name != "" {
+			headers[strings.ToLower(name)] = true
+		}
+	}
+	return &SecretRedactor{
+		headers:           headers,
+		requestBodyPaths:  cfg.Redaction.RequestBodyPaths,
+		responseBodyPaths: cfg.Redaction.ResponseBodyPaths,
+		cipher:            cipher,
+	}
+}
+
+// RedactRecord 对执行记录脱敏，脱敏前的原始内容加密保存到 RawPayload
+func (r *SecretRedactor) RedactRecord(record *entity.ApiInterfaceExecutionRecord, apiInterface *entity.ApiInterface) {
+	secretParams := passwordParamNames(apiInterface)
+	raw := executionRecordRawPayload{
+		RequestParams:     record.RequestParams,
+		RequestPathParams: record.RequestPathParams,
+		RequestHeaders:    record.RequestHeaders,
+		RequestBody:       record.RequestBody,
+		ResponseHeaders:   record.ResponseHeaders,
+		ResponseBody:      record.ResponseBody,
+		RedirectChain:     record.RedirectChain,
+		Attempts:          record.Attempts,
+		ErrorMessage:      record.ErrorMessage,
+	}
+
+	r.maskRecord(record, secretParams)
+
+	changed := !sameStringPtr(raw.RequestParams, record.RequestParams) ||
+		!sameStringPtr(raw.RequestPathParams, record.RequestPathParams) ||
+		!sameStringPtr(raw.RequestHeaders, record.RequestHeaders) ||
+		!sameStringPtr(raw.RequestBody, record.RequestBody) ||
+		!sameStringPtr(raw.ResponseHeaders, record.ResponseHeaders) ||
+		!sameStringPtr(raw.ResponseBody, record.ResponseBody) ||
+		!sameStringPtr(raw.RedirectChain, record.RedirectChain) ||
+		!sameStringPtr(raw.Attempts, record.Attempts) ||
+		!sameStringPtr(raw.ErrorMessage, record.ErrorMessage)
+	if !changed {
+		return
+	}
+
+	rawJSON, err := json.Marshal(raw)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "序列化执行记录原始内容失败: %v\n", err)
+		return
+	}
+	encrypted, err := r.cipher.Encrypt(string(rawJSON))
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "加密执行记录原始内容失败: %v\n", err)
+		return
+	}
+	record.RawPayload = stringPtr(encrypted)
+}
+
+// maskRecord 按规则就地脱敏执行记录
+func (r *SecretRedactor) maskRecord(record *entity.ApiInterfaceExecutionRecord, secretParams map[string]bool) {
+	record.RequestParams = maskJSONObjectKeys(record.RequestParams, secretParams)
+	record.RequestPathParams = maskJSONObjectKeys(record.RequestPathParams, secretParams)
+	record.RequestHeaders = r.maskHeaders(record.RequestHeaders, secretParams)
+	record.RequestBody = maskJSONPaths(maskJSONObjectKeys(record.RequestBody, secretParams), r.requestBodyPaths)
+	record.ResponseHeaders = r.maskHeaders(record.ResponseHeaders, nil)
+	record.ResponseBody = maskJSONPaths(record.ResponseBody, r.responseBodyPaths)
+	record.RedirectChain = maskRedirectChainJSON(record.RedirectChain)
+	record.Attempts = maskAttemptsJSON(record.Attempts)
+	record.ErrorMessage = maskURLsInText(record.ErrorMessage)
+}
+
+// RedactResponse 脱敏返回给调用方的执行结果中的重定向链、重试记录与错误信息，不修改响应体
+func (r *SecretRedactor) RedactResponse(response *dto.ApiExecuteResponseDto) {
+	response.RedirectChain = maskRedirectHops(response.RedirectChain)
+	response.Attempts = maskAttempts(response.Attempts)
+	response.Error = maskURLsInText(response.Error)
+}
+
+// RestoreRecord 使用加密保存的原始内容还原执行记录
+func (r *SecretRedactor) RestoreRecord(record *entity.ApiInterfaceExecutionRecord) error {
+	if record.RawPayload == nil || *record.RawPayload == "" {
+		return nil
+	}
+	decrypted, err := r.cipher.Decrypt(*record.RawPayload)
+	if err != nil {
+		return fmt.Errorf("解密执行记录原始内容失败: %w", err)
+	}
+	var raw executionRecordRawPayload
+	if err := json.Unmarshal([]byte(decrypted), &raw); err != nil {
+		return fmt.Errorf("解析执行记录原始内容失败: %w", err)
+	}
+	record.RequestParams = raw.RequestParams
+	record.RequestPathParams = raw.RequestPathParams
+	record.RequestHeaders = raw.RequestHeaders
+	record.RequestBody = raw.RequestBody
+	record.ResponseHeaders = raw.ResponseHeaders
+	record.ResponseBody = raw.ResponseBody
+	// 早期记录的原始内容不包含以下字段
+	if raw.RedirectChain != nil {
+		record.RedirectChain = raw.RedirectChain
+	}
+	if raw.Attempts != nil {
+		record.Attempts = raw.Attempts
+	}
+	if raw.ErrorMessage != nil {
+		record.ErrorMessage = raw.ErrorMessage
+	}
+	return nil
+}
+
+// RedactDatasetRow 脱敏批量执行数据集中的一行：PASSWORD类型参数及配置的敏感请求头对应的列
+func (r *SecretRedactor) RedactDatasetRow(row map[string]any, secretParams map[string]bool) map[string]any {
+	if row == nil {
+		return nil
+	}
+	masked := make(map[string]any, len(row))
+	for column, value := range row {
+		if value != nil && (secretParams[column] || r.headers[strings.ToLower(column)]) {
+			value = redactionMask
+		}
+		masked[column] = value
+	}
+	return masked
+}
+
+// RedactExtractedValue 脱敏从响应体提取的值
+// 按响应体规则脱敏后重新提取，响应体无法解析或提取失败时整体脱敏
+func (r *SecretRedactor) RedactExtractedValue(body *string, valuePath string, value *string) *string {
+	if value == nil || !r.redactsResponseBody() {
+		return value
+	}
+	masked := maskJSONPaths(body, r.responseBodyPaths)
+	if masked == nil {
+		return stringPtr(redactionMask)
+	}
+	var data any
+	if err := json.Unmarshal([]byte(*masked), &data); err != nil {
+		return stringPtr(redactionMask)
+	}
+	result, err := jsonpath.Get(valuePath, data)
+	if err != nil {
+		return stringPtr(redactionMask)
+	}
+	return stringPtr(formatJSONValue(result))
+}
+
+// redactsResponseBody 是否配置了响应体脱敏规则
+func (r *SecretRedactor) redactsResponseBody() bool {
+	return len(r.responseBodyPaths) > 0
+}
+
+// maskHeaders 脱敏请求头/响应头（按配置名称及额外的敏感参数名）
+func (r *SecretRedactor) maskHeaders(headersJSON *string, extra map[string]bool) *string {
+	if headersJSON == nil || *headersJSON == "" {
+		return headersJSON
+	}
+	var headers map[string]any
+	if err := json.Unmarshal([]byte(*headersJSON), &headers); err != nil || headers == nil {
+		return headersJSON
+	}
+	changed := false
+	for name, value := range headers {
+		if value == nil {
+			continue
+		}
+		if r.headers[strings.ToLower(name)] || extra[name] {
+			headers[name] = redactionMask
+			changed = true
+		}
+	}
+	if !changed {
+		return headersJSON
+	}
+	return marshalRedacted(headers)
+}
+
+// passwordParamNames 收集接口中输入类型为PASSWORD的参数名
+func passwordParamNames(apiInterface *entity.ApiInterface) map[string]bool {
+	names := make(map[string]bool)
+	if apiInterface == nil {
+		return names
+	}
+	for _, param := range parseInterfaceParams(apiInterface) {
+		if param.Name == nil || *param.Name == "" || param.InputType == nil {
+			continue
+		}
+		if *param.InputType == enums.InputTypePASSWORD.Code() {
+			names[*param.Name] = true
+		}
+	}
+	return names
+}
+
+// maskJSONObjectKeys 脱敏JSON对象中指定名称的顶层字段
+func maskJSONObjectKeys(jsonStr *string, keys map[string]bool) *string {
+	if jsonStr == nil || *jsonStr == "" || len(keys) == 0 {
+		return jsonStr
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(*jsonStr), &data); err != nil || data == nil {
+		return jsonStr
+	}
+	changed := false
+	for key, value := range data {
+		if value != nil && keys[key] {
+			data[key] = redactionMask
+			changed = true
+		}
+	}
+	if !changed {
+		return jsonStr
+	}
+	return marshalRedacted(data)
+}
+
+// maskJSONPaths 按JSONPath规则脱敏JSON文本
+// 无法解析的内容（非JSON或已截断的JSON）无法定位敏感字段，整体脱敏
+func maskJSONPaths(jsonStr *string, paths []string) *string {
+	if jsonStr == nil || *jsonStr == "" || len(paths) == 0 {
+		return jsonStr
+	}
+	var data any
+	if err := json.Unmarshal([]byte(*jsonStr), &data); err != nil {
+		return stringPtr(redactionMask)
+	}
+	changed := false
+	for _, path := range paths {
+		tokens, err := parseRedactionPath(path)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "脱敏规则JSONPath无效 %s: %v\n", path, err)
+			continue
+		}
+		var masked bool
+		data, masked = maskJSONNode(data, tokens)
+		changed = changed || masked
+	}
+	if !changed {
+		return jsonStr
+	}
+	return marshalRedacted(data)
+}
+
+// maskRedirectChainJSON 脱敏JSON格式的重定向链，无法解析时整体脱敏
+func maskRedirectChainJSON(chainJSON *string) *string {
+	if chainJSON == nil || *chainJSON == "" {
+		return chainJSON
+	}
+	var hops []dto.ApiRedirectHopDto
+	if err := json.Unmarshal([]byte(*chainJSON), &hops); err != nil {
+		return stringPtr(redactionMask)
+	}
+	return marshalRedacted(maskRedirectHops(hops))
+}
+
+// maskAttemptsJSON 脱敏JSON格式的重试记录，无法解析时整体脱敏
+func maskAttemptsJSON(attemptsJSON *string) *string {
+	if attemptsJSON == nil || *attemptsJSON == "" {
+		return attemptsJSON
+	}
+	var attempts []dto.ApiExecuteAttemptDto
+	if err := json.Unmarshal([]byte(*attemptsJSON), &attempts); err != nil {
+		return stringPtr(redactionMask)
+	}
+	return marshalRedacted(maskAttempts(attempts))
+}
+
+// maskRedirectHops 脱敏重定向链中的地址，返回新的切片
+func maskRedirectHops(hops []dto.ApiRedirectHopDto) []dto.ApiRedirectHopDto {
+	if hops == nil {
+		return nil
+	}
+	masked := make([]dto.ApiRedirectHopDto, len(hops))
+	for i, hop := range hops {
+		hop.URL = maskURL(hop.URL)
+		hop.Location = maskURL(hop.Location)
+		masked[i] = hop
+	}
+	return masked
+}
+
+// maskAttempts 脱敏重试记录错误信息中的地址，返回新的切片
+func maskAttempts(attempts []dto.ApiExecuteAttemptDto) []dto.ApiExecuteAttemptDto {
+	if attempts == nil {
+		return nil
+	}
+	masked := make([]dto.ApiExecuteAttemptDto, len(attempts))
+	for i, attempt := range attempts {
+		attempt.Error = maskURLsInText(attempt.Error)
+		masked[i] = attempt
+	}
+	return masked
+}
+
+// maskURLsInText 脱敏文本中出现的请求地址
+func maskURLsInText(text *string) *string {
+	if text == nil || *text == "" {
+		return text
+	}
+	return stringPtr(redactionURLPattern.ReplaceAllStringFunc(*text, maskURL))
+}
+
+// redactExecutionError 脱敏接口执行的错误信息：HTTP失败只保留状态码（早期记录会将失败响应体写入错误信息），
+// 其余错误脱敏其中的地址
+func redactExecutionError(status int, message *string) *string {
+	if message == nil {
+		return nil
+	}
+	if status >= http.StatusBadRequest {
+		return stringPtr(fmt.Sprintf("HTTP %d", status))
+	}
+	return maskURLsInText(message)
+}
+
+// maskURL 脱敏地址中的查询参数值与用户密码，保留参数名便于排查；无法解析时整体脱敏
+func maskURL(rawURL string) string {
+	if rawURL == "" {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redactionMask
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactionMask)
+	}
+	if u.RawQuery != "" {
+		pairs := strings.Split(u.RawQuery, "&")
+		for i, pair := range pairs {
+			if name, _, found := strings.Cut(pair, "="); found {
+				pairs[i] = name + "=" + redactionMask
+			}
+		}
+		u.RawQuery = strings.Join(pairs, "&")
+	}
+	return u.String()
+}
+
+// marshalRedacted 序列化脱敏后的数据，失败时整体脱敏
+func marshalRedacted(data any) *string {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "序列化脱敏内容失败: %v\n", err)
+		return stringPtr(redactionMask)
+	}
+	return stringPtr(string(jsonBytes))
+}
+
+// redactionPathToken JSONPath片段
+type redactionPathToken struct {
+	key       string
+	index     int
+	wildcard  bool
+	recursive bool
+	isIndex   bool
+}
+
+// parseRedactionPath 解析脱敏用的JSONPath，支持 $.a.b、$['a']、$.a[0]、$.a[*]、$..a
+func parseRedactionPath(path string) ([]redactionPathToken, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath必须以$开头")
+	}
+	tokens := make([]redactionPathToken, 0)
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			name, remain := readPathName(rest[2:])
+			if name == "" {
+				return nil, fmt.Errorf("递归匹配缺少字段名")
+			}
+			tokens = append(tokens, redactionPathToken{key: name, recursive: true, wildcard: name == "*"})
+			rest = remain
+		case strings.HasPrefix(rest, "."):
+			name, remain := readPathName(rest[1:])
+			if name == "" {
+				return nil, fmt.Errorf("缺少字段名")
+			}
+			tokens = append(tokens, redactionPathToken{key: name, wildcard: name == "*"})
+			rest = remain
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("缺少]")
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				tokens = append(tokens, redactionPathToken{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				tokens = append(tokens, redactionPathToken{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("无效的下标: %s", inner)
+				}
+				tokens = append(tokens, redactionPathToken{index: index, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("无法解析: %s", rest)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("不能对根节点脱敏")
+	}
+	return tokens, nil
+}
+
+// readPathName 读取点号后的字段名
+func readPathName(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// maskJSONNode 按路径片段脱敏节点，返回新节点及是否发生脱敏
+func maskJSONNode(node any, tokens []redactionPathToken) (any, bool) {
+	if len(tokens) == 0 {
+		if node == nil {
+			return node, false
+		}
+		return redactionMask, true
+	}
+	token, rest := tokens[0], tokens[1:]
+	changed := false
+
+	if token.recursive {
+		// 递归匹配：当前层按普通字段匹配，同时继续向下查找
+		direct := redactionPathToken{key: token.key, wildcard: token.wildcard}
+		var masked bool
+		node, masked = maskJSONNode(node, append([]redactionPathToken{direct}, rest...))
+		changed = masked
+		switch value := node.(type) {
+		case map[string]any:
+			for k, child := range value {
+				if child, masked = maskJSONNode(child, tokens); masked {
+					value[k] = child
+					changed = true
+				}
+			}
+		case []any:
+			for i, child := range value {
+				if child, masked = maskJSONNode(child, tokens); masked {
+					value[i] = child
+					changed = true
+				}
+			}
+		}
+		return node, changed
+	}
+
+	switch value := node.(type) {
+	case map[string]any:
+		if token.isIndex {
+			return node, false
+		}
+		for k, child := range value {
+			if !token.wildcard && k != token.key {
+				continue
+			}
+			if child, masked := maskJSONNode(child, rest); masked {
+				value[k] = child
+				changed = true
+			}
+		}
+	case []any:
+		for i, child := range value {
+			if !token.wildcard && (!token.isIndex || i != token.index) {
+				continue
+			}
+			if child, masked := maskJSONNode(child, rest); masked {
+				value[i] = child
+				changed = true
+			}
+		}
+	}
+	return node, changed
+}
+
+// sameStringPtr 比较两个字符串指针的值是否相同
+func sameStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/util"
+)
+
+// newTestSecretCipher 创建使用测试密钥的敏感信息加密器
+func newTestSecretCipher(t *testing.T) *util.SecretCipher {
+	t.Helper()
+	cipher, err := util.NewSecretCipher("test-secret-key")
+	if err != nil {
+		t.Fatalf("NewSecretCipher() error: %v", err)
+	}
+	return cipher
+}
+
+func TestParseRedactionPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    []redactionPathToken
+		wantErr bool
+	}{
+		{"点号", "$.data.token", []redactionPathToken{{key: "data"}, {key: "token"}}, false},
+		{"中括号字段名", "$['data'][\"access-token\"]", []redactionPathToken{{key: "data"}, {key: "access-token"}}, false},
+		{"下标", "$.items[2].secret", []redactionPathToken{{key: "items"}, {index: 2, isIndex: true}, {key: "secret"}}, false},
+		{"通配符", "$.items[*].secret", []redactionPathToken{{key: "items"}, {wildcard: true}, {key: "secret"}}, false},
+		{"点号通配符", "$.data.*", []redactionPathToken{{key: "data"}, {key: "*", wildcard: true}}, false},
+		{"递归", "$..password", []redactionPathToken{{key: "password", recursive: true}}, false},
+		{"首尾空白", "  $.token  ", []redactionPathToken{{key: "token"}}, false},
+		{"缺少$", "data.token", nil, true},
+		{"根节点", "$", nil, true},
+		{"缺少字段名", "$.data.", nil, true},
+		{"递归缺少字段名", "$..", nil, true},
+		{"缺少]", "$.items[0", nil, true},
+		{"无效下标", "$.items[a]", nil, true},
+		{"无法解析", "$data", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRedactionPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRedactionPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRedactionPath(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskJSONPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		paths []string
+		want  string
+	}{
+		{"嵌套字段", `{"data":{"token":"abc","name":"n"}}`, []string{"$.data.token"}, `{"data":{"name":"n","token":"******"}}`},
+		{"数组通配", `{"items":[{"secret":"a"},{"secret":"b"}]}`, []string{"$.items[*].secret"}, `{"items":[{"secret":"******"},{"secret":"******"}]}`},
+		{"数组下标", `{"items":[{"secret":"a"},{"secret":"b"}]}`, []string{"$.items[1].secret"}, `{"items":[{"secret":"a"},{"secret":"******"}]}`},
+		{"递归", `{"password":"a","user":{"password":"b"},"list":[{"password":"c"}]}`, []string{"$..password"}, `{"list":[{"password":"******"}],"password":"******","user":{"password":"******"}}`},
+		{"null不脱敏", `{"token":null}`, []string{"$.token"}, `{"token":null}`},
+		{"未命中保持原文", `{ "a": 1 }`, []string{"$.token"}, `{ "a": 1 }`},
+		{"无效规则跳过", `{"token":"abc"}`, []string{"token", "$.token"}, `{"token":"******"}`},
+		{"截断的JSON整体脱敏", `{"token":"abc","data":` + "\n...[响应体已截断]", []string{"$.token"}, redactionMask},
+		{"非JSON整体脱敏", `token=abc`, []string{"$.token"}, redactionMask},
+		{"无规则保持原文", `token=abc`, nil, `token=abc`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := maskJSONPaths(stringPtr(tt.body), tt.paths)
+			if got == nil || *got != tt.want {
+				t.Errorf("maskJSONPaths(%q) = %v, want %q", tt.body, stringValue(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskJSONObjectKeys(t *testing.T) {
+	keys := map[string]bool{"password": true}
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"顶层字段", `{"password":"p","name":"n"}`, `{"name":"n","password":"******"}`},
+		{"只处理顶层", `{"user":{"password":"p"}}`, `{"user":{"password":"p"}}`},
+		{"非对象保持原文", `["password"]`, `["password"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := maskJSONObjectKeys(stringPtr(tt.body), keys)
+			if got == nil || *got != tt.want {
+				t.Errorf("maskJSONObjectKeys(%q) = %v, want %q", tt.body, stringValue(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretRedactorMaskHeaders(t *testing.T) {
+	redactor := &SecretRedactor{headers: map[string]bool{"authorization": true}}
+	got := redactor.maskHeaders(stringPtr(`{"Authorization":"Bearer x","X-Token":"t","Accept":"*/*"}`), map[string]bool{"X-Token": true})
+	want := `{"Accept":"*/*","Authorization":"******","X-Token":"******"}`
+	if got == nil || *got != want {
+		t.Errorf("maskHeaders() = %v, want %q", stringValue(got), want)
+	}
+}
+
+func TestSecretRedactorRedactDatasetRow(t *testing.T) {
+	redactor := &SecretRedactor{headers: map[string]bool{"authorization": true}}
+	row := map[string]any{"password": "p", "Authorization": "Bearer x", "name": "n", "empty": nil}
+	got := redactor.RedactDatasetRow(row, map[string]bool{"password": true, "empty": true})
+	want := map[string]any{"password": redactionMask, "Authorization": redactionMask, "name": "n", "empty": nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactDatasetRow() = %v, want %v", got, want)
+	}
+	if row["password"] != "p" {
+		t.Errorf("RedactDatasetRow() 修改了原始行数据")
+	}
+}
+
+func TestSecretRedactorRedactExtractedValue(t *testing.T) {
+	redactor := &SecretRedactor{responseBodyPaths: []string{"$.data.token"}}
+	tests := []struct {
+		name      string
+		body      *string
+		valuePath string
+		value     *string
+		want      *string
+	}{
+		{"取值被脱敏", stringPtr(`{"data":{"token":"abc"}}`), "$.data.token", stringPtr("abc"), stringPtr(redactionMask)},
+		{"取值包含脱敏字段", stringPtr(`{"data":{"token":"abc","id":1}}`), "$.data", stringPtr(`{"id":1,"token":"abc"}`), stringPtr(`{"id":1,"token":"******"}`)},
+		{"取值不敏感", stringPtr(`{"data":{"token":"abc","id":1}}`), "$.data.id", stringPtr("1"), stringPtr("1")},
+		{"响应体已截断", stringPtr(`{"data":{"token":"abc"`), "$.data.token", stringPtr("abc"), stringPtr(redactionMask)},
+		{"没有响应体", nil, "$.data.token", stringPtr("abc"), stringPtr(redactionMask)},
+		{"没有取值", stringPtr(`{"data":{"token":"abc"}}`), "$.data.token", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactor.RedactExtractedValue(tt.body, tt.valuePath, tt.value)
+			if !sameStringPtr(got, tt.want) {
+				t.Errorf("RedactExtractedValue() = %v, want %v", stringValue(got), stringValue(tt.want))
+			}
+		})
+	}
+
+	noRules := &SecretRedactor{}
+	if got := noRules.RedactExtractedValue(nil, "$.token", stringPtr("abc")); stringValue(got) != "abc" {
+		t.Errorf("未配置规则时 RedactExtractedValue() = %v, want abc", stringValue(got))
+	}
+}
+
+func TestSecretRedactorRestoreRecord(t *testing.T) {
+	redactor := &SecretRedactor{headers: map[string]bool{"authorization": true}, cipher: newTestSecretCipher(t)}
+	record := &entity.ApiInterfaceExecutionRecord{
+		RequestHeaders: stringPtr(`{"Authorization":"Bearer secret-token"}`),
+		ErrorMessage:   stringPtr(`Get "https://a/b?api_key=secret-key": timeout`),
+	}
+	redactor.RedactRecord(record, &entity.ApiInterface{})
+	if stringValue(record.RequestHeaders) == `{"Authorization":"Bearer secret-token"}` || stringValue(record.RawPayload) == "" {
+		t.Fatalf("RedactRecord() 未脱敏或未保存原始内容")
+	}
+	if legacy, err := util.Decrypt(stringValue(record.RawPayload)); err == nil && legacy != "" {
+		t.Errorf("原始内容不应使用默认密钥加密")
+	}
+
+	if err := redactor.RestoreRecord(record); err != nil {
+		t.Fatalf("RestoreRecord() error: %v", err)
+	}
+	if got := stringValue(record.RequestHeaders); got != `{"Authorization":"Bearer secret-token"}` {
+		t.Errorf("还原的请求头 = %s", got)
+	}
+	if got := stringValue(record.ErrorMessage); got != `Get "https://a/b?api_key=secret-key": timeout` {
+		t.Errorf("还原的错误信息 = %s", got)
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bucketheadv/infra-market/internal/config"
+	"github.com/bucketheadv/infra-market/internal/dto"
+)
+
+// responseTruncatedMarker 文本响应体截断标记
+const responseTruncatedMarker = "\n...[响应体已截断，保存 %d 字节，已读取 %d 字节]"
+
+// responseBlobKeyPattern 本地保存的响应体文件名（SHA-256）
+var responseBlobKeyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// textMediaTypes 按文本处理的非 text/* 媒体类型
+var textMediaTypes = map[string]bool{
+	"application/json":                  true,
+	"application/xml":                   true,
+	"application/javascript":            true,
+	"application/ecmascript":            true,
+	"application/x-www-form-urlencoded": true,
+	"application/graphql":               true,
+	"application/x-ndjson":              true,
+	"application/yaml":                  true,
+	"application/x-yaml":                true,
+	"application/problem+json":          true,
+}
+
+// ResponseBodyStore 响应体读取与保存
+// 规则：内存中最多保留 MaxBodySize 字节，超出部分截断；二进制内容只保存元信息与哈希；
+// 配置了本地目录时，二进制或被截断的完整响应体按 SHA-256 保存，便于下载；
+// 执行记录删除后不再被引用的文件由执行记录服务定期清理
+type ResponseBodyStore struct {
+	maxBodySize         int64
+	blobDir             string
+	maxBlobSize         int64
+	imagePreviewMaxSize int64
+}
+
+func NewResponseBodyStore(cfg *config.Config) *ResponseBodyStore {
+	return &ResponseBodyStore{
+		maxBodySize:         cfg.Response.MaxBodySize,
+		blobDir:             strings.TrimSpace(cfg.Response.BlobDir),
+		maxBlobSize:         cfg.Response.MaxBlobSize,
+		imagePreviewMaxSize: cfg.Response.ImagePreviewMaxSize,
+	}
+}
+
+// Read 读取响应体，返回保存到执行记录的文本（二进制内容为 nil）与元信息
+func (s *ResponseBodyStore) Read(body io.Reader, contentType string) (*string, *dto.ApiResponseBodyInfoDto, error) {
+	readLimit := s.maxBodySize
+	var blob *os.File
+	if s.blobDir != "" {
+		if s.maxBlobSize > readLimit {
+			readLimit = s.maxBlobSize
+		}
+		if err := os.MkdirAll(s.blobDir, 0o755); err != nil {
+			return nil, nil, fmt.Errorf("创建响应体保存目录失败: %w", err)
+		}
+		file, err := os.CreateTemp(s.blobDir, "tmp-*")
+		if err != nil {
+			return nil, nil, fmt.Errorf("创建响应体临时文件失败: %w", err)
+		}
+		blob = file
+		defer func() {
+			blob.Close()
+			os.Remove(blob.Name())
+		}()
+	}
+
+	hasher := sha256.New()
+	head := &headBuffer{max: s.maxBodySize}
+	writers := []io.Writer{hasher, head}
+	if blob != nil {
+		writers = append(writers, blob)
+	}
+	size, err := io.Copy(io.MultiWriter(writers...), io.LimitReader(body, readLimit))
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+	// 再读取一个字节判断响应体是否已读完
+	complete := true
+	if _, err := io.ReadFull(body, make([]byte, 1)); err == nil {
+		complete = false
+	}
+
+	sample := head.Bytes()
+	if contentType == "" && size > 0 {
+		contentType = http.DetectContentType(sample)
+	}
+	info := &dto.ApiResponseBodyInfoDto{
+		ContentType: contentType,
+		Size:        size,
+		Complete:    complete,
+		Binary:      isBinaryContent(contentType, sample),
+		SHA256:      hex.EncodeToString(hasher.Sum(nil)),
+	}
+
+	var text *string
+	if !info.Binary {
+		info.Truncated = size > s.maxBodySize || !complete
+		value := string(sample)
+		if info.Truncated {
+			kept := trimPartialRune(sample)
+			value = string(kept) + fmt.Sprintf(responseTruncatedMarker, len(kept), size)
+		}
+		text = &value
+	} else if preview := s.imagePreview(contentType, sample, size, complete); preview != nil {
+		info.Preview = preview
+	}
+
+	if blob != nil && complete && (info.Binary || info.Truncated) {
+		if err := s.saveBlob(blob, info.SHA256); err != nil {
+			return nil, nil, err
+		}
+		info.BlobStored = true
+		info.BlobKey = info.SHA256
+	}
+	return text, info, nil
+}
+
+// Open 打开本地保存的响应体
+func (s *ResponseBodyStore) Open(key string) (*os.File, error) {
+	if s.blobDir == "" {
+		return nil, fmt.Errorf("未配置响应体保存目录")
+	}
+	if !responseBlobKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("无效的响应体文件名")
+	}
+	return os.Open(filepath.Join(s.blobDir, key))
+}
+
+// ListBlobs 返回修改时间早于 before 的响应体文件名，并删除同样过期的临时文件（异常退出时遗留）；未配置保存目录时返回空
+func (s *ResponseBodyStore) ListBlobs(before time.Time) ([]string, error) {
+	if s.blobDir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(s.blobDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取响应体保存目录失败: %w", err)
+	}
+	keys := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+		switch name := entry.Name(); {
+		case responseBlobKeyPattern.MatchString(name):
+			keys = append(keys, name)
+		case strings.HasPrefix(name, "tmp-"):
+			os.Remove(filepath.Join(s.blobDir, name))
+		}
+	}
+	return keys, nil
+}
+
+// Remove 删除本地保存的响应体，文件不存在时忽略
+func (s *ResponseBodyStore) Remove(key string) error {
+	if s.blobDir == "" || !responseBlobKeyPattern.MatchString(key) {
+		return fmt.Errorf("无效的响应体文件名")
+	}
+	if err := os.Remove(filepath.Join(s.blobDir, key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// saveBlob 将临时文件按哈希命名保存，内容相同的响应体只保存一份
+// 文件已存在时刷新修改时间，避免在新的执行记录保存前被当作未引用的文件清理
+func (s *ResponseBodyStore) saveBlob(blob *os.File, key string) error {
+	target := filepath.Join(s.blobDir, key)
+	if _, err := os.Stat(target); err == nil {
+		now := time.Now()
+		if err := os.Chtimes(target, now, now); err != nil {
+			return fmt.Errorf("保存响应体失败: %w", err)
+		}
+		return nil
+	}
+	if err := blob.Sync(); err != nil {
+		return fmt.Errorf("保存响应体失败: %w", err)
+	}
+	blob.Close()
+	if err := os.Rename(blob.Name(), target); err != nil {
+		return fmt.Errorf("保存响应体失败: %w", err)
+	}
+	return nil
+}
+
+// imagePreview 为完整读取的小图片生成 data URI
+func (s *ResponseBodyStore) imagePreview(contentType string, data []byte, size int64, complete bool) *string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !strings.HasPrefix(mediaType, "image/") || !complete || s.imagePreviewMaxSize <= 0 || size > s.imagePreviewMaxSize || int64(len(data)) != size {
+		return nil
+	}
+	preview := "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	return &preview
+}
+
+// isBinaryContent 判断响应体是否为二进制内容：优先按媒体类型判断，无法判断时检查内容是否为有效的UTF-8文本
+func isBinaryContent(contentType string, sample []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	mediaType = strings.ToLower(mediaType)
+	switch {
+	case mediaType == "":
+	case strings.HasPrefix(mediaType, "text/"), textMediaTypes[mediaType],
+		strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return false
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "font/"),
+		mediaType == "application/octet-stream", mediaType == "application/pdf",
+		mediaType == "application/zip", mediaType == "application/gzip", mediaType == "application/x-protobuf":
+		return true
+	}
+	sample = trimPartialRune(sample)
+	return !utf8.Valid(sample) || bytes.IndexByte(sample, 0) >= 0
+}
+
+// trimPartialRune 去掉截断时末尾不完整的UTF-8字符
+func trimPartialRune(data []byte) []byte {
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			return data
+		}
+		data = data[:len(data)-1]
+	}
+	return data
+}
+
+// headBuffer 只保留前 max 字节的写入缓冲
+type headBuffer struct {
+	buf bytes.Buffer
+	max int64
+}
+
+func (b *headBuffer) Write(p []byte) (int, error) {
+	if remain := b.max - int64(b.buf.Len()); remain > 0 {
+		if int64(len(p)) > remain {
+			b.buf.Write(p[:remain])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *headBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
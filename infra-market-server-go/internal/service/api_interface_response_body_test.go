@@ -0,0 +1,100 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseBodyStoreListBlobs(t *testing.T) {
+	dir := t.TempDir()
+	s := &ResponseBodyStore{blobDir: dir}
+	old := time.Now().Add(-2 * time.Hour)
+	oldKey := strings.Repeat("a", 64)
+	newKey := strings.Repeat("b", 64)
+	for name, modTime := range map[string]time.Time{
+		oldKey:      old,
+		newKey:      time.Now(),
+		"tmp-1":     old,
+		"tmp-2":     time.Now(),
+		"other.txt": old,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("body"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := s.ListBlobs(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListBlobs() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0] != oldKey {
+		t.Fatalf("ListBlobs() = %v, want [%s]", keys, oldKey)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tmp-1")); !os.IsNotExist(err) {
+		t.Error("过期的临时文件应被删除")
+	}
+	for _, name := range []string{"tmp-2", "other.txt", newKey} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s 不应被删除: %v", name, err)
+		}
+	}
+}
+
+func TestResponseBodyStoreRemove(t *testing.T) {
+	dir := t.TempDir()
+	s := &ResponseBodyStore{blobDir: dir}
+	key := strings.Repeat("c", 64)
+	if err := os.WriteFile(filepath.Join(dir, key), []byte("body"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Remove(key); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, key)); !os.IsNotExist(err) {
+		t.Error("响应体文件应被删除")
+	}
+	if err := s.Remove(key); err != nil {
+		t.Errorf("重复删除不应报错: %v", err)
+	}
+	if err := s.Remove("../" + key); err == nil {
+		t.Error("非法文件名应返回错误")
+	}
+}
+
+func TestResponseBodyStoreSaveBlobRefreshesExisting(t *testing.T) {
+	dir := t.TempDir()
+	s := &ResponseBodyStore{blobDir: dir}
+	key := strings.Repeat("d", 64)
+	target := filepath.Join(dir, key)
+	if err := os.WriteFile(target, []byte("body"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(target, old, old); err != nil {
+		t.Fatal(err)
+	}
+	blob, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blob.Close()
+
+	if err := s.saveBlob(blob, key); err != nil {
+		t.Fatalf("saveBlob() error = %v", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().After(old.Add(time.Hour)) {
+		t.Error("复用已有文件时应刷新修改时间")
+	}
+}
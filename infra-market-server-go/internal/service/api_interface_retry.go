@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// maxRetryAttempts 最大请求次数（含首次请求）
+	maxRetryAttempts = 10
+	// defaultRetryInitialBackoff 默认首次重试的退避时间
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	// defaultRetryMaxBackoff 默认最大退避时间
+	defaultRetryMaxBackoff = 10 * time.Second
+	// maxRetryBackoff 允许配置的最大退避时间
+	maxRetryBackoff = 5 * time.Minute
+	// maxRetryDuration 重试的总时长上限（从首次请求开始计算），剩余时间不足以等待下一次重试时不再重试
+	maxRetryDuration = 2 * time.Minute
+	// retryDiscardBodyLimit 重试前丢弃响应体时最多读取的字节数，读完的连接可以复用
+	retryDiscardBodyLimit = 64 << 10
+)
+
+// retryPolicy 执行时使用的重试策略
+type retryPolicy struct {
+	maxAttempts         int
+	retryOnStatus       map[int]bool
+	retryOnNetworkError bool
+	initialBackoff      time.Duration
+	maxBackoff          time.Duration
+}
+
+// parseRetryPolicy 解析接口的重试策略，未配置或最多只请求一次时返回 nil
+func parseRetryPolicy(apiInterface *entity.ApiInterface) *retryPolicy {
+	config := parseInterfaceRetryPolicy(apiInterface)
+	if config == nil || config.MaxAttempts <= 1 {
+		return nil
+	}
+
+	policy := &retryPolicy{
+		maxAttempts:         min(config.MaxAttempts, maxRetryAttempts),
+		retryOnStatus:       make(map[int]bool, len(config.RetryOnStatus)),
+		retryOnNetworkError: config.RetryOnNetworkError,
+		initialBackoff:      defaultRetryInitialBackoff,
+		maxBackoff:          defaultRetryMaxBackoff,
+	}
+	for _, status := range config.RetryOnStatus {
+		policy.retryOnStatus[status] = true
+	}
+	if config.InitialBackoff > 0 {
+		policy.initialBackoff = time.Duration(config.InitialBackoff) * time.Millisecond
+	}
+	if config.MaxBackoff > 0 {
+		policy.maxBackoff = time.Duration(config.MaxBackoff) * time.Millisecond
+	}
+	policy.initialBackoff = min(policy.initialBackoff, policy.maxBackoff)
+	return policy
+}
+
+// next 判断第 attempt 次请求后是否需要重试，返回重试前的等待时间
+func (p *retryPolicy) next(attempt int, resp *resty.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.maxAttempts {
+		return 0, false
+	}
+	if err != nil {
+		return p.backoff(attempt), p.retryOnNetworkError
+	}
+	if resp == nil || !p.retryOnStatus[resp.StatusCode()] {
+		return 0, false
+	}
+	// 服务端要求的等待时间超过最大退避时间时不再重试
+	if delay, ok := parseRetryAfter(resp.Header().Get("Retry-After"), time.Now()); ok {
+		return delay, delay <= p.maxBackoff
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff 第 attempt 次请求失败后的退避时间：指数增长，取上限后在后一半区间内随机抖动
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.maxBackoff
+	if shift := attempt - 1; shift < 32 {
+		if grown := p.initialBackoff << shift; grown > 0 && grown < delay {
+			delay = grown
+		}
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter 解析 Retry-After 响应头，支持秒数与HTTP日期两种格式
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// retryWithinDeadline 判断等待 delay 后是否仍在 ctx 的截止时间之前
+func retryWithinDeadline(ctx context.Context, delay time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || !time.Now().Add(delay).After(deadline)
+}
+
+// waitRetry 等待重试，ctx 取消时提前返回 false
+func waitRetry(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// newExecuteAttempt 记录单次请求的结果
+func newExecuteAttempt(attempt int, delay, elapsed time.Duration, resp *resty.Response, err error) dto.ApiExecuteAttemptDto {
+	result := dto.ApiExecuteAttemptDto{
+		Attempt:      attempt,
+		ResponseTime: elapsed.Milliseconds(),
+		Delay:        delay.Milliseconds(),
+	}
+	if err != nil {
+		result.Error = basic.Ptr(err.Error())
+	} else if resp != nil {
+		result.Status = resp.StatusCode()
+	}
+	return result
+}
+
+// discardResponseBody 重试前丢弃并关闭需要重试的响应体
+func discardResponseBody(resp *resty.Response) {
+	if body := resp.RawBody(); body != nil {
+		io.Copy(io.Discard, io.LimitReader(body, retryDiscardBodyLimit))
+		body.Close()
+	}
+}
+
+// parseInterfaceRetryPolicy 解析接口保存的重试策略
+func parseInterfaceRetryPolicy(apiInterface *entity.ApiInterface) *dto.ApiRetryPolicyDto {
+	if apiInterface.RetryPolicy == nil || *apiInterface.RetryPolicy == "" {
+		return nil
+	}
+	var policy dto.ApiRetryPolicyDto
+	if err := json.Unmarshal([]byte(*apiInterface.RetryPolicy), &policy); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析接口重试策略失败: %v\n", err)
+		return nil
+	}
+	return &policy
+}
+
+// validateRetryPolicy 校验重试策略
+func validateRetryPolicy(policy *dto.ApiRetryPolicyDto) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAttempts < 1 || policy.MaxAttempts > maxRetryAttempts {
+		return fmt.Errorf("最大请求次数需在 1 到 %d 之间", maxRetryAttempts)
+	}
+	if policy.MaxAttempts > 1 && len(policy.RetryOnStatus) == 0 && !policy.RetryOnNetworkError {
+		return fmt.Errorf("请配置需要重试的状态码或开启网络错误重试")
+	}
+	for _, status := range policy.RetryOnStatus {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("无效的重试状态码: %d", status)
+		}
+	}
+	if policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+		return fmt.Errorf("退避时间不能小于0")
+	}
+	if policy.MaxBackoff > maxRetryBackoff.Milliseconds() || policy.InitialBackoff > maxRetryBackoff.Milliseconds() {
+		return fmt.Errorf("退避时间不能超过 %d 毫秒", maxRetryBackoff.Milliseconds())
+	}
+	if policy.MaxBackoff > 0 && policy.InitialBackoff > policy.MaxBackoff {
+		return fmt.Errorf("首次退避时间不能大于最大退避时间")
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &retryPolicy{initialBackoff: 200 * time.Millisecond, maxBackoff: 10 * time.Second}
+	tests := []struct {
+		name    string
+		attempt int
+		max     time.Duration
+	}{
+		{"首次重试", 1, 200 * time.Millisecond},
+		{"指数增长", 3, 800 * time.Millisecond},
+		{"达到上限", 7, 10 * time.Second},
+		{"移位溢出时取上限", 64, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				got := policy.backoff(tt.attempt)
+				if got < tt.max/2 || got > tt.max {
+					t.Fatalf("backoff(%d) = %v, want [%v, %v]", tt.attempt, got, tt.max/2, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"秒数", "120", 2 * time.Minute, true},
+		{"零秒", "0", 0, true},
+		{"首尾空白", " 5 ", 5 * time.Second, true},
+		{"HTTP日期", "Sat, 17 Oct 2026 10:00:30 GMT", 30 * time.Second, true},
+		{"过去的日期", "Sat, 17 Oct 2026 09:00:00 GMT", 0, true},
+		{"负数", "-1", 0, false},
+		{"空值", "", 0, false},
+		{"无法解析", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNext(t *testing.T) {
+	policy := &retryPolicy{
+		maxAttempts:         3,
+		retryOnStatus:       map[int]bool{503: true},
+		retryOnNetworkError: true,
+		initialBackoff:      100 * time.Millisecond,
+		maxBackoff:          time.Second,
+	}
+	response := func(status int, retryAfter string) *resty.Response {
+		header := http.Header{}
+		if retryAfter != "" {
+			header.Set("Retry-After", retryAfter)
+		}
+		return &resty.Response{RawResponse: &http.Response{StatusCode: status, Header: header}}
+	}
+	tests := []struct {
+		name      string
+		policy    *retryPolicy
+		attempt   int
+		resp      *resty.Response
+		err       error
+		wantRetry bool
+		wantDelay time.Duration // 为0时只校验是否重试
+	}{
+		{"未配置重试", nil, 1, response(503, ""), nil, false, 0},
+		{"状态码需要重试", policy, 1, response(503, ""), nil, true, 0},
+		{"状态码不需要重试", policy, 1, response(500, ""), nil, false, 0},
+		{"达到最大请求次数", policy, 3, response(503, ""), nil, false, 0},
+		{"网络错误", policy, 1, nil, errors.New("connection refused"), true, 0},
+		{"使用Retry-After", policy, 1, response(503, "1"), nil, true, time.Second},
+		{"Retry-After超过最大退避时间", policy, 1, response(503, "2"), nil, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := tt.policy.next(tt.attempt, tt.resp, tt.err)
+			if retry != tt.wantRetry {
+				t.Fatalf("next() retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if tt.wantDelay > 0 && delay != tt.wantDelay {
+				t.Errorf("next() delay = %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+
+	noNetworkRetry := *policy
+	noNetworkRetry.retryOnNetworkError = false
+	if _, retry := noNetworkRetry.next(1, nil, errors.New("timeout")); retry {
+		t.Errorf("未开启网络错误重试时不应重试")
+	}
+}
+
+func TestRetryWithinDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if !retryWithinDeadline(ctx, time.Second) {
+		t.Errorf("剩余时间足够时应继续重试")
+	}
+	if retryWithinDeadline(ctx, 2*time.Minute) {
+		t.Errorf("等待时间超过截止时间时不应重试")
+	}
+	if !retryWithinDeadline(context.Background(), time.Hour) {
+		t.Errorf("没有截止时间时应继续重试")
+	}
+	cancel()
+	if retryWithinDeadline(ctx, 0) {
+		t.Errorf("ctx 已取消时不应重试")
+	}
+}
+
+func TestWaitRetry(t *testing.T) {
+	if !waitRetry(context.Background(), time.Millisecond) {
+		t.Errorf("等待结束后应返回 true")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	if waitRetry(ctx, time.Minute) {
+		t.Errorf("ctx 取消后应返回 false")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ctx 取消后应立即返回，实际等待 %v", elapsed)
+	}
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"github.com/bucketheadv/infra-go/basic"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -14,37 +16,88 @@ import (
 	"github.com/bucketheadv/infra-go/logx"
 	"github.com/bucketheadv/infra-market/internal/dto"
 	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
 	"github.com/bucketheadv/infra-market/internal/repository"
 	"github.com/bucketheadv/infra-market/internal/util"
 	"github.com/go-resty/resty/v2"
+	"gorm.io/gorm"
 )
 
+// SystemExecutorID 系统执行人ID，定时计划等非用户触发的执行使用
+const (
+	SystemExecutorID   uint64 = 0
+	SystemExecutorName        = "系统调度"
+)
+
+// pathParamPattern 匹配URL中的 {name} 路径占位符
+var pathParamPattern = regexp.MustCompile(`\{([A-Za-z0-9_.\-]+)\}`)
+
 type ApiInterfaceService struct {
+	db                              *gorm.DB
 	apiInterfaceRepo                *repository.ApiInterfaceRepository
+	apiInterfaceVersionRepo         *repository.ApiInterfaceVersionRepository
 	apiInterfaceExecutionRecordRepo *repository.ApiInterfaceExecutionRecordRepository
+	apiEnvironmentRepo              *repository.ApiEnvironmentRepository
 	userRepo                        *repository.UserRepository
+	authProfileService              *ApiAuthProfileService
+	redactor                        *SecretRedactor
+	responseBodyStore               *ResponseBodyStore
+	transportService                *ApiTransportService
+	groupService                    *ApiInterfaceGroupService
+	aclService                      *ApiInterfaceAclService
 }
 
 func NewApiInterfaceService(
+	db *gorm.DB,
 	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	apiInterfaceVersionRepo *repository.ApiInterfaceVersionRepository,
 	apiInterfaceExecutionRecordRepo *repository.ApiInterfaceExecutionRecordRepository,
+	apiEnvironmentRepo *repository.ApiEnvironmentRepository,
 	userRepo *repository.UserRepository,
+	authProfileService *ApiAuthProfileService,
+	redactor *SecretRedactor,
+	responseBodyStore *ResponseBodyStore,
+	transportService *ApiTransportService,
+	groupService *ApiInterfaceGroupService,
+	aclService *ApiInterfaceAclService,
 ) *ApiInterfaceService {
 	return &ApiInterfaceService{
+		db:                              db,
 		apiInterfaceRepo:                apiInterfaceRepo,
+		apiInterfaceVersionRepo:         apiInterfaceVersionRepo,
 		apiInterfaceExecutionRecordRepo: apiInterfaceExecutionRecordRepo,
+		apiEnvironmentRepo:              apiEnvironmentRepo,
 		userRepo:                        userRepo,
+		authProfileService:              authProfileService,
+		redactor:                        redactor,
+		responseBodyStore:               responseBodyStore,
+		transportService:                transportService,
+		groupService:                    groupService,
+		aclService:                      aclService,
 	}
 }
 
-// FindPage 分页查询接口
-func (s *ApiInterfaceService) FindPage(query dto.ApiInterfaceQueryDto) dto.ApiData[dto.PageResult[dto.ApiInterfaceDto]] {
+// FindPage 分页查询接口，按分组查询时包含子分组中的接口，只返回 uid 可见的接口
+func (s *ApiInterfaceService) FindPage(query dto.ApiInterfaceQueryDto, uid uint64) dto.ApiData[dto.PageResult[dto.ApiInterfaceDto]] {
+	if query.GroupID != nil && *query.GroupID != 0 {
+		groupIDs, err := s.groupService.ExpandGroupIDs(*query.GroupID)
+		if err != nil {
+			return dto.Error[dto.PageResult[dto.ApiInterfaceDto]]("分组不存在", http.StatusNotFound)
+		}
+		query.GroupIDs = groupIDs
+	}
+
+	s.aclService.ApplyVisibility(&query, uid)
+
 	interfaces, total, err := s.apiInterfaceRepo.Page(query)
-	return PageResultBuilder(interfaces, total, err, s.convertToDto, basic.Ptr(query))
+	result := PageResultBuilder(interfaces, total, err, s.convertToDto, basic.Ptr(query))
+	s.fillTags(result.Data.Records)
+	result.Data.Records = s.aclService.FillAccess(result.Data.Records, uid)
+	return result
 }
 
-// FindMostUsedInterfaces 获取最近最热门的接口
-func (s *ApiInterfaceService) FindMostUsedInterfaces(days, limit int) dto.ApiData[[]dto.ApiInterfaceDto] {
+// FindMostUsedInterfaces 获取最近最热门的接口，只返回 uid 可见的接口
+func (s *ApiInterfaceService) FindMostUsedInterfaces(days, limit int, uid uint64) dto.ApiData[[]dto.ApiInterfaceDto] {
 	// 查询最近使用最多的接口ID
 	interfaceIDs, err := s.apiInterfaceExecutionRecordRepo.FindMostUsedInterfaceIDs(days, limit)
 	if err != nil {
@@ -73,36 +126,52 @@ func (s *ApiInterfaceService) FindMostUsedInterfaces(days, limit int) dto.ApiDat
 			result = append(result, s.convertToDto(&apiInterface))
 		}
 	}
+	s.fillTags(result)
+	result = s.aclService.FillAccess(result, uid)
 
 	return dto.Success(result)
 }
 
-// FindByID 根据ID查询
-func (s *ApiInterfaceService) FindByID(id uint64) dto.ApiData[dto.ApiInterfaceDto] {
+// FindByID 根据ID查询，需要查看权限
+func (s *ApiInterfaceService) FindByID(id uint64, uid uint64) dto.ApiData[dto.ApiInterfaceDto] {
 	apiInterface, err := s.apiInterfaceRepo.FindByID(id)
 	if err != nil {
 		return dto.Error[dto.ApiInterfaceDto]("接口不存在", http.StatusNotFound)
 	}
+	if err := s.aclService.CheckPermission(id, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
 
-	interfaceDto := s.convertToDto(apiInterface)
+	interfaceDto := s.convertToDetailDto(apiInterface, uid)
 	return dto.Success(interfaceDto)
 }
 
-// Save 保存接口
-func (s *ApiInterfaceService) Save(form dto.ApiInterfaceFormDto) dto.ApiData[dto.ApiInterfaceDto] {
-	// 验证POST类型
-	if err := s.validatePostType(&form); err != nil {
+// Save 保存接口，uid 为操作人
+func (s *ApiInterfaceService) Save(form dto.ApiInterfaceFormDto, uid uint64) dto.ApiData[dto.ApiInterfaceDto] {
+	if err := s.validateForm(&form, nil, uid); err != nil {
 		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
 	}
+	if err := s.transportService.CheckPermission(form.Transport, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
+	if status, err := s.groupService.CheckPermission(form.GroupID, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), status)
+	}
 
 	apiInterface := s.convertToEntity(&form)
+	transportConfig, transportSecret, err := s.transportService.applyForm(form.Transport, nil, nil)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
+	}
+	apiInterface.TransportConfig = transportConfig
+	apiInterface.TransportSecret = transportSecret
 	now := time.Now().UnixMilli()
 	apiInterface.CreateTime = now
 	apiInterface.UpdateTime = now
 	status := 1
 	apiInterface.Status = basic.Ptr(status)
 
-	if err := s.apiInterfaceRepo.Create(apiInterface); err != nil {
+	if err := s.saveWithVersion(apiInterface, nil, enums.InterfaceVersionActionCreate, uid, nil, form.Tags); err != nil {
 		name := ""
 		if form.Name != nil {
 			name = *form.Name
@@ -111,52 +180,87 @@ func (s *ApiInterfaceService) Save(form dto.ApiInterfaceFormDto) dto.ApiData[dto
 		return dto.Error[dto.ApiInterfaceDto]("创建接口失败", http.StatusInternalServerError)
 	}
 
-	interfaceDto := s.convertToDto(apiInterface)
+	interfaceDto := s.convertToDetailDto(apiInterface, uid)
 	return dto.Success(interfaceDto)
 }
 
-// Update 更新接口
-func (s *ApiInterfaceService) Update(id uint64, form dto.ApiInterfaceFormDto) dto.ApiData[dto.ApiInterfaceDto] {
+// Update 更新接口，需要编辑权限，uid 为操作人
+func (s *ApiInterfaceService) Update(id uint64, form dto.ApiInterfaceFormDto, uid uint64) dto.ApiData[dto.ApiInterfaceDto] {
 	existing, err := s.apiInterfaceRepo.FindByID(id)
 	if err != nil {
 		return dto.Error[dto.ApiInterfaceDto]("接口不存在", http.StatusNotFound)
 	}
+	if err := s.aclService.CheckPermission(id, uid, enums.InterfacePermissionEdit); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
 
-	// 验证POST类型
-	if err := s.validatePostType(&form); err != nil {
+	if err := s.validateForm(&form, existing.AuthProfileID, uid); err != nil {
 		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
 	}
+	if err := s.transportService.CheckPermission(form.Transport, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
+	// 未传入分组时保持原分组，修改分组需要有目标分组的管理权限
+	if form.GroupID == nil {
+		form.GroupID = existing.GroupID
+	} else if !equalUint64Ptr(form.GroupID, existing.GroupID) {
+		if status, err := s.groupService.CheckPermission(form.GroupID, uid); err != nil {
+			return dto.Error[dto.ApiInterfaceDto](err.Error(), status)
+		}
+	}
 
 	apiInterface := s.convertToEntity(&form)
+	transportConfig, transportSecret, err := s.transportService.applyForm(form.Transport, existing.TransportConfig, existing.TransportSecret)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
+	}
+	apiInterface.TransportConfig = transportConfig
+	apiInterface.TransportSecret = transportSecret
 	apiInterface.ID = existing.ID
 	apiInterface.CreateTime = existing.CreateTime
 	apiInterface.UpdateTime = time.Now().UnixMilli()
 	apiInterface.Status = existing.Status
 
-	if err := s.apiInterfaceRepo.Update(apiInterface); err != nil {
+	if err := s.saveWithVersion(apiInterface, existing, enums.InterfaceVersionActionUpdate, uid, nil, form.Tags); err != nil {
 		logx.Errorf(context.Background(), logx.NameApp, "更新接口失败，接口ID: %d, 错误: %v\n", id, err)
 		return dto.Error[dto.ApiInterfaceDto]("更新接口失败", http.StatusInternalServerError)
 	}
 
-	interfaceDto := s.convertToDto(apiInterface)
+	interfaceDto := s.convertToDetailDto(apiInterface, uid)
 	return dto.Success(interfaceDto)
 }
 
-// Delete 删除接口
-func (s *ApiInterfaceService) Delete(id uint64) dto.ApiData[any] {
-	if err := s.apiInterfaceRepo.Delete(id); err != nil {
+// Delete 删除接口及其标签关联与授权，需要编辑权限
+func (s *ApiInterfaceService) Delete(id uint64, uid uint64) dto.ApiData[any] {
+	if err := s.aclService.CheckPermission(id, uid, enums.InterfacePermissionEdit); err != nil {
+		return dto.Error[any](err.Error(), http.StatusForbidden)
+	}
+
+	err := WithTransaction(s.db, func(tx *gorm.DB) error {
+		if err := repository.NewApiInterfaceTagRelationRepository(tx).DeleteByInterfaceIDs([]uint64{id}); err != nil {
+			return err
+		}
+		if err := repository.NewApiInterfaceAclRepository(tx).DeleteByInterfaceIDs([]uint64{id}); err != nil {
+			return err
+		}
+		return repository.NewApiInterfaceRepository(tx).Delete(id)
+	})
+	if err != nil {
 		logx.Errorf(context.Background(), logx.NameApp, "删除接口失败，接口ID: %d, 错误: %v\n", id, err)
 		return dto.Error[any]("删除接口失败", http.StatusInternalServerError)
 	}
 	return dto.Success[any](nil)
 }
 
-// UpdateStatus 更新接口状态
-func (s *ApiInterfaceService) UpdateStatus(id uint64, status int) dto.ApiData[dto.ApiInterfaceDto] {
+// UpdateStatus 更新接口状态，需要编辑权限
+func (s *ApiInterfaceService) UpdateStatus(id uint64, status int, uid uint64) dto.ApiData[dto.ApiInterfaceDto] {
 	apiInterface, err := s.apiInterfaceRepo.FindByID(id)
 	if err != nil {
 		return dto.Error[dto.ApiInterfaceDto]("接口不存在", http.StatusNotFound)
 	}
+	if err := s.aclService.CheckPermission(id, uid, enums.InterfacePermissionEdit); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
 
 	apiInterface.Status = basic.Ptr(status)
 	apiInterface.UpdateTime = time.Now().UnixMilli()
@@ -166,16 +270,29 @@ func (s *ApiInterfaceService) UpdateStatus(id uint64, status int) dto.ApiData[dt
 		return dto.Error[dto.ApiInterfaceDto]("更新状态失败", http.StatusInternalServerError)
 	}
 
-	interfaceDto := s.convertToDto(apiInterface)
+	interfaceDto := s.convertToDetailDto(apiInterface, uid)
 	return dto.Success(interfaceDto)
 }
 
-// Copy 复制接口
-func (s *ApiInterfaceService) Copy(id uint64) dto.ApiData[dto.ApiInterfaceDto] {
+// Copy 复制接口，需要编辑权限，uid 为操作人并成为副本的负责人
+func (s *ApiInterfaceService) Copy(id uint64, uid uint64) dto.ApiData[dto.ApiInterfaceDto] {
 	existing, err := s.apiInterfaceRepo.FindByID(id)
 	if err != nil {
 		return dto.Error[dto.ApiInterfaceDto]("接口不存在", http.StatusNotFound)
 	}
+	// 副本沿用原接口的传输层配置、密钥与认证配置，需要原接口的编辑权限，并与新建接口一样校验各项配置
+	if err := s.aclService.CheckPermission(id, uid, enums.InterfacePermissionEdit); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
+	if err := s.transportService.CheckConfigPermission(existing.TransportConfig, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
+	if err := s.authProfileService.ValidateReference(existing.AuthProfileID, nil, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
+	}
+	if status, err := s.groupService.CheckPermission(existing.GroupID, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), status)
+	}
 
 	newInterface := *existing
 	newInterface.ID = 0
@@ -188,11 +305,17 @@ func (s *ApiInterfaceService) Copy(id uint64) dto.ApiData[dto.ApiInterfaceDto] {
 	status := 1
 	newInterface.Status = basic.Ptr(status)
 
-	if err := s.apiInterfaceRepo.Create(&newInterface); err != nil {
+	// 副本与原接口位于同一分组并带有相同的标签
+	tags := s.groupService.TagNames([]uint64{id})[id]
+	if tags == nil {
+		tags = []string{}
+	}
+	if err := s.saveWithVersion(&newInterface, nil, enums.InterfaceVersionActionCopy, uid, nil, tags); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "复制接口失败，接口ID: %d, 错误: %v\n", id, err)
 		return dto.Error[dto.ApiInterfaceDto]("复制接口失败", http.StatusInternalServerError)
 	}
 
-	interfaceDto := s.convertToDto(&newInterface)
+	interfaceDto := s.convertToDetailDto(&newInterface, uid)
 	return dto.Success(interfaceDto)
 }
 
@@ -201,10 +324,7 @@ func (s *ApiInterfaceService) Execute(req dto.ApiExecuteRequestDto, executorID u
 	startTime := time.Now()
 
 	// 获取执行人姓名
-	executorName := "未知用户"
-	if user, err := s.userRepo.FindByUID(executorID); err == nil {
-		executorName = user.Username
-	}
+	executorName := s.executorName(executorID)
 
 	// 获取接口信息
 	if req.InterfaceID == nil {
@@ -217,6 +337,13 @@ func (s *ApiInterfaceService) Execute(req dto.ApiExecuteRequestDto, executorID u
 		return dto.Error[dto.ApiExecuteResponseDto]("接口不存在", http.StatusNotFound)
 	}
 
+	// 检查执行权限，定时计划等系统执行不受限制
+	if executorID != SystemExecutorID {
+		if err := s.aclService.CheckPermission(apiInterface.ID, executorID, enums.InterfacePermissionExecute); err != nil {
+			return dto.Error[dto.ApiExecuteResponseDto](err.Error(), http.StatusForbidden)
+		}
+	}
+
 	// 检查接口状态
 	if apiInterface.Status == nil || *apiInterface.Status != 1 {
 		responseTime := time.Since(startTime).Milliseconds()
@@ -228,11 +355,45 @@ func (s *ApiInterfaceService) Execute(req dto.ApiExecuteRequestDto, executorID u
 		})
 	}
 
+	// 解析执行环境：请求指定的环境优先，其次为接口默认环境
+	environment, err := s.resolveEnvironment(apiInterface, &req)
+	if err != nil {
+		responseTime := time.Since(startTime).Milliseconds()
+		return dto.Success(dto.ApiExecuteResponseDto{
+			Status:       http.StatusBadRequest,
+			Success:      false,
+			Error:        stringPtr(err.Error()),
+			ResponseTime: responseTime,
+		})
+	}
+
 	// 处理参数值：JSON_OBJECT 类型需要解析为对象
 	processedReq := s.processParams(apiInterface, &req)
+	if isRawBodyMode(apiInterface) && processedReq.RawBody == nil {
+		// 未在执行时编辑请求体时使用接口保存的请求体模板
+		processedReq.RawBody = apiInterface.RawBody
+	}
+	if environment != nil {
+		processedReq.EnvironmentID = basic.Ptr(environment.ID)
+	}
+
+	// 未传入的参数使用默认值，不可修改的参数固定使用默认值，需在替换变量前处理以便默认值中可以使用变量
+	params := parseInterfaceParams(apiInterface)
+	paramSources, paramErrors := applyParamDefaults(params, isRawBodyMode(apiInterface), processedReq)
+	if len(paramErrors) > 0 {
+		responseTime := time.Since(startTime).Milliseconds()
+		return dto.Success(dto.ApiExecuteResponseDto{
+			Status:       http.StatusBadRequest,
+			Success:      false,
+			Error:        stringPtr(paramErrorMessage(paramErrors)),
+			ParamErrors:  paramErrors,
+			ResponseTime: responseTime,
+		})
+	}
 
-	// 验证必填参数
-	if err := s.validateRequiredParams(apiInterface, processedReq); err != nil {
+	// 替换URL、请求头和请求体中的变量占位符
+	renderedInterface, err := s.renderTemplates(apiInterface, environment, processedReq)
+	if err != nil {
 		responseTime := time.Since(startTime).Milliseconds()
 		return dto.Success(dto.ApiExecuteResponseDto{
 			Status:       http.StatusBadRequest,
@@ -242,25 +403,39 @@ func (s *ApiInterfaceService) Execute(req dto.ApiExecuteRequestDto, executorID u
 		})
 	}
 
+	// 按参数定义转换并校验参数值：必填、数据类型与可选项
+	if paramErrors := coerceParams(params, isRawBodyMode(apiInterface), processedReq); len(paramErrors) > 0 {
+		responseTime := time.Since(startTime).Milliseconds()
+		return dto.Success(dto.ApiExecuteResponseDto{
+			Status:       http.StatusBadRequest,
+			Success:      false,
+			Error:        stringPtr(paramErrorMessage(paramErrors)),
+			ParamErrors:  paramErrors,
+			ResponseTime: responseTime,
+		})
+	}
+
 	// 执行HTTP请求
-	response, err := s.executeHTTPRequest(apiInterface, processedReq)
+	response, err := s.executeHTTPRequest(renderedInterface, environment, processedReq)
 	responseTime := time.Since(startTime).Milliseconds()
 
 	if err != nil {
 		// 记录失败的执行记录
-		s.saveExecutionRecord(*processedReq.InterfaceID, &executorID, executorName, processedReq, &dto.ApiExecuteResponseDto{
+		failedResponse := &dto.ApiExecuteResponseDto{
 			Status:       http.StatusInternalServerError,
 			Success:      false,
 			Error:        stringPtr(err.Error()),
+			ParamSources: paramSources,
 			ResponseTime: responseTime,
-		}, clientIP, userAgent, req.Remark)
+		}
+		if response != nil {
+			// 重试的请求均失败时保留每次请求的结果
+			failedResponse.Attempts = response.Attempts
+		}
+		s.saveExecutionRecord(apiInterface, &executorID, executorName, processedReq, failedResponse, clientIP, userAgent, req.Remark)
+		s.redactor.RedactResponse(failedResponse)
 
-		return dto.Success(dto.ApiExecuteResponseDto{
-			Status:       http.StatusInternalServerError,
-			Success:      false,
-			Error:        stringPtr(err.Error()),
-			ResponseTime: responseTime,
-		})
+		return dto.Success(*failedResponse)
 	}
 
 	// 提取值（如果配置了valuePath）
@@ -268,35 +443,147 @@ func (s *ApiInterfaceService) Execute(req dto.ApiExecuteRequestDto, executorID u
 		extractedValue := s.extractValueByPath(*response.Body, *apiInterface.ValuePath)
 		response.ExtractedValue = extractedValue
 	}
+	response.ParamSources = paramSources
+
+	// 执行响应断言
+	assertions := parseInterfaceAssertions(apiInterface)
+	applyAssertionResults(response, assertions, evaluateAssertions(assertions, response))
 
 	// 记录执行记录
-	s.saveExecutionRecord(*req.InterfaceID, &executorID, executorName, processedReq, response, clientIP, userAgent, req.Remark)
+	s.saveExecutionRecord(apiInterface, &executorID, executorName, processedReq, response, clientIP, userAgent, req.Remark)
+	// 重定向链等内容中的地址可能带有认证配置的密钥，返回前脱敏
+	s.redactor.RedactResponse(response)
 
 	return dto.Success(*response)
 }
 
+// BuildSnippet 根据接口与参数取值生成代码片段，参数处理与执行接口时一致
+func (s *ApiInterfaceService) BuildSnippet(req dto.ApiExecuteRequestDto, uid uint64) dto.ApiData[dto.ApiInterfaceSnippetDto] {
+	apiInterface, err := s.apiInterfaceRepo.FindByID(*req.InterfaceID)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto]("接口不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(apiInterface.ID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto](err.Error(), http.StatusForbidden)
+	}
+
+	environment, err := s.resolveEnvironment(apiInterface, &req)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto](err.Error(), http.StatusBadRequest)
+	}
+
+	processedReq := s.processParams(apiInterface, &req)
+	if isRawBodyMode(apiInterface) && processedReq.RawBody == nil {
+		processedReq.RawBody = apiInterface.RawBody
+	}
+	params := parseInterfaceParams(apiInterface)
+	if _, paramErrors := applyParamDefaults(params, isRawBodyMode(apiInterface), processedReq); len(paramErrors) > 0 {
+		return dto.Error[dto.ApiInterfaceSnippetDto](paramErrorMessage(paramErrors), http.StatusBadRequest)
+	}
+	renderedInterface, err := s.renderTemplates(apiInterface, environment, processedReq)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto](err.Error(), http.StatusBadRequest)
+	}
+	if paramErrors := coerceParams(params, isRawBodyMode(apiInterface), processedReq); len(paramErrors) > 0 {
+		return dto.Error[dto.ApiInterfaceSnippetDto](paramErrorMessage(paramErrors), http.StatusBadRequest)
+	}
+
+	return s.renderSnippet(renderedInterface, environment, processedReq)
+}
+
+// BuildRecordSnippet 根据执行记录中保存的请求生成代码片段，需要接口的查看权限
+func (s *ApiInterfaceService) BuildRecordSnippet(record *entity.ApiInterfaceExecutionRecord, uid uint64) dto.ApiData[dto.ApiInterfaceSnippetDto] {
+	if record.InterfaceID == nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto]("接口不存在", http.StatusNotFound)
+	}
+	apiInterface, err := s.apiInterfaceRepo.FindByID(*record.InterfaceID)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto]("接口不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(apiInterface.ID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto](err.Error(), http.StatusForbidden)
+	}
+
+	var environment *entity.ApiEnvironment
+	if record.EnvironmentID != nil {
+		if environment, err = s.apiEnvironmentRepo.FindByID(*record.EnvironmentID); err != nil {
+			return dto.Error[dto.ApiInterfaceSnippetDto]("执行环境不存在", http.StatusNotFound)
+		}
+	}
+
+	renderedInterface, req := restoreRecordRequest(apiInterface, environment, record)
+	return s.renderSnippet(renderedInterface, environment, req)
+}
+
+// restoreRecordRequest 将执行记录中保存的请求还原为请求结构，返回URL已替换环境变量的接口副本
+// 执行记录中保存的是处理后的参数值，无需再次处理参数
+func restoreRecordRequest(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment, record *entity.ApiInterfaceExecutionRecord) (*entity.ApiInterface, *dto.ApiExecuteRequestDto) {
+	req := dto.ApiExecuteRequestDto{InterfaceID: record.InterfaceID, EnvironmentID: record.EnvironmentID}
+	unmarshalRecordJSON(record.RequestHeaders, &req.Headers)
+	unmarshalRecordJSON(record.RequestParams, &req.URLParams)
+	unmarshalRecordJSON(record.RequestPathParams, &req.PathParams)
+	if isRawBodyMode(apiInterface) {
+		req.RawBody = record.RequestBody
+	} else {
+		unmarshalRecordJSON(record.RequestBody, &req.BodyParams)
+	}
+	var fileMetas []dto.ApiUploadFileMetaDto
+	unmarshalRecordJSON(record.RequestFiles, &fileMetas)
+	for _, meta := range fileMetas {
+		req.Files = append(req.Files, dto.ApiUploadFileDto{ParamName: meta.ParamName, FileName: meta.FileName, ContentType: meta.ContentType})
+	}
+
+	renderedInterface := *apiInterface
+	renderedInterface.URL = util.NewTemplateRenderer(parseEnvironmentVariables(environment)).RenderString(apiInterface.URL)
+	return &renderedInterface, &req
+}
+
+// renderSnippet 构建最终请求并生成各语言代码片段
+func (s *ApiInterfaceService) renderSnippet(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment, req *dto.ApiExecuteRequestDto) dto.ApiData[dto.ApiInterfaceSnippetDto] {
+	finalURL, err := s.resolveRequestURL(apiInterface, environment, req)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto](err.Error(), http.StatusBadRequest)
+	}
+	snippet, err := newSnippetRequest(apiInterface, finalURL, req)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceSnippetDto](err.Error(), http.StatusBadRequest)
+	}
+	return dto.Success(snippet.render())
+}
+
+// unmarshalRecordJSON 解析执行记录中保存的JSON字段，内容为空或格式错误时忽略
+func unmarshalRecordJSON(value *string, target any) {
+	if value == nil || *value == "" {
+		return
+	}
+	if err := json.Unmarshal([]byte(*value), target); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析执行记录内容失败: %v\n", err)
+	}
+}
+
 // executeHTTPRequest 执行HTTP请求
-func (s *ApiInterfaceService) executeHTTPRequest(apiInterface *entity.ApiInterface, req *dto.ApiExecuteRequestDto) (*dto.ApiExecuteResponseDto, error) {
+func (s *ApiInterfaceService) executeHTTPRequest(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment, req *dto.ApiExecuteRequestDto) (*dto.ApiExecuteResponseDto, error) {
 	// 构建URL
-	finalURL := apiInterface.URL
-	if req.URLParams != nil && len(req.URLParams) > 0 {
-		params := url.Values{}
-		for k, v := range req.URLParams {
-			if v != nil {
-				params.Add(k, fmt.Sprintf("%v", v))
-			}
-		}
-		if len(params) > 0 {
-			separator := "?"
-			if strings.Contains(finalURL, "?") {
-				separator = "&"
-			}
-			finalURL += separator + params.Encode()
-		}
+	finalURL, err := s.resolveRequestURL(apiInterface, environment, req)
+	if err != nil {
+		return nil, err
 	}
 
-	// 创建HTTP客户端
+	// 创建HTTP客户端：应用传输层配置，接口配置优先，其次为环境配置
+	transportSettings, err := s.transportService.Resolve(apiInterface, environment)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newHTTPTransport(apiInterface, transportSettings)
+	if err != nil {
+		return nil, err
+	}
 	client := resty.New()
+	client.SetTransport(transport)
+
+	// 设置重定向策略并记录重定向链
+	redirects := newRedirectRecorder(apiInterface)
+	client.SetRedirectPolicy(redirects.policy())
 
 	// 设置超时时间
 	timeoutSeconds := int64(60)
@@ -307,6 +594,112 @@ func (s *ApiInterfaceService) executeHTTPRequest(apiInterface *entity.ApiInterfa
 	}
 	client.SetTimeout(time.Duration(timeoutSeconds) * time.Second)
 
+	method := strings.ToUpper(apiInterface.Method)
+	if enums.HttpMethodFromCode(method) == nil {
+		return nil, fmt.Errorf("不支持的HTTP方法: %s", method)
+	}
+
+	// 认证配置：接口配置优先，其次为环境配置
+	authProfile, err := s.authProfileService.Resolve(apiInterface, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	// 执行请求：按重试策略重试，每次重新构建请求以便重新签名；重试总时长不超过 maxRetryDuration
+	retry := parseRetryPolicy(apiInterface)
+	retryCtx, cancel := context.WithTimeout(context.Background(), maxRetryDuration)
+	defer cancel()
+	var attempts []dto.ApiExecuteAttemptDto
+	var resp *resty.Response
+	var delay time.Duration
+	startTime := time.Now()
+	attemptStart := startTime
+	for attempt := 1; ; attempt++ {
+		redirects.reset()
+		request, err := s.buildHTTPRequest(client, apiInterface, req, authProfile, method, finalURL)
+		if err != nil {
+			return nil, err
+		}
+		// 自行读取响应体以限制保存大小
+		request.SetDoNotParseResponse(true)
+		if retry != nil {
+			// 配置重试时每次请求都受重试总时长限制，超时后正在进行的请求随之取消
+			request.SetContext(retryCtx)
+		}
+		attemptStart = time.Now()
+		resp, err = request.Execute(method, finalURL)
+		if err != nil {
+			err = describeTransportError(err)
+		}
+		if retry != nil {
+			attempts = append(attempts, newExecuteAttempt(attempt, delay, time.Since(attemptStart), resp, err))
+		}
+
+		var retryable bool
+		delay, retryable = retry.next(attempt, resp, err)
+		// 剩余时间不足以等待下一次重试时不再重试，保留本次请求的结果
+		if retryable && !retryWithinDeadline(retryCtx, delay) {
+			retryable = false
+		}
+		if !retryable {
+			if err != nil {
+				return &dto.ApiExecuteResponseDto{Attempts: attempts}, err
+			}
+			break
+		}
+		if err == nil {
+			discardResponseBody(resp)
+		}
+		if !waitRetry(retryCtx, delay) {
+			return &dto.ApiExecuteResponseDto{Attempts: attempts}, fmt.Errorf("等待重试已取消: %w", retryCtx.Err())
+		}
+	}
+	defer resp.RawBody().Close()
+	bodyText, bodyInfo, err := s.responseBodyStore.Read(resp.RawBody(), resp.Header().Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	responseTime := time.Since(startTime).Milliseconds()
+	if len(attempts) > 0 {
+		// 最后一次请求的耗时包含读取响应体的时间
+		attempts[len(attempts)-1].ResponseTime = time.Since(attemptStart).Milliseconds()
+	}
+
+	// 构建响应
+	responseHeaders := make(map[string]string)
+	for k, v := range resp.Header() {
+		if len(v) > 0 {
+			responseHeaders[k] = v[0]
+		}
+	}
+
+	// 不跟随重定向时，3xx响应视为成功
+	success := resp.IsSuccess() || (!redirects.follow && resp.StatusCode() >= 300 && resp.StatusCode() < 400)
+	response := &dto.ApiExecuteResponseDto{
+		Status:        resp.StatusCode(),
+		Headers:       responseHeaders,
+		Body:          bodyText,
+		BodyInfo:      bodyInfo,
+		Protocol:      basic.Ptr(resp.Proto()),
+		RedirectChain: redirects.hops,
+		Attempts:      attempts,
+		ResponseTime:  responseTime,
+		Success:       success,
+	}
+
+	if err := redirects.error(); err != nil {
+		response.Success = false
+		response.Error = basic.Ptr(err.Error())
+	} else if !success {
+		// 响应体已保存在 Body 中，错误信息只记录状态码，避免响应体绕过脱敏规则
+		response.Error = basic.Ptr(fmt.Sprintf("HTTP %d", resp.StatusCode()))
+	}
+
+	return response, nil
+}
+
+// buildHTTPRequest 构建请求：设置请求头、请求体并应用认证配置
+func (s *ApiInterfaceService) buildHTTPRequest(client *resty.Client, apiInterface *entity.ApiInterface, req *dto.ApiExecuteRequestDto, authProfile *entity.ApiAuthProfile, method, finalURL string) (*resty.Request, error) {
 	// 设置请求头
 	headers := make(map[string]string)
 	if req.Headers != nil {
@@ -320,82 +713,226 @@ func (s *ApiInterfaceService) executeHTTPRequest(apiInterface *entity.ApiInterfa
 
 	// 设置请求体
 	var body string
-	if apiInterface.Method != "GET" && req.BodyParams != nil && len(req.BodyParams) > 0 {
-		postType := "application/json"
-		if apiInterface.PostType != nil {
-			postType = *apiInterface.PostType
-		}
-
-		if postType == "application/x-www-form-urlencoded" {
-			// 表单格式
-			formData := url.Values{}
+	postType := requestPostType(apiInterface)
+	if hasRequestBody(apiInterface, postType, req) {
+		if !isRawBodyMode(apiInterface) && postType == "multipart/form-data" {
+			// 多部分表单：Content-Type（含boundary）由resty生成，数组参数展开为重复的字段
 			for k, v := range req.BodyParams {
 				if v != nil {
-					formData.Add(k, fmt.Sprintf("%v", v))
+					for _, item := range paramValues(v) {
+						request.SetMultipartField(k, "", "", strings.NewReader(item))
+					}
+				}
+			}
+			for _, file := range req.Files {
+				contentType := file.ContentType
+				if contentType == "" {
+					contentType = "application/octet-stream"
 				}
+				request.SetMultipartField(file.ParamName, file.FileName, contentType, bytes.NewReader(file.Content))
 			}
-			body = formData.Encode()
-			request.SetHeader("Content-Type", postType)
-			request.SetBody(body)
 		} else {
-			// JSON格式
-			jsonData, err := json.Marshal(req.BodyParams)
+			contentType, encoded, err := encodeRequestBody(apiInterface, postType, req)
 			if err != nil {
-				return nil, fmt.Errorf("序列化请求体失败: %w", err)
+				return nil, err
 			}
-			body = string(jsonData)
-			request.SetHeader("Content-Type", "application/json")
-			request.SetBody(jsonData)
+			body = encoded
+			request.SetHeader("Content-Type", contentType)
+			request.SetBody([]byte(body))
 		}
 	}
 
-	// 执行请求
-	var resp *resty.Response
-	var err error
+	// 应用认证配置
+	if err := s.authProfileService.Apply(request, authProfile, method, finalURL, body); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
 
-	method := strings.ToUpper(apiInterface.Method)
-	switch method {
-	case "GET":
-		resp, err = request.Get(finalURL)
-	case "POST":
-		resp, err = request.Post(finalURL)
-	case "PUT":
-		resp, err = request.Put(finalURL)
-	case "DELETE":
-		resp, err = request.Delete(finalURL)
-	case "PATCH":
-		resp, err = request.Patch(finalURL)
-	default:
-		return nil, fmt.Errorf("不支持的HTTP方法: %s", method)
+// resolveRequestURL 构建最终请求地址：拼接环境地址、替换Path参数并追加URL参数
+func (s *ApiInterfaceService) resolveRequestURL(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment, req *dto.ApiExecuteRequestDto) (string, error) {
+	finalURL, err := s.buildRequestURL(apiInterface.URL, environment)
+	if err != nil {
+		return "", err
+	}
+	finalURL, err = s.applyPathParams(finalURL, apiInterface, req)
+	if err != nil {
+		return "", err
 	}
+	if req.URLParams != nil && len(req.URLParams) > 0 {
+		params := url.Values{}
+		for k, v := range req.URLParams {
+			if v != nil {
+				// 数组参数展开为重复的查询键
+				for _, item := range paramValues(v) {
+					params.Add(k, item)
+				}
+			}
+		}
+		if len(params) > 0 {
+			separator := "?"
+			if strings.Contains(finalURL, "?") {
+				separator = "&"
+			}
+			finalURL += separator + params.Encode()
+		}
+	}
+	return finalURL, nil
+}
+
+// requestPostType 接口的请求体类型，未配置时为JSON
+func requestPostType(apiInterface *entity.ApiInterface) string {
+	if apiInterface.PostType != nil {
+		return *apiInterface.PostType
+	}
+	return "application/json"
+}
 
+// hasRequestBody 判断本次请求是否需要发送请求体
+func hasRequestBody(apiInterface *entity.ApiInterface, postType string, req *dto.ApiExecuteRequestDto) bool {
+	if apiInterface.Method == "GET" || apiInterface.Method == "HEAD" {
+		return false
+	}
+	if isRawBodyMode(apiInterface) {
+		return req.RawBody != nil && *req.RawBody != ""
+	}
+	return len(req.BodyParams) > 0 || (postType == "multipart/form-data" && len(req.Files) > 0)
+}
+
+// encodeRequestBody 编码非multipart请求体，返回实际发送的Content-Type与请求体
+func encodeRequestBody(apiInterface *entity.ApiInterface, postType string, req *dto.ApiExecuteRequestDto) (string, string, error) {
+	if isRawBodyMode(apiInterface) {
+		// RAW模式：请求体模板原样发送
+		return postType, *req.RawBody, nil
+	}
+	if postType == "application/x-www-form-urlencoded" {
+		// 表单格式
+		formData := url.Values{}
+		for k, v := range req.BodyParams {
+			if v != nil {
+				for _, item := range paramValues(v) {
+					formData.Add(k, item)
+				}
+			}
+		}
+		return postType, formData.Encode(), nil
+	}
+	// JSON格式
+	jsonData, err := json.Marshal(req.BodyParams)
 	if err != nil {
-		return nil, err
+		return "", "", fmt.Errorf("序列化请求体失败: %w", err)
 	}
+	return "application/json", string(jsonData), nil
+}
 
-	// 构建响应
-	responseHeaders := make(map[string]string)
-	for k, v := range resp.Header() {
-		if len(v) > 0 {
-			responseHeaders[k] = v[0]
+// resolveEnvironment 解析本次执行使用的环境
+func (s *ApiInterfaceService) resolveEnvironment(apiInterface *entity.ApiInterface, req *dto.ApiExecuteRequestDto) (*entity.ApiEnvironment, error) {
+	environmentID := apiInterface.EnvironmentID
+	if req.EnvironmentID != nil {
+		environmentID = req.EnvironmentID
+	}
+	if environmentID == nil {
+		return nil, nil
+	}
+
+	environment, err := s.apiEnvironmentRepo.FindByID(*environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("执行环境不存在，环境ID: %d", *environmentID)
+	}
+	if environment.Status != 1 {
+		return nil, fmt.Errorf("执行环境 %s 已禁用", environment.Name)
+	}
+	return environment, nil
+}
+
+// renderTemplates 替换 {{name}} 占位符，变量优先级：内置变量 > 请求变量 > 环境变量
+// 返回URL已替换的接口副本，请求中的参数值原地替换；存在未解析的占位符时返回错误
+func (s *ApiInterfaceService) renderTemplates(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment, req *dto.ApiExecuteRequestDto) (*entity.ApiInterface, error) {
+	renderer := util.NewTemplateRenderer(parseEnvironmentVariables(environment), req.Variables)
+
+	rendered := *apiInterface
+	rendered.URL = renderer.RenderString(apiInterface.URL)
+
+	if req.Headers != nil {
+		headers := make(map[string]string, len(req.Headers))
+		for k, v := range req.Headers {
+			headers[k] = renderer.RenderString(v)
 		}
+		req.Headers = headers
+	}
+	if req.URLParams != nil {
+		req.URLParams = renderer.RenderValue(req.URLParams).(map[string]any)
+	}
+	if req.PathParams != nil {
+		req.PathParams = renderer.RenderValue(req.PathParams).(map[string]any)
+	}
+	if req.BodyParams != nil {
+		req.BodyParams = renderer.RenderValue(req.BodyParams).(map[string]any)
+	}
+	if req.RawBody != nil {
+		req.RawBody = basic.Ptr(renderer.RenderString(*req.RawBody))
 	}
 
-	bodyStr := resp.String()
-	response := &dto.ApiExecuteResponseDto{
-		Status:       resp.StatusCode(),
-		Headers:      responseHeaders,
-		Body:         basic.Ptr(bodyStr),
-		ResponseTime: resp.Time().Milliseconds(),
-		Success:      resp.IsSuccess(),
+	if missing := renderer.Missing(); len(missing) > 0 {
+		return nil, fmt.Errorf("存在未解析的变量: %s", strings.Join(missing, ", "))
+	}
+	return &rendered, nil
+}
+
+// buildRequestURL 根据环境基础地址拼接接口路径，绝对地址保持不变
+func (s *ApiInterfaceService) buildRequestURL(path string, environment *entity.ApiEnvironment) (string, error) {
+	if isAbsoluteURL(path) {
+		return path, nil
+	}
+	if environment == nil {
+		return "", fmt.Errorf("接口URL为相对路径，请选择执行环境")
 	}
+	if path == "" {
+		return environment.BaseURL, nil
+	}
+	return strings.TrimRight(environment.BaseURL, "/") + "/" + strings.TrimLeft(path, "/"), nil
+}
 
-	if !resp.IsSuccess() {
-		errMsg := bodyStr
-		response.Error = basic.Ptr(errMsg)
+// applyPathParams 将Path参数值转义后替换URL中的 {name} 路径占位符
+func (s *ApiInterfaceService) applyPathParams(rawURL string, apiInterface *entity.ApiInterface, req *dto.ApiExecuteRequestDto) (string, error) {
+	if !pathParamPattern.MatchString(rawURL) {
+		return rawURL, nil
 	}
 
-	return response, nil
+	definedParams := make(map[string]bool)
+	for _, param := range parseInterfaceParams(apiInterface) {
+		if param.Name != nil && param.ParamType != nil && *param.ParamType == enums.ParamTypePath.Code() {
+			definedParams[*param.Name] = true
+		}
+	}
+
+	var resolveErr error
+	result := pathParamPattern.ReplaceAllStringFunc(rawURL, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+		name := match[1 : len(match)-1]
+		if !definedParams[name] {
+			resolveErr = fmt.Errorf("URL路径占位符 {%s} 没有对应的Path参数定义", name)
+			return match
+		}
+		value, ok := req.PathParams[name]
+		if !ok || isEmptyParamValue(value) {
+			resolveErr = fmt.Errorf("Path参数 %s 缺少取值", name)
+			return match
+		}
+		return url.PathEscape(strings.Join(paramValues(value), ","))
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return result, nil
+}
+
+// isAbsoluteURL 判断是否为带协议的完整地址
+func isAbsoluteURL(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
 }
 
 // extractValueByPath 根据JSONPath提取值
@@ -414,33 +951,33 @@ func (s *ApiInterfaceService) extractValueByPath(jsonString, path string) *strin
 		return nil
 	}
 
-	// 将结果转换为字符串
-	var resultStr string
-	switch v := result.(type) {
+	return basic.Ptr(formatJSONValue(result))
+}
+
+// formatJSONValue 将JSONPath提取结果转换为字符串
+func formatJSONValue(value any) string {
+	switch v := value.(type) {
 	case string:
-		resultStr = v
+		return v
 	case float64, int, int64:
-		resultStr = fmt.Sprintf("%v", v)
+		return fmt.Sprintf("%v", v)
 	case bool:
-		resultStr = fmt.Sprintf("%v", v)
+		return fmt.Sprintf("%v", v)
 	case []any, map[string]any:
 		jsonBytes, err := json.Marshal(v)
 		if err != nil {
 			logx.Errorf(context.Background(), logx.NameApp, "JSON序列化失败: %v\n", err)
-			resultStr = fmt.Sprintf("%v", v)
-		} else {
-			resultStr = string(jsonBytes)
+			return fmt.Sprintf("%v", v)
 		}
+		return string(jsonBytes)
 	default:
-		resultStr = fmt.Sprintf("%v", v)
+		return fmt.Sprintf("%v", v)
 	}
-
-	return basic.Ptr(resultStr)
 }
 
 // saveExecutionRecord 保存执行记录
 func (s *ApiInterfaceService) saveExecutionRecord(
-	interfaceID uint64,
+	apiInterface *entity.ApiInterface,
 	executorID *uint64,
 	executorName string,
 	request *dto.ApiExecuteRequestDto,
@@ -454,6 +991,28 @@ func (s *ApiInterfaceService) saveExecutionRecord(
 		logx.Errorf(context.Background(), logx.NameApp, "序列化请求参数失败: %v\n", err)
 		requestParamsJSON = []byte("{}")
 	}
+	requestPathParamsJSON, err := json.Marshal(request.PathParams)
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "序列化Path参数失败: %v\n", err)
+		requestPathParamsJSON = []byte("{}")
+	}
+	var requestFilesJSON *string
+	if len(request.Files) > 0 {
+		fileMetas := make([]dto.ApiUploadFileMetaDto, len(request.Files))
+		for i, file := range request.Files {
+			fileMetas[i] = dto.ApiUploadFileMetaDto{
+				ParamName:   file.ParamName,
+				FileName:    file.FileName,
+				ContentType: file.ContentType,
+				Size:        int64(len(file.Content)),
+			}
+		}
+		if jsonBytes, err := json.Marshal(fileMetas); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化上传文件信息失败: %v\n", err)
+		} else {
+			requestFilesJSON = stringPtr(string(jsonBytes))
+		}
+	}
 	requestHeadersJSON, err := json.Marshal(request.Headers)
 	if err != nil {
 		logx.Errorf(context.Background(), logx.NameApp, "序列化请求头失败: %v\n", err)
@@ -464,6 +1023,10 @@ func (s *ApiInterfaceService) saveExecutionRecord(
 		logx.Errorf(context.Background(), logx.NameApp, "序列化请求体失败: %v\n", err)
 		requestBodyJSON = []byte("{}")
 	}
+	if request.RawBody != nil {
+		// RAW模式直接记录发送的原始请求体
+		requestBodyJSON = []byte(*request.RawBody)
+	}
 
 	// 序列化响应头
 	responseHeadersJSON, err := json.Marshal(response.Headers)
@@ -472,27 +1035,114 @@ func (s *ApiInterfaceService) saveExecutionRecord(
 		responseHeadersJSON = []byte("{}")
 	}
 
-	record := &entity.ApiInterfaceExecutionRecord{
-		InterfaceID:     basic.Ptr(interfaceID),
-		ExecutorID:      executorID,
-		ExecutorName:    executorName,
-		RequestParams:   stringPtr(string(requestParamsJSON)),
-		RequestHeaders:  stringPtr(string(requestHeadersJSON)),
-		RequestBody:     stringPtr(string(requestBodyJSON)),
-		ResponseStatus:  basic.Ptr(response.Status),
-		ResponseHeaders: stringPtr(string(responseHeadersJSON)),
-		ResponseBody:    response.Body,
-		ExecutionTime:   basic.Ptr(response.ResponseTime),
-		Success:         basic.Ptr(response.Success),
-		ErrorMessage:    response.Error,
-		Remark:          remark,
-		ClientIP:        basic.Ptr(clientIP),
-		UserAgent:       basic.Ptr(userAgent),
+	// 序列化响应体元信息
+	var responseBodyInfoJSON, responseBlobKey *string
+	if response.BodyInfo != nil {
+		if jsonBytes, err := json.Marshal(response.BodyInfo); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化响应体元信息失败: %v\n", err)
+		} else {
+			responseBodyInfoJSON = stringPtr(string(jsonBytes))
+		}
+		if response.BodyInfo.BlobKey != "" {
+			responseBlobKey = stringPtr(response.BodyInfo.BlobKey)
+		}
 	}
 
+	// 序列化断言结果
+	var assertionResultsJSON *string
+	if len(response.AssertionResults) > 0 {
+		if jsonBytes, err := json.Marshal(response.AssertionResults); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化断言结果失败: %v\n", err)
+		} else {
+			assertionResultsJSON = stringPtr(string(jsonBytes))
+		}
+	}
+
+	// 序列化重定向链
+	var redirectChainJSON *string
+	if len(response.RedirectChain) > 0 {
+		if jsonBytes, err := json.Marshal(response.RedirectChain); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化重定向链失败: %v\n", err)
+		} else {
+			redirectChainJSON = stringPtr(string(jsonBytes))
+		}
+	}
+
+	// 序列化重试记录
+	var attemptsJSON *string
+	if len(response.Attempts) > 0 {
+		if jsonBytes, err := json.Marshal(response.Attempts); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化重试记录失败: %v\n", err)
+		} else {
+			attemptsJSON = stringPtr(string(jsonBytes))
+		}
+	}
+
+	// 序列化参数来源
+	var paramSourcesJSON *string
+	if len(response.ParamSources) > 0 {
+		if jsonBytes, err := json.Marshal(response.ParamSources); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化参数来源失败: %v\n", err)
+		} else {
+			paramSourcesJSON = stringPtr(string(jsonBytes))
+		}
+	}
+
+	record := &entity.ApiInterfaceExecutionRecord{
+		InterfaceID:       basic.Ptr(apiInterface.ID),
+		InterfaceVersion:  apiInterface.Version,
+		EnvironmentID:     request.EnvironmentID,
+		WorkflowRunID:     request.WorkflowRunID,
+		ScheduleID:        request.ScheduleID,
+		BatchID:           request.BatchID,
+		ReplayOfID:        request.ReplayOfID,
+		ExecutorID:        executorID,
+		ExecutorName:      executorName,
+		RequestParams:     stringPtr(string(requestParamsJSON)),
+		RequestPathParams: stringPtr(string(requestPathParamsJSON)),
+		RequestHeaders:    stringPtr(string(requestHeadersJSON)),
+		RequestBody:       stringPtr(string(requestBodyJSON)),
+		RequestFiles:      requestFilesJSON,
+		ResponseStatus:    basic.Ptr(response.Status),
+		ResponseHeaders:   stringPtr(string(responseHeadersJSON)),
+		ResponseBody:      response.Body,
+		ResponseBodyInfo:  responseBodyInfoJSON,
+		ResponseBlobKey:   responseBlobKey,
+		RedirectChain:     redirectChainJSON,
+		Attempts:          attemptsJSON,
+		ParamSources:      paramSourcesJSON,
+		AssertionResults:  assertionResultsJSON,
+		ExecutionTime:     basic.Ptr(response.ResponseTime),
+		Success:           basic.Ptr(response.Success),
+		ErrorMessage:      response.Error,
+		Remark:            remark,
+		ClientIP:          basic.Ptr(clientIP),
+		UserAgent:         basic.Ptr(userAgent),
+	}
+
+	// 持久化前脱敏敏感信息
+	s.redactor.RedactRecord(record, apiInterface)
+
 	if err := s.apiInterfaceExecutionRecordRepo.Create(record); err != nil {
 		logx.Errorf(context.Background(), logx.NameApp, "保存接口执行记录失败: %v\n", err)
+		return
 	}
+	response.RecordID = basic.Ptr(record.ID)
+}
+
+// validateForm 验证接口表单：POST类型、默认环境与认证配置，currentAuthProfileID 为接口原有的认证配置
+func (s *ApiInterfaceService) validateForm(form *dto.ApiInterfaceFormDto, currentAuthProfileID *uint64, uid uint64) error {
+	s.applyRawDataType(form)
+	if err := s.validatePostType(form); err != nil {
+		return err
+	}
+	if err := s.validateEnvironment(form); err != nil {
+		return err
+	}
+	if err := validateTagNames(form.Tags); err != nil {
+		return err
+	}
+	return s.validateAuthProfile(form, currentAuthProfileID, uid)
 }
 
 // validatePostType 验证POST类型
@@ -502,86 +1152,86 @@ func (s *ApiInterfaceService) validatePostType(form *dto.ApiInterfaceFormDto) er
 	}
 
 	method := strings.ToUpper(*form.Method)
+	if enums.HttpMethodFromCode(method) == nil {
+		return fmt.Errorf("不支持的HTTP方法: %s", *form.Method)
+	}
 	if method == "POST" || method == "PUT" || method == "PATCH" {
 		if form.PostType == nil || *form.PostType == "" {
 			return fmt.Errorf("POST类型为必填项")
 		}
 	}
-	return nil
-}
 
-// validateRequiredParams 验证必填参数
-func (s *ApiInterfaceService) validateRequiredParams(apiInterface *entity.ApiInterface, request *dto.ApiExecuteRequestDto) error {
-	// 解析参数配置
-	if apiInterface.Params == nil || *apiInterface.Params == "" {
-		return nil
+	if form.HttpVersion != nil && *form.HttpVersion != "" && enums.HttpVersionFromCode(*form.HttpVersion) == nil {
+		return fmt.Errorf("不支持的HTTP版本: %s", *form.HttpVersion)
 	}
-
-	var params []dto.ApiParamDto
-	if err := json.Unmarshal([]byte(*apiInterface.Params), &params); err != nil {
-		logx.Errorf(context.Background(), logx.NameApp, "解析接口参数失败: %v\n", err)
-		return nil
+	if form.MaxRedirects != nil && *form.MaxRedirects < 0 {
+		return fmt.Errorf("最大重定向次数不能小于0")
 	}
 
-	// 验证URL参数
-	for _, param := range params {
-		if param.ParamType != nil && *param.ParamType == "URL_PARAM" {
-			if param.Required != nil && *param.Required {
-				if param.Name != nil {
-					if request.URLParams == nil {
-						return fmt.Errorf("URL参数 %s 为必填项，不能为空", *param.Name)
-					}
-					if _, exists := request.URLParams[*param.Name]; !exists {
-						return fmt.Errorf("URL参数 %s 为必填项，不能为空", *param.Name)
-					}
-				}
-			}
-		}
+	if err := validateAssertions(form.Assertions); err != nil {
+		return err
+	}
+	if err := validateRetryPolicy(form.RetryPolicy); err != nil {
+		return err
 	}
 
-	// 验证Header参数
-	for _, param := range params {
-		if param.ParamType != nil && *param.ParamType == "HEADER_PARAM" {
-			if param.Required != nil && *param.Required {
-				if param.Name != nil {
-					if request.Headers == nil {
-						return fmt.Errorf("Header参数 %s 为必填项，不能为空", *param.Name)
-					}
-					if _, exists := request.Headers[*param.Name]; !exists {
-						return fmt.Errorf("Header参数 %s 为必填项，不能为空", *param.Name)
-					}
-				}
+	if form.BodyMode != nil && *form.BodyMode != "" {
+		bodyMode := enums.BodyModeFromCode(*form.BodyMode)
+		if bodyMode == nil {
+			return fmt.Errorf("不支持的请求体模式: %s", *form.BodyMode)
+		}
+		if *bodyMode == enums.BodyModeRaw && form.PostType != nil && *form.PostType != "" {
+			postType := enums.PostTypeFromCode(*form.PostType)
+			if postType == nil || !postType.IsRawBodyType() {
+				return fmt.Errorf("RAW模式不支持的请求体类型: %s", *form.PostType)
 			}
 		}
 	}
+	return nil
+}
 
-	// 验证Body参数
-	for _, param := range params {
-		if param.ParamType != nil && *param.ParamType == "BODY_PARAM" {
-			if param.Required != nil && *param.Required {
-				if param.Name != nil {
-					if request.BodyParams == nil {
-						return fmt.Errorf("Body参数 %s 为必填项，不能为空", *param.Name)
-					}
-					if _, exists := request.BodyParams[*param.Name]; !exists {
-						return fmt.Errorf("Body参数 %s 为必填项，不能为空", *param.Name)
-					}
-				}
-			}
+// applyRawDataType RAW模式下未指定POST类型时，根据代码编辑器的数据类型推导
+func (s *ApiInterfaceService) applyRawDataType(form *dto.ApiInterfaceFormDto) {
+	if form.BodyMode == nil || *form.BodyMode != enums.BodyModeRaw.Code() {
+		return
+	}
+	if form.PostType != nil && *form.PostType != "" {
+		return
+	}
+	if form.RawDataType != nil {
+		if postType := enums.PostTypeFromDataType(*form.RawDataType); postType != nil {
+			form.PostType = basic.Ptr(postType.Code())
 		}
 	}
+}
 
+// validateEnvironment 验证默认环境
+func (s *ApiInterfaceService) validateEnvironment(form *dto.ApiInterfaceFormDto) error {
+	if form.EnvironmentID == nil {
+		return nil
+	}
+	if _, err := s.apiEnvironmentRepo.FindByID(*form.EnvironmentID); err != nil {
+		return fmt.Errorf("默认环境不存在")
+	}
 	return nil
 }
 
+// validateAuthProfile 验证认证配置，引用新的认证配置需要使用权限
+func (s *ApiInterfaceService) validateAuthProfile(form *dto.ApiInterfaceFormDto, currentID *uint64, uid uint64) error {
+	return s.authProfileService.ValidateReference(form.AuthProfileID, currentID, uid)
+}
+
 // convertToDto 转换实体为DTO
 func (s *ApiInterfaceService) convertToDto(entity *entity.ApiInterface) dto.ApiInterfaceDto {
 	// 解析参数
-	var urlParams, headerParams, bodyParams []dto.ApiParamDto
+	var urlParams, pathParams, headerParams, bodyParams []dto.ApiParamDto
 	if entity.Params != nil && *entity.Params != "" {
 		if err := json.Unmarshal([]byte(*entity.Params), &urlParams); err != nil {
 			logx.Errorf(context.Background(), logx.NameApp, "解析URL参数失败: %v\n", err)
 		}
+		if err := json.Unmarshal([]byte(*entity.Params), &pathParams); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "解析Path参数失败: %v\n", err)
+		}
 		if err := json.Unmarshal([]byte(*entity.Params), &headerParams); err != nil {
 			logx.Errorf(context.Background(), logx.NameApp, "解析Header参数失败: %v\n", err)
 		}
@@ -591,6 +1241,7 @@ func (s *ApiInterfaceService) convertToDto(entity *entity.ApiInterface) dto.ApiI
 
 		// 过滤参数类型
 		filteredURLParams := make([]dto.ApiParamDto, 0)
+		filteredPathParams := make([]dto.ApiParamDto, 0)
 		filteredHeaderParams := make([]dto.ApiParamDto, 0)
 		filteredBodyParams := make([]dto.ApiParamDto, 0)
 
@@ -599,6 +1250,11 @@ func (s *ApiInterfaceService) convertToDto(entity *entity.ApiInterface) dto.ApiI
 				filteredURLParams = append(filteredURLParams, p)
 			}
 		}
+		for _, p := range pathParams {
+			if p.ParamType != nil && *p.ParamType == "PATH_PARAM" {
+				filteredPathParams = append(filteredPathParams, p)
+			}
+		}
 		for _, p := range headerParams {
 			if p.ParamType != nil && *p.ParamType == "HEADER_PARAM" {
 				filteredHeaderParams = append(filteredHeaderParams, p)
@@ -611,29 +1267,79 @@ func (s *ApiInterfaceService) convertToDto(entity *entity.ApiInterface) dto.ApiI
 		}
 
 		urlParams = filteredURLParams
+		pathParams = filteredPathParams
 		headerParams = filteredHeaderParams
 		bodyParams = filteredBodyParams
 	}
 
+	var rawDataType *string
+	if isRawBodyMode(entity) && entity.PostType != nil {
+		if postType := enums.PostTypeFromCode(*entity.PostType); postType != nil {
+			rawDataType = basic.Ptr(postType.DataType().Code())
+		}
+	}
+
 	createTime := util.Format(&entity.CreateTime)
 	updateTime := util.Format(&entity.UpdateTime)
 
 	return dto.ApiInterfaceDto{
-		ID:           basic.Ptr(entity.ID),
-		Name:         basic.Ptr(entity.Name),
-		Method:       basic.Ptr(entity.Method),
-		URL:          basic.Ptr(entity.URL),
-		Description:  entity.Description,
-		Status:       entity.Status,
-		PostType:     entity.PostType,
-		Environment:  entity.Environment,
-		Timeout:      entity.Timeout,
-		ValuePath:    entity.ValuePath,
-		URLParams:    urlParams,
-		HeaderParams: headerParams,
-		BodyParams:   bodyParams,
-		CreateTime:   basic.Ptr(createTime),
-		UpdateTime:   basic.Ptr(updateTime),
+		ID:              basic.Ptr(entity.ID),
+		Name:            basic.Ptr(entity.Name),
+		Method:          basic.Ptr(entity.Method),
+		URL:             basic.Ptr(entity.URL),
+		Description:     entity.Description,
+		Status:          entity.Status,
+		PostType:        entity.PostType,
+		BodyMode:        entity.BodyMode,
+		RawBody:         entity.RawBody,
+		RawDataType:     rawDataType,
+		Environment:     entity.Environment,
+		EnvironmentID:   entity.EnvironmentID,
+		GroupID:         entity.GroupID,
+		AuthProfileID:   entity.AuthProfileID,
+		Timeout:         entity.Timeout,
+		FollowRedirects: entity.FollowRedirects,
+		MaxRedirects:    entity.MaxRedirects,
+		HttpVersion:     entity.HttpVersion,
+		ValuePath:       entity.ValuePath,
+		URLParams:       urlParams,
+		PathParams:      pathParams,
+		HeaderParams:    headerParams,
+		BodyParams:      bodyParams,
+		Assertions:      parseInterfaceAssertions(entity),
+		RetryPolicy:     parseInterfaceRetryPolicy(entity),
+		Transport:       convertTransportToDto(entity.TransportConfig, entity.TransportSecret),
+		Version:         entity.Version,
+		CreateTime:      basic.Ptr(createTime),
+		UpdateTime:      basic.Ptr(updateTime),
+	}
+}
+
+// convertToDetailDto 转换实体为DTO并填充标签、负责人与 uid 的权限
+func (s *ApiInterfaceService) convertToDetailDto(entity *entity.ApiInterface, uid uint64) dto.ApiInterfaceDto {
+	interfaces := []dto.ApiInterfaceDto{s.convertToDto(entity)}
+	s.fillTags(interfaces)
+	if filled := s.aclService.FillAccess(interfaces, uid); len(filled) > 0 {
+		return filled[0]
+	}
+	return interfaces[0]
+}
+
+// fillTags 批量填充接口的标签
+func (s *ApiInterfaceService) fillTags(interfaces []dto.ApiInterfaceDto) {
+	if len(interfaces) == 0 {
+		return
+	}
+	ids := make([]uint64, 0, len(interfaces))
+	for _, interfaceDto := range interfaces {
+		ids = append(ids, *interfaceDto.ID)
+	}
+	tagNames := s.groupService.TagNames(ids)
+	for i := range interfaces {
+		interfaces[i].Tags = tagNames[*interfaces[i].ID]
+		if interfaces[i].Tags == nil {
+			interfaces[i].Tags = []string{}
+		}
 	}
 }
 
@@ -644,6 +1350,9 @@ func (s *ApiInterfaceService) convertToEntity(form *dto.ApiInterfaceFormDto) *en
 	if form.URLParams != nil {
 		allParams = append(allParams, form.URLParams...)
 	}
+	if form.PathParams != nil {
+		allParams = append(allParams, form.PathParams...)
+	}
 	if form.HeaderParams != nil {
 		allParams = append(allParams, form.HeaderParams...)
 	}
@@ -663,16 +1372,46 @@ func (s *ApiInterfaceService) convertToEntity(form *dto.ApiInterfaceFormDto) *en
 		}
 	}
 
+	var assertionsJSON *string
+	if len(form.Assertions) > 0 {
+		jsonBytes, err := json.Marshal(form.Assertions)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化断言失败: %v\n", err)
+		} else {
+			assertionsJSON = basic.Ptr(string(jsonBytes))
+		}
+	}
+
+	var retryPolicyJSON *string
+	if form.RetryPolicy != nil {
+		jsonBytes, err := json.Marshal(form.RetryPolicy)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "序列化重试策略失败: %v\n", err)
+		} else {
+			retryPolicyJSON = basic.Ptr(string(jsonBytes))
+		}
+	}
+
 	return &entity.ApiInterface{
-		Name:        *form.Name,
-		Method:      *form.Method,
-		URL:         *form.URL,
-		Description: form.Description,
-		PostType:    form.PostType,
-		Environment: form.Environment,
-		Timeout:     form.Timeout,
-		ValuePath:   form.ValuePath,
-		Params:      paramsJSON,
+		Name:            *form.Name,
+		Method:          *form.Method,
+		URL:             *form.URL,
+		Description:     form.Description,
+		PostType:        form.PostType,
+		BodyMode:        form.BodyMode,
+		RawBody:         form.RawBody,
+		Environment:     form.Environment,
+		EnvironmentID:   form.EnvironmentID,
+		GroupID:         form.GroupID,
+		AuthProfileID:   form.AuthProfileID,
+		Timeout:         form.Timeout,
+		FollowRedirects: form.FollowRedirects,
+		MaxRedirects:    form.MaxRedirects,
+		HttpVersion:     form.HttpVersion,
+		ValuePath:       form.ValuePath,
+		Params:          paramsJSON,
+		Assertions:      assertionsJSON,
+		RetryPolicy:     retryPolicyJSON,
 	}
 }
 
@@ -718,6 +1457,12 @@ func (s *ApiInterfaceService) processParams(apiInterface *entity.ApiInterface, r
 		}
 	}
 
+	// 处理Path参数：路径段只接受标量值，原样保留
+	processedPathParams := make(map[string]any)
+	for k, v := range req.PathParams {
+		processedPathParams[k] = v
+	}
+
 	// 处理Header参数
 	processedHeaders := make(map[string]string)
 	if req.Headers != nil {
@@ -754,13 +1499,50 @@ func (s *ApiInterfaceService) processParams(apiInterface *entity.ApiInterface, r
 	}
 
 	return &dto.ApiExecuteRequestDto{
-		InterfaceID: req.InterfaceID,
-		Headers:     processedHeaders,
-		URLParams:   processedURLParams,
-		BodyParams:  processedBodyParams,
-		Timeout:     req.Timeout,
-		Remark:      req.Remark,
+		InterfaceID:   req.InterfaceID,
+		EnvironmentID: req.EnvironmentID,
+		Headers:       processedHeaders,
+		URLParams:     processedURLParams,
+		PathParams:    processedPathParams,
+		BodyParams:    processedBodyParams,
+		RawBody:       req.RawBody,
+		Variables:     req.Variables,
+		Files:         req.Files,
+		Timeout:       req.Timeout,
+		Remark:        req.Remark,
+		WorkflowRunID: req.WorkflowRunID,
+		ScheduleID:    req.ScheduleID,
+		BatchID:       req.BatchID,
+		ReplayOfID:    req.ReplayOfID,
+	}
+}
+
+// isRawBodyMode 判断接口是否为RAW请求体模式
+func isRawBodyMode(apiInterface *entity.ApiInterface) bool {
+	return apiInterface.BodyMode != nil && *apiInterface.BodyMode == enums.BodyModeRaw.Code()
+}
+
+// hasUploadFile 判断是否上传了指定参数的文件
+func hasUploadFile(files []dto.ApiUploadFileDto, paramName string) bool {
+	for _, file := range files {
+		if file.ParamName == paramName && len(file.Content) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInterfaceParams 解析接口参数配置
+func parseInterfaceParams(apiInterface *entity.ApiInterface) []dto.ApiParamDto {
+	if apiInterface.Params == nil || *apiInterface.Params == "" {
+		return nil
+	}
+	var params []dto.ApiParamDto
+	if err := json.Unmarshal([]byte(*apiInterface.Params), &params); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析接口参数失败: %v\n", err)
+		return nil
 	}
+	return params
 }
 
 // stringPtr 字符串指针辅助函数
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+)
+
+// snippetRequest 生成代码片段所需的请求信息
+type snippetRequest struct {
+	method  string
+	url     string
+	headers [][2]string
+	body    *string
+	form    []snippetFormField // multipart 表单字段，存在时忽略 body
+}
+
+// snippetFormField multipart 表单字段
+type snippetFormField struct {
+	name  string
+	value string // 文件字段为文件名
+	file  bool
+}
+
+// newSnippetRequest 根据处理后的请求参数构建代码片段请求
+// 认证配置中的密钥不会写入代码片段
+func newSnippetRequest(apiInterface *entity.ApiInterface, finalURL string, req *dto.ApiExecuteRequestDto) (*snippetRequest, error) {
+	snippet := &snippetRequest{
+		method: strings.ToUpper(apiInterface.Method),
+		url:    finalURL,
+	}
+	headerNames := make([]string, 0, len(req.Headers))
+	for name := range req.Headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	for _, name := range headerNames {
+		snippet.headers = append(snippet.headers, [2]string{name, req.Headers[name]})
+	}
+
+	postType := requestPostType(apiInterface)
+	if !hasRequestBody(apiInterface, postType, req) {
+		return snippet, nil
+	}
+	if !isRawBodyMode(apiInterface) && postType == "multipart/form-data" {
+		snippet.removeHeader("Content-Type")
+		names := make([]string, 0, len(req.BodyParams))
+		for name := range req.BodyParams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value := req.BodyParams[name]; value != nil {
+				for _, item := range paramValues(value) {
+					snippet.form = append(snippet.form, snippetFormField{name: name, value: item})
+				}
+			}
+		}
+		for _, file := range req.Files {
+			snippet.form = append(snippet.form, snippetFormField{name: file.ParamName, value: file.FileName, file: true})
+		}
+		return snippet, nil
+	}
+
+	contentType, body, err := encodeRequestBody(apiInterface, postType, req)
+	if err != nil {
+		return nil, err
+	}
+	snippet.removeHeader("Content-Type")
+	snippet.headers = append(snippet.headers, [2]string{"Content-Type", contentType})
+	snippet.body = &body
+	return snippet, nil
+}
+
+// removeHeader 移除指定名称的请求头（不区分大小写）
+func (r *snippetRequest) removeHeader(name string) {
+	headers := r.headers[:0]
+	for _, header := range r.headers {
+		if !strings.EqualFold(header[0], name) {
+			headers = append(headers, header)
+		}
+	}
+	r.headers = headers
+}
+
+// render 生成全部语言的代码片段
+func (r *snippetRequest) render() dto.ApiInterfaceSnippetDto {
+	return dto.ApiInterfaceSnippetDto{
+		Curl:       r.renderCurl(),
+		Go:         r.renderGo(),
+		Python:     r.renderPython(),
+		JavaScript: r.renderJavaScript(),
+	}
+}
+
+// renderCurl 生成 curl 命令
+func (r *snippetRequest) renderCurl() string {
+	lines := []string{"curl -X " + r.method + " " + shellQuote(r.url)}
+	for _, header := range r.headers {
+		lines = append(lines, "-H "+shellQuote(header[0]+": "+header[1]))
+	}
+	for _, field := range r.form {
+		value := field.value
+		if field.file {
+			value = "@" + value
+		}
+		lines = append(lines, "-F "+shellQuote(field.name+"="+value))
+	}
+	if r.body != nil {
+		lines = append(lines, "--data-raw "+shellQuote(*r.body))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
+
+// renderGo 生成 Go net/http 代码
+func (r *snippetRequest) renderGo() string {
+	imports := []string{"fmt", "io", "net/http"}
+	var b strings.Builder
+	bodyVar := "nil"
+	switch {
+	case len(r.form) > 0:
+		imports = append(imports, "bytes", "mime/multipart")
+		if r.hasFile() {
+			imports = append(imports, "os")
+		}
+		bodyVar = "&body"
+	case r.body != nil:
+		imports = append(imports, "strings")
+		bodyVar = "body"
+	}
+	sort.Strings(imports)
+
+	b.WriteString("package main\n\nimport (\n")
+	for _, item := range imports {
+		b.WriteString("\t" + strconv.Quote(item) + "\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	switch {
+	case len(r.form) > 0:
+		b.WriteString("\tvar body bytes.Buffer\n\twriter := multipart.NewWriter(&body)\n")
+		for _, field := range r.form {
+			if !field.file {
+				b.WriteString(fmt.Sprintf("\t_ = writer.WriteField(%s, %s)\n", strconv.Quote(field.name), strconv.Quote(field.value)))
+				continue
+			}
+			b.WriteString("\t{\n")
+			b.WriteString(fmt.Sprintf("\t\tfile, err := os.Open(%s)\n\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n", strconv.Quote(field.value)))
+			b.WriteString(fmt.Sprintf("\t\tpart, _ := writer.CreateFormFile(%s, %s)\n", strconv.Quote(field.name), strconv.Quote(field.value)))
+			b.WriteString("\t\t_, _ = io.Copy(part, file)\n\t\tfile.Close()\n\t}\n")
+		}
+		b.WriteString("\twriter.Close()\n\n")
+	case r.body != nil:
+		b.WriteString(fmt.Sprintf("\tbody := strings.NewReader(%s)\n\n", goStringLiteral(*r.body)))
+	}
+
+	b.WriteString(fmt.Sprintf("\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(r.method), strconv.Quote(r.url), bodyVar))
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, header := range r.headers {
+		b.WriteString(fmt.Sprintf("\treq.Header.Set(%s, %s)\n", strconv.Quote(header[0]), strconv.Quote(header[1])))
+	}
+	if len(r.form) > 0 {
+		b.WriteString("\treq.Header.Set(\"Content-Type\", writer.FormDataContentType())\n")
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tdata, _ := io.ReadAll(resp.Body)\n\tfmt.Println(resp.Status)\n\tfmt.Println(string(data))\n}\n")
+	return b.String()
+}
+
+// renderPython 生成 Python requests 代码
+func (r *snippetRequest) renderPython() string {
+	var b strings.Builder
+	b.WriteString("import requests\n\n")
+	b.WriteString("url = " + jsonStringLiteral(r.url) + "\n")
+	args := []string{jsonStringLiteral(r.method), "url"}
+
+	if len(r.headers) > 0 {
+		b.WriteString("headers = {\n")
+		for _, header := range r.headers {
+			b.WriteString(fmt.Sprintf("    %s: %s,\n", jsonStringLiteral(header[0]), jsonStringLiteral(header[1])))
+		}
+		b.WriteString("}\n")
+		args = append(args, "headers=headers")
+	}
+	if len(r.form) > 0 {
+		var data, files []string
+		for _, field := range r.form {
+			if field.file {
+				files = append(files, fmt.Sprintf("    %s: open(%s, \"rb\"),\n", jsonStringLiteral(field.name), jsonStringLiteral(field.value)))
+			} else {
+				data = append(data, fmt.Sprintf("    %s: %s,\n", jsonStringLiteral(field.name), jsonStringLiteral(field.value)))
+			}
+		}
+		if len(data) > 0 {
+			b.WriteString("data = {\n" + strings.Join(data, "") + "}\n")
+			args = append(args, "data=data")
+		}
+		if len(files) > 0 {
+			b.WriteString("files = {\n" + strings.Join(files, "") + "}\n")
+			args = append(args, "files=files")
+		}
+	} else if r.body != nil {
+		b.WriteString("data = " + jsonStringLiteral(*r.body) + "\n")
+		args = append(args, "data=data.encode(\"utf-8\")")
+	}
+
+	b.WriteString("\nresponse = requests.request(" + strings.Join(args, ", ") + ")\n")
+	b.WriteString("print(response.status_code)\nprint(response.text)\n")
+	return b.String()
+}
+
+// renderJavaScript 生成 JavaScript fetch 代码
+func (r *snippetRequest) renderJavaScript() string {
+	var b strings.Builder
+	if len(r.form) > 0 {
+		b.WriteString("const formData = new FormData();\n")
+		for _, field := range r.form {
+			if field.file {
+				b.WriteString(fmt.Sprintf("formData.append(%s, fileInput.files[0], %s);\n", jsonStringLiteral(field.name), jsonStringLiteral(field.value)))
+			} else {
+				b.WriteString(fmt.Sprintf("formData.append(%s, %s);\n", jsonStringLiteral(field.name), jsonStringLiteral(field.value)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("const response = await fetch(" + jsonStringLiteral(r.url) + ", {\n")
+	b.WriteString("  method: " + jsonStringLiteral(r.method) + ",\n")
+	if len(r.headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, header := range r.headers {
+			b.WriteString(fmt.Sprintf("    %s: %s,\n", jsonStringLiteral(header[0]), jsonStringLiteral(header[1])))
+		}
+		b.WriteString("  },\n")
+	}
+	if len(r.form) > 0 {
+		b.WriteString("  body: formData,\n")
+	} else if r.body != nil {
+		b.WriteString("  body: " + jsonStringLiteral(*r.body) + ",\n")
+	}
+	b.WriteString("});\n\nconsole.log(response.status);\nconsole.log(await response.text());\n")
+	return b.String()
+}
+
+// hasFile 是否包含文件字段
+func (r *snippetRequest) hasFile() bool {
+	for _, field := range r.form {
+		if field.file {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote 使用单引号转义 shell 参数
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// goStringLiteral Go 字符串字面量，多行文本优先使用反引号
+func goStringLiteral(value string) string {
+	if strings.Contains(value, "\n") && !strings.Contains(value, "`") {
+		return "`" + value + "`"
+	}
+	return strconv.Quote(value)
+}
+
+// jsonStringLiteral JSON 字符串字面量，可直接用于 Python 与 JavaScript
+func jsonStringLiteral(value string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return strconv.Quote(value)
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
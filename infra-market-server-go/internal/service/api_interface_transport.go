@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/util"
+)
+
+// ApiTransportService 接口与环境的传输层配置：自定义CA、客户端证书、SNI与代理
+// 规则：接口配置优先，其次为环境配置；客户端私钥与代理密码分别加密保存
+type ApiTransportService struct {
+	authService *AuthService
+	cipher      *util.SecretCipher
+}
+
+func NewApiTransportService(authService *AuthService, cipher *util.SecretCipher) *ApiTransportService {
+	return &ApiTransportService{authService: authService, cipher: cipher}
+}
+
+// transportSecrets 传输层敏感信息，保存时各字段为加密后的密文
+type transportSecrets struct {
+	ClientKey     string `json:"clientKey,omitempty"`
+	ProxyPassword string `json:"proxyPassword,omitempty"`
+}
+
+// transportSettings 执行时使用的传输层配置，敏感信息已解密
+type transportSettings struct {
+	config  dto.ApiTransportConfigDto
+	secrets transportSecrets
+}
+
+// CheckPermission 校验用户是否可以保存该传输层配置，跳过证书校验需要单独授权
+func (s *ApiTransportService) CheckPermission(form *dto.ApiTransportFormDto, uid uint64) error {
+	if form == nil || !form.InsecureSkipVerify {
+		return nil
+	}
+	if uid == 0 || !s.authService.HasPermission(uid, enums.TransportInsecurePermissionCode) {
+		return fmt.Errorf("无权配置跳过TLS证书校验")
+	}
+	return nil
+}
+
+// CheckConfigPermission 校验用户是否可以使用已保存的传输层配置，用于复制接口等沿用原配置的场景
+func (s *ApiTransportService) CheckConfigPermission(configJSON *string, uid uint64) error {
+	form, err := parseTransportForm(configJSON)
+	if err != nil {
+		return err
+	}
+	return s.CheckPermission(form, uid)
+}
+
+// Resolve 获取执行时使用的传输层配置：接口配置优先，其次为环境配置，均未配置时返回 nil
+func (s *ApiTransportService) Resolve(apiInterface *entity.ApiInterface, environment *entity.ApiEnvironment) (*transportSettings, error) {
+	configJSON, secretsJSON := apiInterface.TransportConfig, apiInterface.TransportSecret
+	owner := "接口 " + apiInterface.Name
+	if stringValue(configJSON) == "" && environment != nil {
+		configJSON, secretsJSON = environment.TransportConfig, environment.TransportSecret
+		owner = "环境 " + environment.Name
+	}
+	if stringValue(configJSON) == "" {
+		return nil, nil
+	}
+
+	settings := &transportSettings{}
+	if err := json.Unmarshal([]byte(*configJSON), &settings.config); err != nil {
+		return nil, fmt.Errorf("%s 的传输层配置解析失败: %w", owner, err)
+	}
+	secrets := parseTransportSecrets(secretsJSON)
+	for _, field := range []*string{&secrets.ClientKey, &secrets.ProxyPassword} {
+		if *field == "" {
+			continue
+		}
+		plain, err := s.cipher.Decrypt(*field)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "解密传输层密钥失败，%s, 错误: %v\n", owner, err)
+			return nil, fmt.Errorf("%s 的传输层密钥无法解密", owner)
+		}
+		*field = plain
+	}
+	settings.secrets = secrets
+	return settings, nil
+}
+
+// applyForm 校验表单并返回要保存的配置与加密后的敏感信息
+// form 为 nil 时保持原配置；表单内容为空时清除配置
+func (s *ApiTransportService) applyForm(form *dto.ApiTransportFormDto, currentConfig, currentSecrets *string) (*string, *string, error) {
+	if form == nil {
+		return currentConfig, currentSecrets, nil
+	}
+
+	config := form.ApiTransportConfigDto
+	config.ServerName = strings.TrimSpace(config.ServerName)
+	config.ProxyURL = strings.TrimSpace(config.ProxyURL)
+	config.ProxyUsername = strings.TrimSpace(config.ProxyUsername)
+
+	// 未填写私钥或代理密码时保留原值，证书或代理地址清空时一并清除；
+	// 代理地址或用户名变更时不沿用原代理密码，SNI或CA证书变更时不沿用原私钥，需要重新填写，避免将原密钥发送给新的目标
+	secrets := parseTransportSecrets(currentSecrets)
+	var current dto.ApiTransportConfigDto
+	if currentForm, err := parseTransportForm(currentConfig); err == nil {
+		current = currentForm.ApiTransportConfigDto
+	}
+	clientKey := form.ClientKey != nil && *form.ClientKey != ""
+	proxyPassword := form.ProxyPassword != nil && *form.ProxyPassword != ""
+	if config.ProxyURL != current.ProxyURL || config.ProxyUsername != current.ProxyUsername {
+		secrets.ProxyPassword = ""
+	}
+	clientKeyReset := false
+	if secrets.ClientKey != "" && (config.ServerName != current.ServerName || config.CACert != current.CACert) {
+		secrets.ClientKey = ""
+		clientKeyReset = true
+	}
+	if config.ClientCert == "" {
+		if clientKey {
+			return nil, nil, fmt.Errorf("配置客户端私钥时必须同时配置客户端证书")
+		}
+		secrets.ClientKey = ""
+	}
+	if config.ProxyURL == "" {
+		config.ProxyUsername = ""
+		secrets.ProxyPassword = ""
+		proxyPassword = false
+	}
+
+	if config.CACert != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(config.CACert)) {
+		return nil, nil, fmt.Errorf("CA证书格式错误，需为PEM格式")
+	}
+	if config.ClientCert != "" {
+		// 只更新证书时使用原私钥校验证书与私钥是否匹配
+		keyPEM := ""
+		if clientKey {
+			keyPEM = *form.ClientKey
+		} else if secrets.ClientKey != "" {
+			plain, err := s.cipher.Decrypt(secrets.ClientKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("原客户端私钥无法解密，请重新填写")
+			}
+			keyPEM = plain
+		}
+		if keyPEM == "" && clientKeyReset {
+			return nil, nil, fmt.Errorf("SNI或CA证书已变更，请重新填写客户端私钥")
+		}
+		if keyPEM == "" {
+			return nil, nil, fmt.Errorf("客户端私钥不能为空")
+		}
+		if _, err := tls.X509KeyPair([]byte(config.ClientCert), []byte(keyPEM)); err != nil {
+			return nil, nil, fmt.Errorf("客户端证书或私钥无效: %w", err)
+		}
+	}
+	if config.ProxyURL != "" {
+		if _, err := parseProxyURL(config.ProxyURL); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, item := range []struct {
+		set    bool
+		value  *string
+		target *string
+	}{
+		{clientKey, form.ClientKey, &secrets.ClientKey},
+		{proxyPassword, form.ProxyPassword, &secrets.ProxyPassword},
+	} {
+		if !item.set {
+			continue
+		}
+		encrypted, err := s.cipher.Encrypt(*item.value)
+		if err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "加密传输层密钥失败: %v\n", err)
+			return nil, nil, fmt.Errorf("传输层密钥加密失败")
+		}
+		*item.target = encrypted
+	}
+
+	var configJSON, secretsJSON *string
+	if config != (dto.ApiTransportConfigDto{}) {
+		jsonBytes, err := json.Marshal(config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("传输层配置序列化失败")
+		}
+		configJSON = basic.Ptr(string(jsonBytes))
+	}
+	if secrets != (transportSecrets{}) {
+		jsonBytes, err := json.Marshal(secrets)
+		if err != nil {
+			return nil, nil, fmt.Errorf("传输层密钥序列化失败")
+		}
+		secretsJSON = basic.Ptr(string(jsonBytes))
+	}
+	return configJSON, secretsJSON, nil
+}
+
+// apply 将传输层配置写入HTTP传输层
+func (t *transportSettings) apply(transport *http.Transport) error {
+	if t == nil {
+		return nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         t.config.ServerName,
+		InsecureSkipVerify: t.config.InsecureSkipVerify,
+	}
+	if t.config.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(t.config.CACert)) {
+			return fmt.Errorf("CA证书格式错误，需为PEM格式")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if t.config.ClientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(t.config.ClientCert), []byte(t.secrets.ClientKey))
+		if err != nil {
+			return fmt.Errorf("客户端证书或私钥无效: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if t.config.ProxyURL != "" {
+		proxyURL, err := parseProxyURL(t.config.ProxyURL)
+		if err != nil {
+			return err
+		}
+		if t.config.ProxyUsername != "" {
+			proxyURL.User = url.UserPassword(t.config.ProxyUsername, t.secrets.ProxyPassword)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return nil
+}
+
+// parseProxyURL 解析代理地址，认证信息需填写在代理用户名与密码中，避免明文保存
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("无效的代理地址: %s", rawURL)
+	}
+	switch strings.ToLower(proxyURL.Scheme) {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("不支持的代理协议: %s，仅支持 http、https、socks5", proxyURL.Scheme)
+	}
+	if proxyURL.User != nil {
+		return nil, fmt.Errorf("代理地址中不能包含认证信息，请填写代理用户名与密码")
+	}
+	return proxyURL, nil
+}
+
+// parseTransportSecrets 解析保存的传输层密钥（密文）
+func parseTransportSecrets(secretsJSON *string) transportSecrets {
+	var secrets transportSecrets
+	if stringValue(secretsJSON) == "" {
+		return secrets
+	}
+	if err := json.Unmarshal([]byte(*secretsJSON), &secrets); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析传输层密钥失败: %v\n", err)
+	}
+	return secrets
+}
+
+// parseTransportForm 将已保存的传输层配置转换为表单，便于按保存时的规则重新校验；未配置时返回空表单
+func parseTransportForm(configJSON *string) (*dto.ApiTransportFormDto, error) {
+	form := &dto.ApiTransportFormDto{}
+	if stringValue(configJSON) == "" {
+		return form, nil
+	}
+	if err := json.Unmarshal([]byte(*configJSON), &form.ApiTransportConfigDto); err != nil {
+		return nil, fmt.Errorf("传输层配置解析失败: %w", err)
+	}
+	return form, nil
+}
+
+// convertTransportToDto 转换传输层配置为DTO，未配置时返回 nil
+func convertTransportToDto(configJSON, secretsJSON *string) *dto.ApiTransportDto {
+	if stringValue(configJSON) == "" {
+		return nil
+	}
+	result := &dto.ApiTransportDto{}
+	if err := json.Unmarshal([]byte(*configJSON), &result.ApiTransportConfigDto); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析传输层配置失败: %v\n", err)
+	}
+	secrets := parseTransportSecrets(secretsJSON)
+	result.HasClientKey = secrets.ClientKey != ""
+	result.HasProxyPassword = secrets.ProxyPassword != ""
+	return result
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+)
+
+func TestCheckConfigPermission(t *testing.T) {
+	s := &ApiTransportService{}
+	tests := []struct {
+		name    string
+		config  *string
+		wantErr bool
+	}{
+		{"未配置", nil, false},
+		{"空配置", stringPtr(""), false},
+		{"校验证书", stringPtr(`{"serverName":"api.example.com"}`), false},
+		{"跳过证书校验需要授权", stringPtr(`{"insecureSkipVerify":true}`), true},
+		{"配置无法解析", stringPtr(`{`), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// uid 为 0 时不查询权限，直接视为未授权
+			err := s.CheckConfigPermission(tt.config, 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckConfigPermission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newTestTransportService 创建使用测试密钥加密的传输层配置服务
+func newTestTransportService(t *testing.T) *ApiTransportService {
+	t.Helper()
+	return &ApiTransportService{cipher: newTestSecretCipher(t)}
+}
+
+// newTestClientCert 生成自签名的客户端证书与私钥（PEM）
+func newTestClientCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("生成证书失败: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("序列化私钥失败: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestApplyFormProxyPassword(t *testing.T) {
+	s := newTestTransportService(t)
+	proxyForm := func(proxyURL, username string, password *string) *dto.ApiTransportFormDto {
+		return &dto.ApiTransportFormDto{
+			ApiTransportConfigDto: dto.ApiTransportConfigDto{ProxyURL: proxyURL, ProxyUsername: username},
+			ProxyPassword:         password,
+		}
+	}
+	config, secrets, err := s.applyForm(proxyForm("http://proxy.example.com:8080", "alice", stringPtr("old-password")), nil, nil)
+	if err != nil {
+		t.Fatalf("applyForm() error: %v", err)
+	}
+	if strings.Contains(stringValue(secrets), "old-password") {
+		t.Fatalf("代理密码未加密保存: %s", stringValue(secrets))
+	}
+
+	tests := []struct {
+		name string
+		form *dto.ApiTransportFormDto
+		want string // 为空表示不保存代理密码
+	}{
+		{"配置不变时保留原密码", proxyForm("http://proxy.example.com:8080", "alice", nil), "old-password"},
+		{"代理地址变更时清除原密码", proxyForm("http://other.example.com:8080", "alice", nil), ""},
+		{"用户名变更时清除原密码", proxyForm("http://proxy.example.com:8080", "bob", nil), ""},
+		{"地址变更并重新填写密码", proxyForm("http://other.example.com:8080", "alice", stringPtr("new-password")), "new-password"},
+		{"清空代理地址", proxyForm("", "alice", stringPtr("new-password")), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, newSecrets, err := s.applyForm(tt.form, config, secrets)
+			if err != nil {
+				t.Fatalf("applyForm() error: %v", err)
+			}
+			got := ""
+			if password := parseTransportSecrets(newSecrets).ProxyPassword; password != "" {
+				if got, err = s.cipher.Decrypt(password); err != nil {
+					t.Fatalf("解密代理密码失败: %v", err)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("代理密码 = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFormClientKey(t *testing.T) {
+	s := newTestTransportService(t)
+	certPEM, keyPEM := newTestClientCert(t)
+	caPEM, _ := newTestClientCert(t)
+	clientForm := func(serverName, caCert string, clientKey *string) *dto.ApiTransportFormDto {
+		return &dto.ApiTransportFormDto{
+			ApiTransportConfigDto: dto.ApiTransportConfigDto{ClientCert: certPEM, ServerName: serverName, CACert: caCert},
+			ClientKey:             clientKey,
+		}
+	}
+	config, secrets, err := s.applyForm(clientForm("api.example.com", "", stringPtr(keyPEM)), nil, nil)
+	if err != nil {
+		t.Fatalf("applyForm() error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		form    *dto.ApiTransportFormDto
+		wantErr bool
+	}{
+		{"配置不变时保留原私钥", clientForm("api.example.com", "", nil), false},
+		{"SNI变更时需要重新填写私钥", clientForm("other.example.com", "", nil), true},
+		{"CA证书变更时需要重新填写私钥", clientForm("api.example.com", caPEM, nil), true},
+		{"SNI变更并重新填写私钥", clientForm("other.example.com", "", stringPtr(keyPEM)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, newSecrets, err := s.applyForm(tt.form, config, secrets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyForm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := s.cipher.Decrypt(parseTransportSecrets(newSecrets).ClientKey)
+			if err != nil || got != keyPEM {
+				t.Errorf("客户端私钥解密结果不一致: %v", err)
+			}
+		})
+	}
+}
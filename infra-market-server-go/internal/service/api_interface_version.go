@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+	"gorm.io/gorm"
+)
+
+// maskedSecret 版本对比时传输层密钥的展示值
+const maskedSecret = "******"
+
+// interfaceSnapshot 接口版本快照，传输层密钥在实体中不参与序列化，单独保存密文
+type interfaceSnapshot struct {
+	entity.ApiInterface
+	TransportSecret *string `json:"transportSecret,omitempty"`
+}
+
+// versionIgnoredFields 版本对比时忽略的字段：标识、时间、状态、版本号与分组不属于接口定义
+var versionIgnoredFields = []string{"id", "createTime", "updateTime", "status", "version", "params", "transportSecret", "groupId"}
+
+// versionJSONFields 以JSON字符串保存的字段，版本对比时逐字段对比
+var versionJSONFields = []string{"assertions", "retryPolicy", "transportConfig"}
+
+// ListVersions 分页查询接口的版本，需要查看权限
+func (s *ApiInterfaceService) ListVersions(query dto.ApiInterfaceVersionQueryDto, uid uint64) dto.ApiData[dto.PageResult[dto.ApiInterfaceVersionDto]] {
+	if err := s.aclService.CheckPermission(*query.InterfaceID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.PageResult[dto.ApiInterfaceVersionDto]](err.Error(), http.StatusForbidden)
+	}
+	versions, total, err := s.apiInterfaceVersionRepo.Page(query)
+	return PageResultBuilder(versions, total, err, convertVersionToDto, basic.Ptr(query))
+}
+
+// FindVersion 查询版本详情，包含该版本的接口定义，需要查看权限
+func (s *ApiInterfaceService) FindVersion(id uint64, uid uint64) dto.ApiData[dto.ApiInterfaceVersionDto] {
+	version, err := s.apiInterfaceVersionRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDto]("接口版本不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(version.InterfaceID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDto](err.Error(), http.StatusForbidden)
+	}
+	snapshot, err := parseInterfaceSnapshot(version)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDto](err.Error(), http.StatusInternalServerError)
+	}
+
+	result := convertVersionToDto(version)
+	interfaceDto := s.convertToDto(snapshot.entity())
+	result.Interface = &interfaceDto
+	return dto.Success(result)
+}
+
+// DiffVersions 对比同一接口的两个版本，需要查看权限
+func (s *ApiInterfaceService) DiffVersions(fromID, toID uint64, uid uint64) dto.ApiData[dto.ApiInterfaceVersionDiffDto] {
+	from, err := s.apiInterfaceVersionRepo.FindByID(fromID)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDiffDto]("接口版本不存在", http.StatusNotFound)
+	}
+	to, err := s.apiInterfaceVersionRepo.FindByID(toID)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDiffDto]("接口版本不存在", http.StatusNotFound)
+	}
+	if from.InterfaceID != to.InterfaceID {
+		return dto.Error[dto.ApiInterfaceVersionDiffDto]("只能对比同一接口的版本", http.StatusBadRequest)
+	}
+	if err := s.aclService.CheckPermission(from.InterfaceID, uid, enums.InterfacePermissionView); err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDiffDto](err.Error(), http.StatusForbidden)
+	}
+
+	fromSnapshot, err := parseInterfaceSnapshot(from)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDiffDto](err.Error(), http.StatusInternalServerError)
+	}
+	toSnapshot, err := parseInterfaceSnapshot(to)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceVersionDiffDto](err.Error(), http.StatusInternalServerError)
+	}
+
+	result := dto.ApiInterfaceVersionDiffDto{
+		From:   convertVersionToDto(from),
+		To:     convertVersionToDto(to),
+		Fields: diffInterfaceFields(fromSnapshot, toSnapshot),
+		Params: diffInterfaceParams(parseInterfaceParams(&fromSnapshot.ApiInterface), parseInterfaceParams(&toSnapshot.ApiInterface)),
+	}
+	result.Identical = len(result.Fields) == 0 && len(result.Params) == 0
+	return dto.Success(result)
+}
+
+// RestoreVersion 将接口恢复为指定版本的定义，恢复后产生新的版本，接口状态保持不变；需要编辑权限
+func (s *ApiInterfaceService) RestoreVersion(id uint64, uid uint64) dto.ApiData[dto.ApiInterfaceDto] {
+	version, err := s.apiInterfaceVersionRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceDto]("接口版本不存在", http.StatusNotFound)
+	}
+	existing, err := s.apiInterfaceRepo.FindByID(version.InterfaceID)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceDto]("接口不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(existing.ID, uid, enums.InterfacePermissionEdit); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
+	snapshot, err := parseInterfaceSnapshot(version)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusInternalServerError)
+	}
+	restored := snapshot.entity()
+
+	// 恢复的版本引用的环境与认证配置可能已被删除；传输层配置按保存时的规则重新校验，跳过证书校验需要恢复人也有权限
+	references := &dto.ApiInterfaceFormDto{EnvironmentID: restored.EnvironmentID, AuthProfileID: restored.AuthProfileID}
+	if err := s.validateEnvironment(references); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
+	}
+	if err := s.validateAuthProfile(references, existing.AuthProfileID, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
+	}
+	transportForm, err := parseTransportForm(restored.TransportConfig)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
+	}
+	if err := s.transportService.CheckPermission(transportForm, uid); err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusForbidden)
+	}
+	transportConfig, transportSecret, err := s.transportService.applyForm(transportForm, restored.TransportConfig, restored.TransportSecret)
+	if err != nil {
+		return dto.Error[dto.ApiInterfaceDto](err.Error(), http.StatusBadRequest)
+	}
+	restored.TransportConfig = transportConfig
+	restored.TransportSecret = transportSecret
+
+	restored.ID = existing.ID
+	restored.CreateTime = existing.CreateTime
+	restored.UpdateTime = time.Now().UnixMilli()
+	restored.Status = existing.Status
+	restored.GroupID = existing.GroupID
+
+	if err := s.saveWithVersion(restored, existing, enums.InterfaceVersionActionRestore, uid, basic.Ptr(version.Version), nil); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "恢复接口版本失败，接口ID: %d, 版本: %d, 错误: %v\n", existing.ID, version.Version, err)
+		return dto.Error[dto.ApiInterfaceDto]("恢复接口版本失败", http.StatusInternalServerError)
+	}
+
+	interfaceDto := s.convertToDetailDto(restored, uid)
+	return dto.Success(interfaceDto)
+}
+
+// saveWithVersion 在事务中保存接口并记录保存后的定义为新版本
+// previous 为空时创建接口并将操作人设为负责人，否则更新；首次为已有接口记录版本时，先将修改前的定义记录为基线版本
+// tags 为空时保持原标签，否则替换为指定的标签
+func (s *ApiInterfaceService) saveWithVersion(apiInterface, previous *entity.ApiInterface, action enums.InterfaceVersionAction, uid uint64, restoredFrom *int, tags []string) error {
+	editorName := s.executorName(uid)
+	return WithTransaction(s.db, func(tx *gorm.DB) error {
+		txInterfaceRepo := repository.NewApiInterfaceRepository(tx)
+		txVersionRepo := repository.NewApiInterfaceVersionRepository(tx)
+
+		if previous == nil {
+			apiInterface.Version = basic.Ptr(1)
+			if err := txInterfaceRepo.Create(apiInterface); err != nil {
+				return err
+			}
+			if uid != SystemExecutorID {
+				if err := addInterfaceOwner(tx, apiInterface.ID, uid); err != nil {
+					return err
+				}
+			}
+		} else {
+			latest, err := txVersionRepo.MaxVersion(previous.ID)
+			if err != nil {
+				return err
+			}
+			if latest == 0 {
+				baseline := *previous
+				baseline.Version = basic.Ptr(1)
+				version, err := newInterfaceVersion(&baseline, enums.InterfaceVersionActionBaseline, nil, nil, nil)
+				if err != nil {
+					return err
+				}
+				if err := txVersionRepo.Create(version); err != nil {
+					return err
+				}
+				latest = 1
+			}
+			apiInterface.Version = basic.Ptr(latest + 1)
+			if err := txInterfaceRepo.Update(apiInterface); err != nil {
+				return err
+			}
+		}
+
+		if tags != nil {
+			if err := replaceInterfaceTags(tx, apiInterface.ID, tags); err != nil {
+				return err
+			}
+		}
+
+		version, err := newInterfaceVersion(apiInterface, action, basic.Ptr(uid), basic.Ptr(editorName), restoredFrom)
+		if err != nil {
+			return err
+		}
+		return txVersionRepo.Create(version)
+	})
+}
+
+// executorName 获取操作人姓名，系统执行使用系统名称
+func (s *ApiInterfaceService) executorName(uid uint64) string {
+	if uid == SystemExecutorID {
+		return SystemExecutorName
+	}
+	if user, err := s.userRepo.FindByUID(uid); err == nil {
+		return user.Username
+	}
+	return "未知用户"
+}
+
+// newInterfaceVersion 根据接口当前定义创建版本
+func newInterfaceVersion(apiInterface *entity.ApiInterface, action enums.InterfaceVersionAction, editorID *uint64, editorName *string, restoredFrom *int) (*entity.ApiInterfaceVersion, error) {
+	snapshot, err := json.Marshal(interfaceSnapshot{ApiInterface: *apiInterface, TransportSecret: apiInterface.TransportSecret})
+	if err != nil {
+		return nil, fmt.Errorf("序列化接口快照失败: %w", err)
+	}
+	return &entity.ApiInterfaceVersion{
+		InterfaceID:  apiInterface.ID,
+		Version:      *apiInterface.Version,
+		Action:       action.Code(),
+		RestoredFrom: restoredFrom,
+		EditorID:     editorID,
+		EditorName:   editorName,
+		Snapshot:     string(snapshot),
+	}, nil
+}
+
+// parseInterfaceSnapshot 解析版本保存的接口快照
+func parseInterfaceSnapshot(version *entity.ApiInterfaceVersion) (*interfaceSnapshot, error) {
+	var snapshot interfaceSnapshot
+	if err := json.Unmarshal([]byte(version.Snapshot), &snapshot); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析接口快照失败，版本ID: %d, 错误: %v\n", version.ID, err)
+		return nil, fmt.Errorf("接口版本快照解析失败")
+	}
+	return &snapshot, nil
+}
+
+// entity 转换快照为接口实体
+func (s *interfaceSnapshot) entity() *entity.ApiInterface {
+	apiInterface := s.ApiInterface
+	apiInterface.TransportSecret = s.TransportSecret
+	return &apiInterface
+}
+
+// diffInterfaceFields 对比两个快照的接口字段，参数单独对比；传输层密钥只提示是否变化
+func diffInterfaceFields(from, to *interfaceSnapshot) []dto.ApiDiffItemDto {
+	items := make([]dto.ApiDiffItemDto, 0)
+	diffJSONNode(snapshotFields(from), snapshotFields(to), nil, nil, &items)
+
+	fromSecret, toSecret := stringValue(from.TransportSecret), stringValue(to.TransportSecret)
+	if fromSecret != toSecret {
+		item := dto.ApiDiffItemDto{Path: formatJSONPath([]any{"transportSecret"})}
+		switch {
+		case fromSecret == "":
+			item.Type, item.Current = enums.DiffTypeAdded.Code(), maskedSecret
+		case toSecret == "":
+			item.Type, item.Original = enums.DiffTypeRemoved.Code(), maskedSecret
+		default:
+			item.Type, item.Original, item.Current = enums.DiffTypeChanged.Code(), maskedSecret, maskedSecret
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// snapshotFields 将快照转换为用于对比的字段，JSON字符串字段解析为对象
+func snapshotFields(snapshot *interfaceSnapshot) map[string]any {
+	fields := make(map[string]any)
+	if jsonBytes, err := json.Marshal(snapshot.ApiInterface); err == nil {
+		_ = json.Unmarshal(jsonBytes, &fields)
+	}
+	for _, name := range versionIgnoredFields {
+		delete(fields, name)
+	}
+	for _, name := range versionJSONFields {
+		text, ok := fields[name].(string)
+		if !ok {
+			continue
+		}
+		var value any
+		if json.Unmarshal([]byte(text), &value) == nil {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// diffInterfaceParams 按参数类型与名称逐个对比参数定义
+func diffInterfaceParams(from, to []dto.ApiParamDto) []dto.ApiParamDiffDto {
+	type paramKey struct{ paramType, name string }
+	keyOf := func(param dto.ApiParamDto) paramKey {
+		return paramKey{paramType: stringValue(param.ParamType), name: stringValue(param.Name)}
+	}
+	// 未设置的字段不参与对比，新增或删除的参数只列出已设置的字段
+	toFields := func(param dto.ApiParamDto) map[string]any {
+		fields := make(map[string]any)
+		if jsonBytes, err := json.Marshal(param); err == nil {
+			_ = json.Unmarshal(jsonBytes, &fields)
+		}
+		for name, value := range fields {
+			if value == nil {
+				delete(fields, name)
+			}
+		}
+		return fields
+	}
+
+	fromParams := make(map[paramKey]dto.ApiParamDto, len(from))
+	for _, param := range from {
+		fromParams[keyOf(param)] = param
+	}
+	toParams := make(map[paramKey]dto.ApiParamDto, len(to))
+	for _, param := range to {
+		toParams[keyOf(param)] = param
+	}
+
+	keys := make([]paramKey, 0, len(fromParams)+len(toParams))
+	for key := range fromParams {
+		keys = append(keys, key)
+	}
+	for key := range toParams {
+		if _, ok := fromParams[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].paramType != keys[j].paramType {
+			return keys[i].paramType < keys[j].paramType
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	diffs := make([]dto.ApiParamDiffDto, 0)
+	for _, key := range keys {
+		fromParam, inFrom := fromParams[key]
+		toParam, inTo := toParams[key]
+		diff := dto.ApiParamDiffDto{Name: key.name, ParamType: key.paramType, Fields: make([]dto.ApiDiffItemDto, 0)}
+		switch {
+		case !inFrom:
+			diff.Type = enums.DiffTypeAdded.Code()
+			diffJSONNode(map[string]any{}, toFields(toParam), nil, nil, &diff.Fields)
+		case !inTo:
+			diff.Type = enums.DiffTypeRemoved.Code()
+			diffJSONNode(toFields(fromParam), map[string]any{}, nil, nil, &diff.Fields)
+		default:
+			diffJSONNode(toFields(fromParam), toFields(toParam), nil, nil, &diff.Fields)
+			if len(diff.Fields) == 0 {
+				continue
+			}
+			diff.Type = enums.DiffTypeChanged.Code()
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// convertVersionToDto 转换版本为DTO，不包含接口定义
+func convertVersionToDto(version *entity.ApiInterfaceVersion) dto.ApiInterfaceVersionDto {
+	return dto.ApiInterfaceVersionDto{
+		ID:           version.ID,
+		InterfaceID:  version.InterfaceID,
+		Version:      version.Version,
+		Action:       version.Action,
+		RestoredFrom: version.RestoredFrom,
+		EditorID:     version.EditorID,
+		EditorName:   version.EditorName,
+		CreateTime:   util.Format(&version.CreateTime),
+	}
+}
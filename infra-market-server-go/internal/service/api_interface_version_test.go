@@ -0,0 +1,126 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+)
+
+func TestInterfaceVersionSnapshot(t *testing.T) {
+	apiInterface := &entity.ApiInterface{
+		BaseEntity:      entity.BaseEntity{ID: 3},
+		Name:            "查询用户",
+		Method:          "GET",
+		URL:             "/users",
+		TransportSecret: basic.Ptr("ciphertext"),
+		Version:         basic.Ptr(2),
+	}
+	version, err := newInterfaceVersion(apiInterface, enums.InterfaceVersionActionUpdate, basic.Ptr(uint64(1)), basic.Ptr("admin"), nil)
+	if err != nil {
+		t.Fatalf("newInterfaceVersion() error = %v", err)
+	}
+	if version.InterfaceID != 3 || version.Version != 2 || version.Action != enums.InterfaceVersionActionUpdate.Code() {
+		t.Errorf("版本 = %+v", version)
+	}
+
+	snapshot, err := parseInterfaceSnapshot(version)
+	if err != nil {
+		t.Fatalf("parseInterfaceSnapshot() error = %v", err)
+	}
+	restored := snapshot.entity()
+	if restored.Name != "查询用户" || restored.URL != "/users" || stringValue(restored.TransportSecret) != "ciphertext" {
+		t.Errorf("恢复的接口 = %+v", restored)
+	}
+
+	if _, err := parseInterfaceSnapshot(&entity.ApiInterfaceVersion{Snapshot: "{"}); err == nil {
+		t.Error("快照格式错误应返回错误")
+	}
+}
+
+func TestDiffInterfaceFields(t *testing.T) {
+	from := &interfaceSnapshot{
+		ApiInterface: entity.ApiInterface{
+			BaseEntity:      entity.BaseEntity{ID: 1, UpdateTime: 1},
+			Name:            "查询用户",
+			Method:          "GET",
+			URL:             "/users",
+			Version:         basic.Ptr(1),
+			RetryPolicy:     basic.Ptr(`{"maxAttempts":2,"backoff":"FIXED"}`),
+			TransportConfig: basic.Ptr(`{"serverName":"a.example.com"}`),
+		},
+		TransportSecret: basic.Ptr("old"),
+	}
+	to := &interfaceSnapshot{
+		ApiInterface: entity.ApiInterface{
+			BaseEntity:      entity.BaseEntity{ID: 1, UpdateTime: 2},
+			Name:            "查询用户",
+			Method:          "POST",
+			URL:             "/users",
+			Version:         basic.Ptr(2),
+			GroupID:         basic.Ptr(uint64(5)),
+			RetryPolicy:     basic.Ptr(`{"maxAttempts":3,"backoff":"FIXED"}`),
+			TransportConfig: basic.Ptr(`{"serverName":"a.example.com"}`),
+		},
+		TransportSecret: basic.Ptr("new"),
+	}
+
+	items := diffInterfaceFields(from, to)
+	got := make(map[string]dto.ApiDiffItemDto, len(items))
+	for _, item := range items {
+		got[item.Path] = item
+	}
+	if len(got) != 3 {
+		t.Fatalf("diffInterfaceFields() = %+v, want method、retryPolicy.maxAttempts、transportSecret", items)
+	}
+	if got["$.method"].Original != "GET" || got["$.method"].Current != "POST" {
+		t.Errorf("method 差异 = %+v", got["$.method"])
+	}
+	if got["$.retryPolicy.maxAttempts"].Type != enums.DiffTypeChanged.Code() {
+		t.Errorf("JSON字段应逐字段对比: %+v", items)
+	}
+	secret := got["$.transportSecret"]
+	if secret.Original != maskedSecret || secret.Current != maskedSecret {
+		t.Errorf("传输层密钥不应展示明文: %+v", secret)
+	}
+
+	to.TransportSecret = nil
+	items = diffInterfaceFields(from, to)
+	last := items[len(items)-1]
+	if last.Path != "$.transportSecret" || last.Type != enums.DiffTypeRemoved.Code() || last.Original != maskedSecret || last.Current != nil {
+		t.Errorf("删除的传输层密钥 = %+v", last)
+	}
+}
+
+func TestDiffInterfaceParams(t *testing.T) {
+	page := newTestParam("page", enums.ParamTypeURL, enums.DataTypeINTEGER, false)
+	changedPage := newTestParam("page", enums.ParamTypeURL, enums.DataTypeINTEGER, true)
+	size := newTestParam("size", enums.ParamTypeURL, enums.DataTypeINTEGER, false)
+	token := newTestParam("token", enums.ParamTypeHeader, enums.DataTypeSTRING, false)
+	unchanged := newTestParam("q", enums.ParamTypeURL, enums.DataTypeSTRING, false)
+
+	diffs := diffInterfaceParams([]dto.ApiParamDto{page, token, unchanged}, []dto.ApiParamDto{changedPage, size, unchanged})
+	got := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		got = append(got, diff.ParamType+":"+diff.Name+"="+diff.Type)
+	}
+	want := []string{
+		enums.ParamTypeHeader.Code() + ":token=" + enums.DiffTypeRemoved.Code(),
+		enums.ParamTypeURL.Code() + ":page=" + enums.DiffTypeChanged.Code(),
+		enums.ParamTypeURL.Code() + ":size=" + enums.DiffTypeAdded.Code(),
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("diffInterfaceParams() = %v, want %v", got, want)
+	}
+	for _, diff := range diffs {
+		if diff.Name == "page" && (len(diff.Fields) != 1 || diff.Fields[0].Path != "$.required") {
+			t.Errorf("修改的参数字段 = %+v", diff.Fields)
+		}
+		if diff.Name == "size" && len(diff.Fields) == 0 {
+			t.Error("新增的参数应列出已设置的字段")
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	scheduleLockPrefix   = "schedule:lock:"
+	scheduleLockExpire   = 10 * time.Minute // 同一触发时间的锁保留时长，避免多副本重复触发
+	schedulePollInterval = 15 * time.Second // 调度器扫描到期计划的间隔
+	scheduleUserAgent    = "infra-market-scheduler"
+)
+
+type ApiScheduleService struct {
+	scheduleRepo        *repository.ApiScheduleRepository
+	apiInterfaceRepo    *repository.ApiInterfaceRepository
+	apiInterfaceService *ApiInterfaceService
+	aclService          *ApiInterfaceAclService
+	redisClient         *redis.Client
+	startOnce           sync.Once
+}
+
+func NewApiScheduleService(
+	scheduleRepo *repository.ApiScheduleRepository,
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	apiInterfaceService *ApiInterfaceService,
+	aclService *ApiInterfaceAclService,
+	redisClient *redis.Client,
+) *ApiScheduleService {
+	return &ApiScheduleService{
+		scheduleRepo:        scheduleRepo,
+		apiInterfaceRepo:    apiInterfaceRepo,
+		apiInterfaceService: apiInterfaceService,
+		aclService:          aclService,
+		redisClient:         redisClient,
+	}
+}
+
+// FindPage 分页查询计划，只返回可以执行的接口的计划
+func (s *ApiScheduleService) FindPage(query dto.ApiScheduleQueryDto, uid uint64) dto.ApiData[dto.PageResult[dto.ApiScheduleDto]] {
+	s.aclService.ApplyScheduleVisibility(&query, uid)
+	schedules, total, err := s.scheduleRepo.Page(query)
+	return PageResultBuilder(schedules, total, err, s.convertToDto, &query)
+}
+
+// FindByID 根据ID查询，需要接口的执行权限
+func (s *ApiScheduleService) FindByID(id uint64, uid uint64) dto.ApiData[dto.ApiScheduleDto] {
+	schedule, err := s.scheduleRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiScheduleDto]("定时计划不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(schedule.InterfaceID, uid, enums.InterfacePermissionExecute); err != nil {
+		return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusForbidden)
+	}
+	return dto.Success(s.convertToDto(schedule))
+}
+
+// Save 创建计划，需要接口的执行权限，计划触发时以创建人身份执行
+func (s *ApiScheduleService) Save(form dto.ApiScheduleFormDto, uid uint64) dto.ApiData[dto.ApiScheduleDto] {
+	if err := s.aclService.CheckPermission(form.InterfaceID, uid, enums.InterfacePermissionExecute); err != nil {
+		return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusForbidden)
+	}
+
+	schedule := &entity.ApiSchedule{Status: 1, CreatorID: uid}
+	if err := s.applyForm(schedule, &form); err != nil {
+		return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusBadRequest)
+	}
+
+	if err := s.scheduleRepo.Create(schedule); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "创建定时计划失败，计划名称: %s, 错误: %v\n", form.Name, err)
+		return dto.Error[dto.ApiScheduleDto]("创建定时计划失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(schedule))
+}
+
+// Update 更新计划，需要原接口与新接口的执行权限；没有创建人的历史计划由更新人接管
+func (s *ApiScheduleService) Update(id uint64, form dto.ApiScheduleFormDto, uid uint64) dto.ApiData[dto.ApiScheduleDto] {
+	schedule, err := s.scheduleRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiScheduleDto]("定时计划不存在", http.StatusNotFound)
+	}
+	for _, interfaceID := range []uint64{schedule.InterfaceID, form.InterfaceID} {
+		if err := s.aclService.CheckPermission(interfaceID, uid, enums.InterfacePermissionExecute); err != nil {
+			return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusForbidden)
+		}
+	}
+	if schedule.CreatorID == SystemExecutorID {
+		schedule.CreatorID = uid
+	}
+
+	if err := s.applyForm(schedule, &form); err != nil {
+		return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusBadRequest)
+	}
+
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新定时计划失败，计划ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiScheduleDto]("更新定时计划失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(schedule))
+}
+
+// Delete 删除计划，需要是计划创建人或有接口的编辑权限
+func (s *ApiScheduleService) Delete(id uint64, uid uint64) dto.ApiData[any] {
+	schedule, err := s.scheduleRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[any]("定时计划不存在", http.StatusNotFound)
+	}
+	if err := s.checkManagePermission(schedule, uid); err != nil {
+		return dto.Error[any](err.Error(), http.StatusForbidden)
+	}
+
+	if err := s.scheduleRepo.Delete(id); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "删除定时计划失败，计划ID: %d, 错误: %v\n", id, err)
+		return dto.Error[any]("删除定时计划失败", http.StatusInternalServerError)
+	}
+	return dto.Success[any](nil)
+}
+
+// Pause 暂停计划，需要是计划创建人或有接口的编辑权限
+func (s *ApiScheduleService) Pause(id uint64, uid uint64) dto.ApiData[dto.ApiScheduleDto] {
+	schedule, err := s.scheduleRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiScheduleDto]("定时计划不存在", http.StatusNotFound)
+	}
+	if err := s.checkManagePermission(schedule, uid); err != nil {
+		return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusForbidden)
+	}
+
+	schedule.Status = 0
+	schedule.NextFireTime = nil
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "暂停定时计划失败，计划ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiScheduleDto]("暂停定时计划失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(schedule))
+}
+
+// Resume 恢复计划，从当前时间重新计算下次触发时间，需要是计划创建人或有接口的编辑权限
+func (s *ApiScheduleService) Resume(id uint64, uid uint64) dto.ApiData[dto.ApiScheduleDto] {
+	schedule, err := s.scheduleRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiScheduleDto]("定时计划不存在", http.StatusNotFound)
+	}
+	if err := s.checkManagePermission(schedule, uid); err != nil {
+		return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusForbidden)
+	}
+
+	schedule.Status = 1
+	if err := s.refreshNextFireTime(schedule); err != nil {
+		return dto.Error[dto.ApiScheduleDto](err.Error(), http.StatusBadRequest)
+	}
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "恢复定时计划失败，计划ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiScheduleDto]("恢复定时计划失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(schedule))
+}
+
+// RunNow 以当前用户身份立即执行一次计划，需要接口的执行权限，不影响下次触发时间
+func (s *ApiScheduleService) RunNow(id uint64, uid uint64) dto.ApiData[dto.ApiExecuteResponseDto] {
+	schedule, err := s.scheduleRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiExecuteResponseDto]("定时计划不存在", http.StatusNotFound)
+	}
+	if err := s.aclService.CheckPermission(schedule.InterfaceID, uid, enums.InterfacePermissionExecute); err != nil {
+		return dto.Error[dto.ApiExecuteResponseDto](err.Error(), http.StatusForbidden)
+	}
+	return s.execute(schedule, uid)
+}
+
+// checkManagePermission 校验用户是否可以删除、暂停或恢复计划：计划创建人或有接口的编辑权限
+// 计划始终以创建人身份执行，恢复后执行时仍会校验创建人的接口权限
+func (s *ApiScheduleService) checkManagePermission(schedule *entity.ApiSchedule, uid uint64) error {
+	if schedule.CreatorID != SystemExecutorID && schedule.CreatorID == uid {
+		return nil
+	}
+	return s.aclService.CheckPermission(schedule.InterfaceID, uid, enums.InterfacePermissionEdit)
+}
+
+// Start 启动调度器，定期扫描到期的计划并执行
+// 多副本部署时通过 Redis 锁保证同一触发时间只有一个副本执行
+func (s *ApiScheduleService) Start() {
+	s.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(schedulePollInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				s.fireDueSchedules()
+			}
+		}()
+		logx.Infof(context.Background(), logx.NameApp, "接口定时调度器已启动，扫描间隔 %v", schedulePollInterval)
+	})
+}
+
+// fireDueSchedules 触发所有到期的计划
+func (s *ApiScheduleService) fireDueSchedules() {
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "定时调度异常: %v\n", r)
+		}
+	}()
+
+	schedules, err := s.scheduleRepo.FindDue(time.Now().UnixMilli())
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询到期定时计划失败: %v\n", err)
+		return
+	}
+
+	for i := range schedules {
+		schedule := schedules[i]
+		if !s.acquireLock(&schedule) {
+			continue
+		}
+
+		// 先推进下次触发时间，再异步执行，避免慢接口阻塞其他计划
+		// 无法计算下次触发时间时停用计划，否则过期的触发时间会在锁失效后被反复触发
+		if err := s.refreshNextFireTime(&schedule); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "计算定时计划下次触发时间失败，已停用计划，计划ID: %d, 错误: %v\n", schedule.ID, err)
+			fields := map[string]any{"status": 0, "next_fire_time": nil, "last_success": false, "last_error": err.Error()}
+			if err := s.scheduleRepo.UpdateFields(schedule.ID, fields); err != nil {
+				logx.Errorf(context.Background(), logx.NameApp, "停用定时计划失败，计划ID: %d, 错误: %v\n", schedule.ID, err)
+			}
+			continue
+		}
+		if err := s.scheduleRepo.UpdateFields(schedule.ID, map[string]any{"next_fire_time": schedule.NextFireTime}); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "更新定时计划下次触发时间失败，计划ID: %d, 错误: %v\n", schedule.ID, err)
+			continue
+		}
+		go s.execute(&schedule, schedule.CreatorID)
+	}
+}
+
+// acquireLock 获取计划本次触发的分布式锁
+func (s *ApiScheduleService) acquireLock(schedule *entity.ApiSchedule) bool {
+	key := fmt.Sprintf("%s%d:%d", scheduleLockPrefix, schedule.ID, *schedule.NextFireTime)
+	ok, err := s.redisClient.SetNX(context.Background(), key, 1, scheduleLockExpire).Result()
+	if err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "获取定时计划锁失败，计划ID: %d, 错误: %v\n", schedule.ID, err)
+		return false
+	}
+	return ok
+}
+
+// execute 以指定执行人身份执行计划，并记录最近一次执行结果
+// 执行时会校验执行人的接口权限；没有创建人的历史计划不再执行，需要重新保存
+func (s *ApiScheduleService) execute(schedule *entity.ApiSchedule, executorID uint64) dto.ApiData[dto.ApiExecuteResponseDto] {
+	fireTime := time.Now().UnixMilli()
+	if executorID == SystemExecutorID {
+		message := "定时计划缺少创建人，请重新保存计划后再执行"
+		fields := map[string]any{"last_fire_time": fireTime, "last_success": false, "last_record_id": nil, "last_error": message}
+		if err := s.scheduleRepo.UpdateFields(schedule.ID, fields); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "更新定时计划执行结果失败，计划ID: %d, 错误: %v\n", schedule.ID, err)
+		}
+		return dto.Error[dto.ApiExecuteResponseDto](message, http.StatusForbidden)
+	}
+
+	params := parseScheduleParams(schedule)
+	req := dto.ApiExecuteRequestDto{
+		InterfaceID:   basic.Ptr(schedule.InterfaceID),
+		EnvironmentID: params.EnvironmentID,
+		Headers:       params.Headers,
+		URLParams:     params.URLParams,
+		PathParams:    params.PathParams,
+		BodyParams:    params.BodyParams,
+		RawBody:       params.RawBody,
+		Variables:     params.Variables,
+		Timeout:       params.Timeout,
+		Remark:        basic.Ptr(fmt.Sprintf("定时计划: %s", schedule.Name)),
+		ScheduleID:    basic.Ptr(schedule.ID),
+	}
+
+	result := s.apiInterfaceService.Execute(req, executorID, "", scheduleUserAgent)
+
+	fields := map[string]any{"last_fire_time": fireTime}
+	if result.Code != http.StatusOK {
+		fields["last_success"] = false
+		fields["last_record_id"] = nil
+		fields["last_error"] = maskURLsInText(basic.Ptr(result.Message))
+	} else {
+		fields["last_success"] = result.Data.Success
+		fields["last_record_id"] = result.Data.RecordID
+		fields["last_error"] = result.Data.Error
+	}
+	if err := s.scheduleRepo.UpdateFields(schedule.ID, fields); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新定时计划执行结果失败，计划ID: %d, 错误: %v\n", schedule.ID, err)
+	}
+	return result
+}
+
+// refreshNextFireTime 根据cron表达式计算下次触发时间，计划未启用时清空
+func (s *ApiScheduleService) refreshNextFireTime(schedule *entity.ApiSchedule) error {
+	if schedule.Status != 1 {
+		schedule.NextFireTime = nil
+		return nil
+	}
+	cron, err := util.ParseCron(schedule.CronExpression, schedule.TimeZone)
+	if err != nil {
+		return err
+	}
+	next := cron.Next(time.Now())
+	if next.IsZero() {
+		schedule.NextFireTime = nil
+		return fmt.Errorf("cron表达式没有可触发的时间: %s", schedule.CronExpression)
+	}
+	schedule.NextFireTime = basic.Ptr(next.UnixMilli())
+	return nil
+}
+
+// applyForm 校验表单并写入实体
+func (s *ApiScheduleService) applyForm(schedule *entity.ApiSchedule, form *dto.ApiScheduleFormDto) error {
+	if _, err := s.apiInterfaceRepo.FindByID(form.InterfaceID); err != nil {
+		return fmt.Errorf("接口不存在")
+	}
+
+	schedule.Name = form.Name
+	schedule.InterfaceID = form.InterfaceID
+	schedule.CronExpression = form.CronExpression
+	schedule.TimeZone = time.Local.String()
+	if form.TimeZone != nil && *form.TimeZone != "" {
+		schedule.TimeZone = *form.TimeZone
+	}
+	schedule.Description = form.Description
+	if form.Status != nil {
+		schedule.Status = *form.Status
+	}
+	schedule.Params = nil
+	if jsonBytes, err := json.Marshal(form.Params); err == nil {
+		schedule.Params = basic.Ptr(string(jsonBytes))
+	}
+
+	if _, err := util.ParseCron(schedule.CronExpression, schedule.TimeZone); err != nil {
+		return err
+	}
+	return s.refreshNextFireTime(schedule)
+}
+
+// convertToDto 转换实体为DTO
+func (s *ApiScheduleService) convertToDto(schedule *entity.ApiSchedule) dto.ApiScheduleDto {
+	var nextFireTime, lastFireTime *string
+	if schedule.NextFireTime != nil {
+		nextFireTime = basic.Ptr(util.Format(schedule.NextFireTime))
+	}
+	if schedule.LastFireTime != nil {
+		lastFireTime = basic.Ptr(util.Format(schedule.LastFireTime))
+	}
+	return dto.ApiScheduleDto{
+		ID:             schedule.ID,
+		Name:           schedule.Name,
+		InterfaceID:    schedule.InterfaceID,
+		CronExpression: schedule.CronExpression,
+		TimeZone:       schedule.TimeZone,
+		Params:         parseScheduleParams(schedule),
+		Description:    schedule.Description,
+		Status:         schedule.Status,
+		NextFireTime:   nextFireTime,
+		LastFireTime:   lastFireTime,
+		LastSuccess:    schedule.LastSuccess,
+		LastRecordID:   schedule.LastRecordID,
+		LastError:      schedule.LastError,
+		CreatorID:      schedule.CreatorID,
+		CreateTime:     util.Format(&schedule.CreateTime),
+		UpdateTime:     util.Format(&schedule.UpdateTime),
+	}
+}
+
+// parseScheduleParams 解析计划的执行参数JSON
+func parseScheduleParams(schedule *entity.ApiSchedule) dto.ApiScheduleParamsDto {
+	var params dto.ApiScheduleParamsDto
+	if schedule.Params == nil || *schedule.Params == "" {
+		return params
+	}
+	if err := json.Unmarshal([]byte(*schedule.Params), &params); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析定时计划参数失败，计划ID: %d, 错误: %v\n", schedule.ID, err)
+	}
+	return params
+}
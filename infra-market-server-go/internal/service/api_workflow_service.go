@@ -0,0 +1,532 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bucketheadv/infra-go/basic"
+	"github.com/bucketheadv/infra-go/logx"
+	"github.com/bucketheadv/infra-market/internal/dto"
+	"github.com/bucketheadv/infra-market/internal/entity"
+	"github.com/bucketheadv/infra-market/internal/enums"
+	"github.com/bucketheadv/infra-market/internal/repository"
+	"github.com/bucketheadv/infra-market/internal/util"
+)
+
+// workflowVariableTarget 映射到模板变量的目标类型
+const workflowVariableTarget = "VARIABLE"
+
+type ApiWorkflowService struct {
+	workflowRepo        *repository.ApiWorkflowRepository
+	apiInterfaceRepo    *repository.ApiInterfaceRepository
+	apiEnvironmentRepo  *repository.ApiEnvironmentRepository
+	userRepo            *repository.UserRepository
+	recordRepo          *repository.ApiInterfaceExecutionRecordRepository
+	apiInterfaceService *ApiInterfaceService
+	redactor            *SecretRedactor
+	aclService          *ApiInterfaceAclService
+}
+
+func NewApiWorkflowService(
+	workflowRepo *repository.ApiWorkflowRepository,
+	apiInterfaceRepo *repository.ApiInterfaceRepository,
+	apiEnvironmentRepo *repository.ApiEnvironmentRepository,
+	userRepo *repository.UserRepository,
+	recordRepo *repository.ApiInterfaceExecutionRecordRepository,
+	apiInterfaceService *ApiInterfaceService,
+	redactor *SecretRedactor,
+	aclService *ApiInterfaceAclService,
+) *ApiWorkflowService {
+	return &ApiWorkflowService{
+		workflowRepo:        workflowRepo,
+		apiInterfaceRepo:    apiInterfaceRepo,
+		apiEnvironmentRepo:  apiEnvironmentRepo,
+		userRepo:            userRepo,
+		recordRepo:          recordRepo,
+		apiInterfaceService: apiInterfaceService,
+		redactor:            redactor,
+		aclService:          aclService,
+	}
+}
+
+// FindPage 分页查询工作流
+func (s *ApiWorkflowService) FindPage(query dto.ApiWorkflowQueryDto) dto.ApiData[dto.PageResult[dto.ApiWorkflowDto]] {
+	workflows, total, err := s.workflowRepo.Page(query)
+	return PageResultBuilder(workflows, total, err, s.convertToDto, &query)
+}
+
+// FindByID 根据ID查询
+func (s *ApiWorkflowService) FindByID(id uint64) dto.ApiData[dto.ApiWorkflowDto] {
+	workflow, err := s.workflowRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiWorkflowDto]("工作流不存在", http.StatusNotFound)
+	}
+	return dto.Success(s.convertToDto(workflow))
+}
+
+// Save 创建工作流
+func (s *ApiWorkflowService) Save(form dto.ApiWorkflowFormDto) dto.ApiData[dto.ApiWorkflowDto] {
+	if err := s.validateForm(&form); err != nil {
+		return dto.Error[dto.ApiWorkflowDto](err.Error(), http.StatusBadRequest)
+	}
+
+	workflow := &entity.ApiWorkflow{Status: 1}
+	s.applyForm(workflow, &form)
+
+	if err := s.workflowRepo.Create(workflow); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "创建工作流失败，工作流名称: %s, 错误: %v\n", form.Name, err)
+		return dto.Error[dto.ApiWorkflowDto]("创建工作流失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(workflow))
+}
+
+// Update 更新工作流
+func (s *ApiWorkflowService) Update(id uint64, form dto.ApiWorkflowFormDto) dto.ApiData[dto.ApiWorkflowDto] {
+	workflow, err := s.workflowRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiWorkflowDto]("工作流不存在", http.StatusNotFound)
+	}
+
+	if err := s.validateForm(&form); err != nil {
+		return dto.Error[dto.ApiWorkflowDto](err.Error(), http.StatusBadRequest)
+	}
+
+	s.applyForm(workflow, &form)
+
+	if err := s.workflowRepo.Update(workflow); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新工作流失败，工作流ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiWorkflowDto]("更新工作流失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(workflow))
+}
+
+// Delete 删除工作流
+func (s *ApiWorkflowService) Delete(id uint64) dto.ApiData[any] {
+	if _, err := s.workflowRepo.FindByID(id); err != nil {
+		return dto.Error[any]("工作流不存在", http.StatusNotFound)
+	}
+
+	if err := s.workflowRepo.Delete(id); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "删除工作流失败，工作流ID: %d, 错误: %v\n", id, err)
+		return dto.Error[any]("删除工作流失败", http.StatusInternalServerError)
+	}
+	return dto.Success[any](nil)
+}
+
+// UpdateStatus 更新工作流状态
+func (s *ApiWorkflowService) UpdateStatus(id uint64, status int) dto.ApiData[dto.ApiWorkflowDto] {
+	workflow, err := s.workflowRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiWorkflowDto]("工作流不存在", http.StatusNotFound)
+	}
+
+	workflow.Status = status
+	if err := s.workflowRepo.Update(workflow); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新工作流状态失败，工作流ID: %d, 状态: %d, 错误: %v\n", id, status, err)
+		return dto.Error[dto.ApiWorkflowDto]("更新状态失败", http.StatusInternalServerError)
+	}
+
+	return dto.Success(s.convertToDto(workflow))
+}
+
+// Run 执行工作流
+// 按顺序执行各步骤，步骤失败时除非配置了 continueOnFailure 否则终止执行，剩余步骤标记为跳过
+func (s *ApiWorkflowService) Run(id uint64, req dto.ApiWorkflowRunRequestDto, executorID uint64, clientIP, userAgent string) dto.ApiData[dto.ApiWorkflowRunDto] {
+	startTime := time.Now()
+
+	workflow, err := s.workflowRepo.FindByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiWorkflowRunDto]("工作流不存在", http.StatusNotFound)
+	}
+	if workflow.Status != 1 {
+		return dto.Error[dto.ApiWorkflowRunDto]("工作流已禁用，无法执行", http.StatusBadRequest)
+	}
+
+	steps := parseWorkflowSteps(workflow)
+	if len(steps) == 0 {
+		return dto.Error[dto.ApiWorkflowRunDto]("工作流没有配置步骤", http.StatusBadRequest)
+	}
+
+	executorName := "未知用户"
+	if user, err := s.userRepo.FindByUID(executorID); err == nil {
+		executorName = user.Username
+	}
+
+	environmentID := workflow.EnvironmentID
+	if req.EnvironmentID != nil {
+		environmentID = req.EnvironmentID
+	}
+
+	// 先创建执行记录，以便各步骤的接口执行记录关联到本次执行
+	run := &entity.ApiWorkflowRun{
+		WorkflowID:    workflow.ID,
+		WorkflowName:  workflow.Name,
+		EnvironmentID: environmentID,
+		ExecutorID:    executorID,
+		ExecutorName:  executorName,
+		TotalSteps:    len(steps),
+	}
+	if err := s.workflowRepo.CreateRun(run); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "创建工作流执行记录失败，工作流ID: %d, 错误: %v\n", id, err)
+		return dto.Error[dto.ApiWorkflowRunDto]("执行工作流失败", http.StatusInternalServerError)
+	}
+
+	results := make([]dto.ApiWorkflowStepResultDto, len(steps))
+	responses := make([]*dto.ApiExecuteResponseDto, len(steps))
+	run.Success = true
+	stopped := false
+
+	for i, step := range steps {
+		result := dto.ApiWorkflowStepResultDto{
+			StepIndex:   i + 1,
+			Name:        step.Name,
+			InterfaceID: *step.InterfaceID,
+		}
+		if stopped {
+			result.Skipped = true
+			results[i] = result
+			continue
+		}
+
+		executeReq, err := s.buildStepRequest(step, i, responses, environmentID, req)
+		if err != nil {
+			result.Error = stringPtr(err.Error())
+		} else {
+			executeReq.WorkflowRunID = basic.Ptr(run.ID)
+			executeResult := s.apiInterfaceService.Execute(*executeReq, executorID, clientIP, userAgent)
+			if executeResult.Code != http.StatusOK {
+				result.Error = maskURLsInText(stringPtr(executeResult.Message))
+			} else {
+				response := executeResult.Data
+				responses[i] = &response
+				result.RecordID = response.RecordID
+				result.Status = response.Status
+				result.Success = response.Success
+				result.ExtractedValue = response.ExtractedValue
+				result.ResponseTime = response.ResponseTime
+				result.Error = response.Error
+			}
+		}
+
+		if result.Success {
+			run.SuccessSteps++
+		} else {
+			run.Success = false
+			stopped = !step.ContinueOnFailure
+		}
+		results[i] = result
+	}
+
+	// 后续步骤使用原始取值，保存的步骤结果按脱敏规则处理
+	stepRefs := make([]*dto.ApiWorkflowStepResultDto, len(results))
+	for i := range results {
+		stepRefs[i] = &results[i]
+	}
+	s.redactStepResults(stepRefs)
+	run.ErrorMessage = workflowRunErrorMessage(results)
+
+	if jsonBytes, err := json.Marshal(results); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "序列化工作流步骤结果失败: %v\n", err)
+	} else {
+		run.StepResults = stringPtr(string(jsonBytes))
+	}
+	run.ExecutionTime = time.Since(startTime).Milliseconds()
+
+	if err := s.workflowRepo.UpdateRun(run); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "更新工作流执行记录失败，执行记录ID: %d, 错误: %v\n", run.ID, err)
+	}
+
+	return dto.Success(s.convertRunToDto(run))
+}
+
+// FindRunPage 分页查询工作流执行记录，只返回各步骤接口均可见的记录
+func (s *ApiWorkflowService) FindRunPage(query dto.ApiWorkflowRunQueryDto, uid uint64) dto.ApiData[dto.PageResult[dto.ApiWorkflowRunDto]] {
+	s.aclService.ApplyRunVisibility(&query, uid)
+	runs, total, err := s.workflowRepo.PageRuns(query)
+	result := PageResultBuilder(runs, total, err, s.convertRunToDto, &query)
+	if result.Code == http.StatusOK {
+		stepRefs := make([]*dto.ApiWorkflowStepResultDto, 0)
+		for i := range result.Data.Records {
+			for j := range result.Data.Records[i].Steps {
+				stepRefs = append(stepRefs, &result.Data.Records[i].Steps[j])
+			}
+		}
+		s.redactStepResults(stepRefs)
+		for i := range result.Data.Records {
+			result.Data.Records[i].ErrorMessage = redactRunErrorMessage(&result.Data.Records[i])
+		}
+	}
+	return result
+}
+
+// FindRunByID 根据ID查询工作流执行记录，需要各步骤接口的查看权限
+func (s *ApiWorkflowService) FindRunByID(id uint64, uid uint64) dto.ApiData[dto.ApiWorkflowRunDto] {
+	run, err := s.workflowRepo.FindRunByID(id)
+	if err != nil {
+		return dto.Error[dto.ApiWorkflowRunDto]("执行记录不存在", http.StatusNotFound)
+	}
+	result := s.convertRunToDto(run)
+	stepRefs := make([]*dto.ApiWorkflowStepResultDto, len(result.Steps))
+	for i := range result.Steps {
+		if err := s.aclService.CheckPermission(result.Steps[i].InterfaceID, uid, enums.InterfacePermissionView); err != nil {
+			return dto.Error[dto.ApiWorkflowRunDto](fmt.Sprintf("步骤%d: %s", result.Steps[i].StepIndex, err.Error()), http.StatusForbidden)
+		}
+		stepRefs[i] = &result.Steps[i]
+	}
+	s.redactStepResults(stepRefs)
+	result.ErrorMessage = redactRunErrorMessage(&result)
+	return dto.Success(result)
+}
+
+// redactStepResults 按步骤执行记录中的响应体重新提取并脱敏步骤取值（兼容历史数据及规则调整）
+// 找不到执行记录或接口时无法判断取值是否敏感，整体脱敏
+// 步骤错误信息同时脱敏，早期记录会将失败响应体写入错误信息，按状态码重新生成
+func (s *ApiWorkflowService) redactStepResults(steps []*dto.ApiWorkflowStepResultDto) {
+	for _, step := range steps {
+		step.Error = redactExecutionError(step.Status, step.Error)
+	}
+	if !s.redactor.redactsResponseBody() {
+		return
+	}
+	recordIDs := make([]uint64, 0)
+	interfaceIDs := make([]uint64, 0)
+	for _, step := range steps {
+		if step.ExtractedValue != nil && step.RecordID != nil {
+			recordIDs = append(recordIDs, *step.RecordID)
+			interfaceIDs = append(interfaceIDs, step.InterfaceID)
+		}
+	}
+	records := make(map[uint64]*entity.ApiInterfaceExecutionRecord)
+	if list, err := s.recordRepo.FindByIDs(recordIDs); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询工作流步骤执行记录失败: %v\n", err)
+	} else {
+		for i := range list {
+			records[list[i].ID] = &list[i]
+		}
+	}
+	interfaces := make(map[uint64]*entity.ApiInterface)
+	if list, err := s.apiInterfaceRepo.FindAllByIDs(interfaceIDs); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "查询工作流步骤接口失败: %v\n", err)
+	} else {
+		for i := range list {
+			interfaces[list[i].ID] = &list[i]
+		}
+	}
+
+	for _, step := range steps {
+		if step.ExtractedValue == nil {
+			continue
+		}
+		var record *entity.ApiInterfaceExecutionRecord
+		if step.RecordID != nil {
+			record = records[*step.RecordID]
+		}
+		apiInterface := interfaces[step.InterfaceID]
+		if record == nil || apiInterface == nil || apiInterface.ValuePath == nil || *apiInterface.ValuePath == "" {
+			step.ExtractedValue = stringPtr(redactionMask)
+			continue
+		}
+		step.ExtractedValue = s.redactor.RedactExtractedValue(record.ResponseBody, *apiInterface.ValuePath, step.ExtractedValue)
+	}
+}
+
+// workflowRunErrorMessage 根据第一个失败的步骤生成执行记录的错误信息，全部成功时返回空
+func workflowRunErrorMessage(steps []dto.ApiWorkflowStepResultDto) *string {
+	for _, step := range steps {
+		if step.Success || step.Skipped {
+			continue
+		}
+		if step.Error != nil {
+			return stringPtr(fmt.Sprintf("步骤%d执行失败: %s", step.StepIndex, *step.Error))
+		}
+		return stringPtr(fmt.Sprintf("步骤%d执行失败", step.StepIndex))
+	}
+	return nil
+}
+
+// redactRunErrorMessage 使用脱敏后的步骤结果重新生成执行记录的错误信息，兼容早期未脱敏的记录
+func redactRunErrorMessage(run *dto.ApiWorkflowRunDto) *string {
+	if run.ErrorMessage == nil {
+		return nil
+	}
+	if message := workflowRunErrorMessage(run.Steps); message != nil {
+		return message
+	}
+	return maskURLsInText(run.ErrorMessage)
+}
+
+// buildStepRequest 构建步骤的接口执行请求，并将前序步骤的取值写入对应参数
+func (s *ApiWorkflowService) buildStepRequest(
+	step dto.ApiWorkflowStepDto,
+	index int,
+	responses []*dto.ApiExecuteResponseDto,
+	environmentID *uint64,
+	runReq dto.ApiWorkflowRunRequestDto,
+) (*dto.ApiExecuteRequestDto, error) {
+	req := &dto.ApiExecuteRequestDto{
+		InterfaceID:   step.InterfaceID,
+		EnvironmentID: environmentID,
+		Headers:       make(map[string]string),
+		URLParams:     make(map[string]any),
+		PathParams:    make(map[string]any),
+		BodyParams:    make(map[string]any),
+		Variables:     make(map[string]string),
+		Remark:        runReq.Remark,
+	}
+	for k, v := range step.Headers {
+		req.Headers[k] = v
+	}
+	for k, v := range step.URLParams {
+		req.URLParams[k] = v
+	}
+	for k, v := range step.PathParams {
+		req.PathParams[k] = v
+	}
+	for k, v := range step.BodyParams {
+		req.BodyParams[k] = v
+	}
+	for k, v := range runReq.Variables {
+		req.Variables[k] = v
+	}
+	for k, v := range step.Variables {
+		req.Variables[k] = v
+	}
+
+	for _, mapping := range step.Mappings {
+		if mapping.SourceStep < 1 || mapping.SourceStep > index {
+			return nil, fmt.Errorf("取值来源步骤 %d 无效", mapping.SourceStep)
+		}
+		source := responses[mapping.SourceStep-1]
+		if source == nil {
+			return nil, fmt.Errorf("取值来源步骤 %d 没有响应", mapping.SourceStep)
+		}
+
+		var value *string
+		if mapping.SourcePath == nil || *mapping.SourcePath == "" {
+			value = source.ExtractedValue
+		} else if source.Body != nil {
+			value = s.apiInterfaceService.extractValueByPath(*source.Body, *mapping.SourcePath)
+		}
+		if value == nil {
+			return nil, fmt.Errorf("无法从步骤 %d 的响应中获取 %s 的取值", mapping.SourceStep, mapping.TargetName)
+		}
+
+		switch mapping.TargetType {
+		case enums.ParamTypeURL.Code():
+			req.URLParams[mapping.TargetName] = *value
+		case enums.ParamTypePath.Code():
+			req.PathParams[mapping.TargetName] = *value
+		case enums.ParamTypeHeader.Code():
+			req.Headers[mapping.TargetName] = *value
+		case enums.ParamTypeBody.Code():
+			req.BodyParams[mapping.TargetName] = *value
+		case workflowVariableTarget:
+			req.Variables[mapping.TargetName] = *value
+		}
+	}
+	return req, nil
+}
+
+// validateForm 验证工作流表单
+func (s *ApiWorkflowService) validateForm(form *dto.ApiWorkflowFormDto) error {
+	if form.EnvironmentID != nil {
+		if _, err := s.apiEnvironmentRepo.FindByID(*form.EnvironmentID); err != nil {
+			return fmt.Errorf("默认环境不存在")
+		}
+	}
+
+	for i, step := range form.Steps {
+		index := i + 1
+		if step.InterfaceID == nil {
+			return fmt.Errorf("步骤%d未选择接口", index)
+		}
+		if _, err := s.apiInterfaceRepo.FindByID(*step.InterfaceID); err != nil {
+			return fmt.Errorf("步骤%d的接口不存在", index)
+		}
+		for _, mapping := range step.Mappings {
+			if mapping.SourceStep < 1 || mapping.SourceStep >= index {
+				return fmt.Errorf("步骤%d的取值来源必须是前序步骤", index)
+			}
+			if mapping.TargetName == "" {
+				return fmt.Errorf("步骤%d的取值映射缺少目标参数名", index)
+			}
+			switch mapping.TargetType {
+			case enums.ParamTypeURL.Code(), enums.ParamTypePath.Code(), enums.ParamTypeHeader.Code(),
+				enums.ParamTypeBody.Code(), workflowVariableTarget:
+			default:
+				return fmt.Errorf("步骤%d的取值映射目标类型不正确: %s", index, mapping.TargetType)
+			}
+		}
+	}
+	return nil
+}
+
+// applyForm 将表单内容写入实体
+func (s *ApiWorkflowService) applyForm(workflow *entity.ApiWorkflow, form *dto.ApiWorkflowFormDto) {
+	workflow.Name = form.Name
+	workflow.Description = form.Description
+	workflow.EnvironmentID = form.EnvironmentID
+	workflow.Steps = nil
+	if jsonBytes, err := json.Marshal(form.Steps); err == nil {
+		workflow.Steps = basic.Ptr(string(jsonBytes))
+	}
+	if form.Status != nil {
+		workflow.Status = *form.Status
+	}
+}
+
+// convertToDto 转换实体为DTO
+func (s *ApiWorkflowService) convertToDto(workflow *entity.ApiWorkflow) dto.ApiWorkflowDto {
+	return dto.ApiWorkflowDto{
+		ID:            workflow.ID,
+		Name:          workflow.Name,
+		Description:   workflow.Description,
+		EnvironmentID: workflow.EnvironmentID,
+		Steps:         parseWorkflowSteps(workflow),
+		Status:        workflow.Status,
+		CreateTime:    util.Format(&workflow.CreateTime),
+		UpdateTime:    util.Format(&workflow.UpdateTime),
+	}
+}
+
+// convertRunToDto 转换执行记录实体为DTO
+func (s *ApiWorkflowService) convertRunToDto(run *entity.ApiWorkflowRun) dto.ApiWorkflowRunDto {
+	var steps []dto.ApiWorkflowStepResultDto
+	if run.StepResults != nil && *run.StepResults != "" {
+		if err := json.Unmarshal([]byte(*run.StepResults), &steps); err != nil {
+			logx.Errorf(context.Background(), logx.NameApp, "解析工作流步骤结果失败，执行记录ID: %d, 错误: %v\n", run.ID, err)
+		}
+	}
+	return dto.ApiWorkflowRunDto{
+		ID:            run.ID,
+		WorkflowID:    run.WorkflowID,
+		WorkflowName:  run.WorkflowName,
+		EnvironmentID: run.EnvironmentID,
+		ExecutorID:    run.ExecutorID,
+		ExecutorName:  run.ExecutorName,
+		TotalSteps:    run.TotalSteps,
+		SuccessSteps:  run.SuccessSteps,
+		Steps:         steps,
+		ExecutionTime: run.ExecutionTime,
+		Success:       run.Success,
+		ErrorMessage:  run.ErrorMessage,
+		CreateTime:    util.Format(&run.CreateTime),
+	}
+}
+
+// parseWorkflowSteps 解析工作流步骤JSON
+func parseWorkflowSteps(workflow *entity.ApiWorkflow) []dto.ApiWorkflowStepDto {
+	steps := make([]dto.ApiWorkflowStepDto, 0)
+	if workflow.Steps == nil || *workflow.Steps == "" {
+		return steps
+	}
+	if err := json.Unmarshal([]byte(*workflow.Steps), &steps); err != nil {
+		logx.Errorf(context.Background(), logx.NameApp, "解析工作流步骤失败，工作流ID: %d, 错误: %v\n", workflow.ID, err)
+	}
+	return steps
+}
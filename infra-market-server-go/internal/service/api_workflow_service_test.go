@@ -0,0 +1,73 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bucketheadv/infra-market/internal/dto"
+)
+
+func TestRedactWorkflowStepErrors(t *testing.T) {
+	legacyBody := `{"error":"boom","token":"secret-token"}`
+	steps := []dto.ApiWorkflowStepResultDto{
+		{StepIndex: 1, Status: 200, Success: true},
+		{StepIndex: 2, Status: 500, Error: stringPtr(legacyBody)},
+		{StepIndex: 3, Error: stringPtr(`Get "https://a/b?api_key=secret-key": connection refused`)},
+		{StepIndex: 4, Skipped: true},
+	}
+	run := dto.ApiWorkflowRunDto{
+		Steps:        steps,
+		ErrorMessage: stringPtr("步骤2执行失败: " + legacyBody),
+	}
+	stepRefs := make([]*dto.ApiWorkflowStepResultDto, len(run.Steps))
+	for i := range run.Steps {
+		stepRefs[i] = &run.Steps[i]
+	}
+
+	s := &ApiWorkflowService{redactor: &SecretRedactor{}}
+	s.redactStepResults(stepRefs)
+	run.ErrorMessage = redactRunErrorMessage(&run)
+
+	if got := stringValue(run.Steps[0].Error); got != "" {
+		t.Errorf("成功步骤错误信息 = %q, want 空", got)
+	}
+	if got := stringValue(run.Steps[1].Error); got != "HTTP 500" {
+		t.Errorf("HTTP失败步骤错误信息 = %q, want HTTP 500", got)
+	}
+	if got := stringValue(run.Steps[2].Error); got != `Get "https://a/b?api_key=******": connection refused` {
+		t.Errorf("请求失败步骤错误信息 = %q", got)
+	}
+	if got := stringValue(run.ErrorMessage); got != "步骤2执行失败: HTTP 500" {
+		t.Errorf("执行记录错误信息 = %q, want 步骤2执行失败: HTTP 500", got)
+	}
+	for _, step := range run.Steps {
+		if strings.Contains(stringValue(step.Error), "secret") {
+			t.Errorf("步骤%d错误信息未脱敏: %s", step.StepIndex, stringValue(step.Error))
+		}
+	}
+}
+
+func TestWorkflowRunErrorMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []dto.ApiWorkflowStepResultDto
+		want  string // 为空表示没有错误信息
+	}{
+		{"全部成功", []dto.ApiWorkflowStepResultDto{{StepIndex: 1, Success: true}}, ""},
+		{"取第一个失败步骤", []dto.ApiWorkflowStepResultDto{
+			{StepIndex: 1, Success: true},
+			{StepIndex: 2, Error: stringPtr("HTTP 404")},
+			{StepIndex: 3, Error: stringPtr("HTTP 500")},
+		}, "步骤2执行失败: HTTP 404"},
+		{"失败没有错误信息", []dto.ApiWorkflowStepResultDto{{StepIndex: 1}}, "步骤1执行失败"},
+		{"忽略跳过的步骤", []dto.ApiWorkflowStepResultDto{{StepIndex: 1, Success: true}, {StepIndex: 2, Skipped: true}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stringValue(workflowRunErrorMessage(tt.steps)); got != tt.want {
+				t.Errorf("workflowRunErrorMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
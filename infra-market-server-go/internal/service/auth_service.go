@@ -17,6 +17,7 @@ import (
 type AuthService struct {
 	userRepo           *repository.UserRepository
 	userRoleRepo       *repository.UserRoleRepository
+	roleRepo           *repository.RoleRepository
 	rolePermissionRepo *repository.RolePermissionRepository
 	permissionRepo     *repository.PermissionRepository
 	tokenService       *TokenService
@@ -25,6 +26,7 @@ type AuthService struct {
 func NewAuthService(
 	userRepo *repository.UserRepository,
 	userRoleRepo *repository.UserRoleRepository,
+	roleRepo *repository.RoleRepository,
 	rolePermissionRepo *repository.RolePermissionRepository,
 	permissionRepo *repository.PermissionRepository,
 	tokenService *TokenService,
@@ -32,6 +34,7 @@ func NewAuthService(
 	return &AuthService{
 		userRepo:           userRepo,
 		userRoleRepo:       userRoleRepo,
+		roleRepo:           roleRepo,
 		rolePermissionRepo: rolePermissionRepo,
 		permissionRepo:     permissionRepo,
 		tokenService:       tokenService,
@@ -275,6 +278,63 @@ func (s *AuthService) ChangePassword(uid uint64, req dto.ChangePasswordRequest)
 	return dto.Success[any](nil)
 }
 
+// HasPermission 判断用户是否拥有指定编码的权限
+func (s *AuthService) HasPermission(uid uint64, code string) bool {
+	for _, permissionCode := range s.getUserPermissions(uid) {
+		if permissionCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRoleIDs 获取用户的有效角色ID列表
+func (s *AuthService) GetRoleIDs(uid uint64) []uint64 {
+	userRoles, err := s.userRoleRepo.FindByUID(uid)
+	if err != nil || len(userRoles) == 0 {
+		return []uint64{}
+	}
+
+	roleIDs := make([]uint64, 0, len(userRoles))
+	for _, ur := range userRoles {
+		if ur.RoleID != nil {
+			roleIDs = append(roleIDs, *ur.RoleID)
+		}
+	}
+	roles, err := s.roleRepo.FindByIDs(roleIDs)
+	if err != nil {
+		return []uint64{}
+	}
+
+	// 只返回激活状态的角色
+	activeIDs := make([]uint64, 0, len(roles))
+	for _, role := range roles {
+		if role.Status == enums.StatusActive.Code() {
+			activeIDs = append(activeIDs, role.ID)
+		}
+	}
+	return activeIDs
+}
+
+// HasRole 判断用户是否拥有指定角色
+func (s *AuthService) HasRole(uid uint64, roleID uint64) bool {
+	for _, id := range s.GetRoleIDs(uid) {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin 判断用户是否拥有系统管理员角色
+func (s *AuthService) IsAdmin(uid uint64) bool {
+	admin, err := s.roleRepo.FindByCode(enums.AdminRoleCode)
+	if err != nil {
+		return false
+	}
+	return s.HasRole(uid, admin.ID)
+}
+
 // getUserPermissions 获取用户权限编码列表
 func (s *AuthService) getUserPermissions(uid uint64) []string {
 	userRoles, err := s.userRoleRepo.FindByUID(uid)
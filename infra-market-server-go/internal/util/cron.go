@@ -0,0 +1,196 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule 解析后的 cron 表达式
+// 支持标准5段格式（分 时 日 月 周），以及 @hourly、@daily、@weekly、@monthly、@yearly 等简写
+type CronSchedule struct {
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	domStar  bool
+	dowStar  bool
+	location *time.Location
+}
+
+// cronBounds cron 表达式各字段的取值范围
+type cronBounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinuteBounds = cronBounds{min: 0, max: 59}
+	cronHourBounds   = cronBounds{min: 0, max: 23}
+	cronDomBounds    = cronBounds{min: 1, max: 31}
+	cronMonthBounds  = cronBounds{min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	cronDowBounds = cronBounds{min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+// cronDescriptors cron 简写
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron 解析 cron 表达式，timeZone 为空时使用服务器本地时区
+func ParseCron(expression, timeZone string) (*CronSchedule, error) {
+	location := time.Local
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("无效的时区: %s", timeZone)
+		}
+		location = loc
+	}
+
+	expression = strings.TrimSpace(expression)
+	if descriptor, ok := cronDescriptors[strings.ToLower(expression)]; ok {
+		expression = descriptor
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron表达式应包含5个字段（分 时 日 月 周）: %s", expression)
+	}
+
+	schedule := &CronSchedule{location: location}
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], cronMinuteBounds); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseCronField(fields[1], cronHourBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dom, err = parseCronField(fields[2], cronDomBounds); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseCronField(fields[3], cronMonthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dow, err = parseCronField(fields[4], cronDowBounds); err != nil {
+		return nil, err
+	}
+	// 周日可以写作 0 或 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	// 与标准 cron 一致，以 * 开头的字段（如 */2）视为未限制，日和周之间按“且”匹配
+	schedule.domStar = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	schedule.dowStar = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+	return schedule, nil
+}
+
+// Next 返回晚于给定时间的下一次触发时间，5年内没有可触发时间时返回零值
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(c.location)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, c.location).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.location)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.location)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.location)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断日期是否匹配；日和周同时指定时满足其一即可（与标准 cron 一致）
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parseCronField 解析单个字段，支持 *、?、列表、区间和步长
+func parseCronField(field string, bounds cronBounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			s, err := strconv.Atoi(part[idx+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("cron字段步长无效: %s", part)
+			}
+			step = s
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			start, end = bounds.min, bounds.max
+		case strings.Contains(rangePart, "-"):
+			ends := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseCronValue(ends[0], bounds); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(ends[1], bounds); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := parseCronValue(rangePart, bounds)
+			if err != nil {
+				return 0, err
+			}
+			start, end = value, value
+			if step > 1 {
+				end = bounds.max
+			}
+		}
+
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, fmt.Errorf("cron字段超出范围: %s", part)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue 解析字段中的单个取值，支持月份和星期的英文缩写
+func parseCronValue(value string, bounds cronBounds) (int, error) {
+	if v, ok := bounds.names[strings.ToUpper(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("cron字段无效: %s", value)
+	}
+	return v, nil
+}
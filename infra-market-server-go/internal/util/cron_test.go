@@ -0,0 +1,76 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		timeZone   string
+		from       string
+		want       string
+	}{
+		{"步长", "*/15 * * * *", "UTC", "2026-10-17T10:07:30Z", "2026-10-17T10:15:00Z"},
+		{"严格晚于给定时间", "@hourly", "UTC", "2026-10-17T10:00:00Z", "2026-10-17T11:00:00Z"},
+		{"工作日", "0 9 * * 1-5", "UTC", "2026-10-16T10:00:00Z", "2026-10-19T09:00:00Z"},
+		{"跨年", "@yearly", "UTC", "2026-10-17T10:00:00Z", "2027-01-01T00:00:00Z"},
+		{"列表", "0 8,20 * * *", "UTC", "2026-10-17T10:00:00Z", "2026-10-17T20:00:00Z"},
+		{"月份缩写", "0 0 1 FEB *", "UTC", "2026-10-17T10:00:00Z", "2027-02-01T00:00:00Z"},
+		{"周日写作7", "0 0 * * 7", "UTC", "2026-10-17T10:00:00Z", "2026-10-18T00:00:00Z"},
+		{"星期缩写", "30 12 * * SUN", "UTC", "2026-10-17T10:00:00Z", "2026-10-18T12:30:00Z"},
+		{"日和周同时指定时满足其一", "0 0 13 * 5", "UTC", "2026-10-17T10:00:00Z", "2026-10-23T00:00:00Z"},
+		{"日为星号步长时与周同时满足", "0 0 */2 * 1", "UTC", "2026-10-20T10:00:00Z", "2026-11-09T00:00:00Z"},
+		{"周为星号步长时与日同时满足", "0 0 19 * */2", "UTC", "2026-10-17T10:00:00Z", "2026-11-19T00:00:00Z"},
+		{"问号", "0 0 ? * 1", "UTC", "2026-10-17T10:00:00Z", "2026-10-19T00:00:00Z"},
+		{"闰日", "0 0 29 2 *", "UTC", "2026-10-17T10:00:00Z", "2028-02-29T00:00:00Z"},
+		{"时区", "0 9 * * *", "Asia/Shanghai", "2026-10-17T02:00:00Z", "2026-10-18T01:00:00Z"},
+		{"不存在的日期", "0 0 30 2 *", "UTC", "2026-10-17T10:00:00Z", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expression, tt.timeZone)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) error: %v", tt.expression, err)
+			}
+			from, _ := time.Parse(time.RFC3339, tt.from)
+			got := schedule.Next(from)
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Fatalf("Next() = %v, want zero", got)
+				}
+				return
+			}
+			want, _ := time.Parse(time.RFC3339, tt.want)
+			if !got.Equal(want) {
+				t.Fatalf("Next() = %v, want %v", got.UTC(), want)
+			}
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		timeZone   string
+	}{
+		{"字段数量不足", "* * * *", "UTC"},
+		{"分钟超出范围", "60 * * * *", "UTC"},
+		{"步长为0", "*/0 * * * *", "UTC"},
+		{"区间倒置", "5-1 * * * *", "UTC"},
+		{"未知名称", "0 0 * * FUNDAY", "UTC"},
+		{"无效时区", "* * * * *", "Mars/Olympus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCron(tt.expression, tt.timeZone); err == nil {
+				t.Fatalf("ParseCron(%q, %q) expected error", tt.expression, tt.timeZone)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// secretCipherPrefix 使用 SecretCipher 加密的密文前缀，用于与早期使用默认密钥加密的密文区分
+const secretCipherPrefix = "v2:"
+
+// SecretCipher 加密保存的敏感配置（认证密钥、客户端私钥、代理密码等）
+// 使用配置的密钥派生的 AES-256-GCM 加密，每次加密使用随机 nonce；
+// 解密时兼容早期使用默认密钥加密的密文，重新保存后即使用新的密钥
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher 根据配置的密钥创建加密器，密钥不能为空
+func NewSecretCipher(key string) (*SecretCipher, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, errors.New("未配置敏感信息加密密钥 security.secret_key")
+	}
+	keyBytes := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(keyBytes[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+// Encrypt 加密字符串
+func (c *SecretCipher) Encrypt(text string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(text), nil)
+	return secretCipherPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密字符串，没有前缀的密文按早期默认密钥解密
+func (c *SecretCipher) Decrypt(encryptedText string) (string, error) {
+	encoded, ok := strings.CutPrefix(encryptedText, secretCipherPrefix)
+	if !ok {
+		return Decrypt(encryptedText)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSecretCipher(t *testing.T) {
+	if _, err := NewSecretCipher(" "); err == nil {
+		t.Fatalf("NewSecretCipher() 未配置密钥时应返回错误")
+	}
+	c, err := NewSecretCipher("test-secret-key")
+	if err != nil {
+		t.Fatalf("NewSecretCipher() error: %v", err)
+	}
+
+	first, err := c.Encrypt("client-secret")
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+	second, _ := c.Encrypt("client-secret")
+	if first == second {
+		t.Errorf("相同明文两次加密的密文应不同")
+	}
+	if !strings.HasPrefix(first, secretCipherPrefix) {
+		t.Errorf("密文 %q 缺少前缀 %q", first, secretCipherPrefix)
+	}
+	if got, err := c.Decrypt(first); err != nil || got != "client-secret" {
+		t.Errorf("Decrypt() = %q, %v, want client-secret", got, err)
+	}
+
+	// 其他密钥无法解密
+	other, _ := NewSecretCipher("other-secret-key")
+	if _, err := other.Decrypt(first); err == nil {
+		t.Errorf("使用其他密钥解密应返回错误")
+	}
+
+	// 兼容早期使用默认密钥加密的密文
+	legacy, _ := Encrypt("legacy-secret")
+	if got, err := c.Decrypt(legacy); err != nil || got != "legacy-secret" {
+		t.Errorf("Decrypt(legacy) = %q, %v, want legacy-secret", got, err)
+	}
+}
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templatePattern 匹配 {{name}} 形式的占位符
+var templatePattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// TemplateRenderer 变量模板渲染器
+// 按顺序合并多个变量来源，后传入的来源覆盖先传入的同名变量
+type TemplateRenderer struct {
+	variables map[string]string
+	missing   map[string]struct{}
+}
+
+// NewTemplateRenderer 创建模板渲染器
+func NewTemplateRenderer(sources ...map[string]string) *TemplateRenderer {
+	variables := make(map[string]string)
+	for _, source := range sources {
+		for k, v := range source {
+			variables[k] = v
+		}
+	}
+	return &TemplateRenderer{
+		variables: variables,
+		missing:   make(map[string]struct{}),
+	}
+}
+
+// RenderString 替换字符串中的占位符，无法解析的占位符保持原样并记录
+func (r *TemplateRenderer) RenderString(text string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	return templatePattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := templatePattern.FindStringSubmatch(match)[1]
+		if value, ok := r.resolve(name); ok {
+			return value
+		}
+		r.missing[name] = struct{}{}
+		return match
+	})
+}
+
+// RenderValue 递归替换任意值中的占位符，支持嵌套的对象和数组
+func (r *TemplateRenderer) RenderValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return r.RenderString(v)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = r.RenderValue(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = r.RenderValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// Missing 返回渲染过程中未解析的占位符名称
+func (r *TemplateRenderer) Missing() []string {
+	names := make([]string, 0, len(r.missing))
+	for name := range r.missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolve 解析单个变量，$ 开头的为内置变量
+func (r *TemplateRenderer) resolve(name string) (string, bool) {
+	if strings.HasPrefix(name, "$") {
+		return resolveBuiltinVariable(name)
+	}
+	value, ok := r.variables[name]
+	return value, ok
+}
+
+// resolveBuiltinVariable 解析内置变量
+// 支持 $timestamp、$timestampMs、$uuid、$randomInt 以及 $date:布局（Go 时间布局，默认 2006-01-02）
+func resolveBuiltinVariable(name string) (string, bool) {
+	now := time.Now()
+	switch {
+	case name == "$timestamp":
+		return strconv.FormatInt(now.Unix(), 10), true
+	case name == "$timestampMs":
+		return strconv.FormatInt(now.UnixMilli(), 10), true
+	case name == "$uuid":
+		return newUUID(), true
+	case name == "$randomInt":
+		n, err := rand.Int(rand.Reader, big.NewInt(1001))
+		if err != nil {
+			return "", false
+		}
+		return n.String(), true
+	case name == "$date":
+		return now.Format("2006-01-02"), true
+	case strings.HasPrefix(name, "$date:"):
+		layout := strings.TrimPrefix(name, "$date:")
+		if layout == "" {
+			layout = "2006-01-02"
+		}
+		return now.Format(layout), true
+	}
+	return "", false
+}
+
+// newUUID 生成随机 UUID（版本4）
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
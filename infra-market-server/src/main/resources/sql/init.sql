@@ -359,3 +359,363 @@ INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `updat
 SELECT 4, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code IN (
     'activity:manage', 'activity:list:manage', 'activity:list', 'activity:view', 'activity:template:manage', 'activity:template:list', 'activity:template:view', 'activity:component:manage', 'activity:component:list', 'activity:component:view'
 );
+
+-- 接口执行环境表
+CREATE TABLE IF NOT EXISTS `api_environment` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '环境名称',
+    `code` VARCHAR(50) NOT NULL COMMENT '环境编码',
+    `base_url` VARCHAR(500) NOT NULL COMMENT '基础地址，接口URL为相对路径时与其拼接',
+    `variables` TEXT NULL COMMENT '环境变量JSON',
+    `description` VARCHAR(500) NULL COMMENT '环境描述',
+    `sort` INT NOT NULL DEFAULT 0 COMMENT '排序',
+    `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态：1-启用，0-禁用',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_code` (`code`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口执行环境表';
+
+-- 接口与执行记录关联执行环境
+ALTER TABLE `api_interface`
+    ADD COLUMN `environment_id` BIGINT NULL COMMENT '默认执行环境ID' AFTER `environment`,
+    ADD KEY `idx_environment_id` (`environment_id`);
+
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `environment_id` BIGINT NULL COMMENT '执行环境ID' AFTER `interface_id`,
+    ADD KEY `idx_environment_id` (`environment_id`);
+
+-- 执行记录保存路径参数
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `request_path_params` TEXT NULL COMMENT '路径参数JSON' AFTER `request_params`;
+
+-- 执行记录保存上传文件信息
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `request_files` TEXT NULL COMMENT '上传文件信息JSON（文件名、大小等，不含文件内容）' AFTER `request_body`;
+
+-- 接口请求体模式
+ALTER TABLE `api_interface`
+    ADD COLUMN `body_mode` VARCHAR(20) NULL COMMENT '请求体模式：PARAMS-按参数组装，RAW-原始请求体' AFTER `post_type`,
+    ADD COLUMN `raw_body` LONGTEXT NULL COMMENT '原始请求体模板' AFTER `body_mode`;
+
+-- 接口重定向与HTTP版本配置
+ALTER TABLE `api_interface`
+    MODIFY COLUMN `method` VARCHAR(20) NOT NULL COMMENT '请求方法：GET、POST、PUT、DELETE等',
+    ADD COLUMN `follow_redirects` TINYINT(1) NULL COMMENT '是否跟随重定向' AFTER `timeout`,
+    ADD COLUMN `max_redirects` INT NULL COMMENT '最大重定向次数' AFTER `follow_redirects`,
+    ADD COLUMN `http_version` VARCHAR(20) NULL COMMENT 'HTTP版本' AFTER `max_redirects`;
+
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `redirect_chain` TEXT NULL COMMENT '重定向链JSON' AFTER `response_body`;
+
+-- 接口响应断言
+ALTER TABLE `api_interface`
+    ADD COLUMN `assertions` TEXT NULL COMMENT '响应断言配置JSON' AFTER `value_path`;
+
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `assertion_results` TEXT NULL COMMENT '断言结果JSON' AFTER `redirect_chain`;
+
+-- 接口工作流表
+CREATE TABLE IF NOT EXISTS `api_workflow` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '工作流名称',
+    `description` VARCHAR(500) NULL COMMENT '工作流描述',
+    `environment_id` BIGINT NULL COMMENT '默认执行环境ID',
+    `steps` LONGTEXT NULL COMMENT '执行步骤JSON数组',
+    `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态：1-启用，0-禁用',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    KEY `idx_environment_id` (`environment_id`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口工作流表';
+
+-- 接口工作流执行记录表
+CREATE TABLE IF NOT EXISTS `api_workflow_run` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `workflow_id` BIGINT NOT NULL COMMENT '工作流ID',
+    `workflow_name` VARCHAR(100) NOT NULL COMMENT '工作流名称',
+    `environment_id` BIGINT NULL COMMENT '执行环境ID',
+    `executor_id` BIGINT NOT NULL COMMENT '执行人ID',
+    `executor_name` VARCHAR(50) NOT NULL COMMENT '执行人姓名',
+    `total_steps` INT NOT NULL DEFAULT 0 COMMENT '步骤总数',
+    `success_steps` INT NOT NULL DEFAULT 0 COMMENT '成功步骤数',
+    `step_results` LONGTEXT NULL COMMENT '各步骤执行结果JSON',
+    `execution_time` BIGINT NULL COMMENT '执行时间（毫秒）',
+    `success` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否成功：1-成功，0-失败',
+    `error_message` TEXT NULL COMMENT '错误信息',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    KEY `idx_workflow_id` (`workflow_id`),
+    KEY `idx_executor_id` (`executor_id`),
+    KEY `idx_success` (`success`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口工作流执行记录表';
+
+-- 执行记录关联工作流执行
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `workflow_run_id` BIGINT NULL COMMENT '工作流执行记录ID' AFTER `environment_id`,
+    ADD KEY `idx_workflow_run_id` (`workflow_run_id`);
+
+-- 接口定时执行计划表
+CREATE TABLE IF NOT EXISTS `api_schedule` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '计划名称',
+    `interface_id` BIGINT NOT NULL COMMENT '接口ID',
+    `cron_expression` VARCHAR(100) NOT NULL COMMENT 'cron表达式',
+    `time_zone` VARCHAR(50) NOT NULL COMMENT '时区',
+    `params` LONGTEXT NULL COMMENT '执行参数JSON',
+    `description` VARCHAR(500) NULL COMMENT '计划描述',
+    `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态：1-启用，0-暂停',
+    `next_fire_time` BIGINT NULL COMMENT '下次触发时间（毫秒时间戳）',
+    `last_fire_time` BIGINT NULL COMMENT '最近一次触发时间（毫秒时间戳）',
+    `last_success` TINYINT(1) NULL COMMENT '最近一次执行是否成功',
+    `last_record_id` BIGINT NULL COMMENT '最近一次执行记录ID',
+    `last_error` TEXT NULL COMMENT '最近一次执行错误信息',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    KEY `idx_interface_id` (`interface_id`),
+    KEY `idx_status` (`status`),
+    KEY `idx_next_fire_time` (`next_fire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口定时执行计划表';
+
+-- 执行记录关联定时计划
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `schedule_id` BIGINT NULL COMMENT '定时计划ID' AFTER `workflow_run_id`,
+    ADD KEY `idx_schedule_id` (`schedule_id`);
+
+-- 接口批量执行表
+CREATE TABLE IF NOT EXISTS `api_batch_execution` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `interface_id` BIGINT NOT NULL COMMENT '接口ID',
+    `environment_id` BIGINT NULL COMMENT '执行环境ID',
+    `executor_id` BIGINT NOT NULL COMMENT '执行人ID',
+    `executor_name` VARCHAR(50) NOT NULL COMMENT '执行人姓名',
+    `file_name` VARCHAR(255) NULL COMMENT '数据集文件名',
+    `concurrency` INT NOT NULL DEFAULT 1 COMMENT '并发数',
+    `stop_on_error` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '失败时是否停止',
+    `status` VARCHAR(20) NOT NULL COMMENT '状态：RUNNING-执行中，COMPLETED-已完成，STOPPED-出错后停止，INTERRUPTED-已中断',
+    `total_rows` INT NOT NULL DEFAULT 0 COMMENT '数据总行数',
+    `finished_rows` INT NOT NULL DEFAULT 0 COMMENT '已完成行数',
+    `success_rows` INT NOT NULL DEFAULT 0 COMMENT '成功行数',
+    `failed_rows` INT NOT NULL DEFAULT 0 COMMENT '失败行数',
+    `finish_time` BIGINT NULL COMMENT '完成时间（毫秒时间戳）',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    KEY `idx_interface_id` (`interface_id`),
+    KEY `idx_executor_id` (`executor_id`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口批量执行表';
+
+-- 接口批量执行单行结果表
+CREATE TABLE IF NOT EXISTS `api_batch_row_result` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `batch_id` BIGINT NOT NULL COMMENT '批量执行ID',
+    `row_index` INT NOT NULL COMMENT '行号（从0开始）',
+    `row_data` TEXT NULL COMMENT '行数据JSON',
+    `record_id` BIGINT NULL COMMENT '执行记录ID',
+    `status` INT NOT NULL DEFAULT 0 COMMENT '响应状态码',
+    `success` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否成功：1-成功，0-失败',
+    `skipped` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否跳过',
+    `response_time` BIGINT NOT NULL DEFAULT 0 COMMENT '响应时间（毫秒）',
+    `error` TEXT NULL COMMENT '错误信息',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_batch_row` (`batch_id`, `row_index`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口批量执行单行结果表';
+
+-- 执行记录关联批量执行
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `batch_id` BIGINT NULL COMMENT '批量执行ID' AFTER `schedule_id`,
+    ADD KEY `idx_batch_id` (`batch_id`);
+
+-- 接口认证配置表
+CREATE TABLE IF NOT EXISTS `api_auth_profile` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '配置名称',
+    `type` VARCHAR(50) NOT NULL COMMENT '认证类型：BASIC、BEARER、API_KEY、HMAC、OAUTH2_CLIENT_CREDENTIALS',
+    `config` TEXT NULL COMMENT '非敏感配置JSON',
+    `secret` TEXT NULL COMMENT '密钥（加密存储）',
+    `description` VARCHAR(500) NULL COMMENT '配置描述',
+    `status` TINYINT NOT NULL DEFAULT 1 COMMENT '状态：1-启用，0-禁用',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口认证配置表';
+
+-- 接口与环境关联认证配置
+ALTER TABLE `api_interface`
+    ADD COLUMN `auth_profile_id` BIGINT NULL COMMENT '认证配置ID，优先于环境的认证配置' AFTER `environment_id`,
+    ADD KEY `idx_auth_profile_id` (`auth_profile_id`);
+
+ALTER TABLE `api_environment`
+    ADD COLUMN `auth_profile_id` BIGINT NULL COMMENT '认证配置ID' AFTER `variables`,
+    ADD KEY `idx_auth_profile_id` (`auth_profile_id`);
+
+-- 插入管理与引用认证配置的按钮权限，仅授予超级管理员和管理员
+SET @interface_manage_id = (SELECT id FROM `permission_info` WHERE code = 'interface:manage');
+INSERT INTO `permission_info` (`name`, `code`, `type`, `parent_id`, `path`, `icon`, `sort`, `status`, `create_time`, `update_time`) VALUES
+('认证配置管理', 'interface:auth-profile:manage', 'button', @interface_manage_id, NULL, NULL, 8, 'active', UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000),
+('认证配置使用', 'interface:auth-profile:use', 'button', @interface_manage_id, NULL, NULL, 9, 'active', UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000);
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 1, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code IN ('interface:auth-profile:manage', 'interface:auth-profile:use');
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 2, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code IN ('interface:auth-profile:manage', 'interface:auth-profile:use');
+
+-- 执行记录保存脱敏前的原始内容
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `raw_payload` LONGTEXT NULL COMMENT '脱敏前的原始内容（加密存储）' AFTER `user_agent`;
+
+-- 插入查看未脱敏执行记录的按钮权限，仅授予超级管理员和管理员
+SET @execution_record_view_id = (SELECT id FROM `permission_info` WHERE code = 'interface:execution:record:view');
+INSERT INTO `permission_info` (`name`, `code`, `type`, `parent_id`, `path`, `icon`, `sort`, `status`, `create_time`, `update_time`) VALUES
+('查看未脱敏记录', 'interface:execution:record:unmasked', 'button', @execution_record_view_id, NULL, NULL, 1, 'active', UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000);
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 1, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code = 'interface:execution:record:unmasked';
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 2, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code = 'interface:execution:record:unmasked';
+
+-- 执行记录关联重放来源
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `replay_of_id` BIGINT NULL COMMENT '重放来源的执行记录ID' AFTER `batch_id`,
+    ADD KEY `idx_replay_of_id` (`replay_of_id`);
+
+-- 执行记录保存响应体元信息
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `response_body_info` LONGTEXT NULL COMMENT '响应体元信息JSON（大小、类型、哈希、是否截断等）' AFTER `response_body`,
+    ADD COLUMN `response_blob_key` VARCHAR(100) NULL COMMENT '完整响应体在本地目录中的文件名' AFTER `response_body_info`;
+
+-- 接口与环境的传输层配置（TLS、代理、客户端证书）
+ALTER TABLE `api_interface`
+    ADD COLUMN `transport_config` TEXT NULL COMMENT '传输层配置JSON，优先于环境的传输层配置' AFTER `assertions`,
+    ADD COLUMN `transport_secret` TEXT NULL COMMENT '传输层敏感信息（客户端私钥、代理密码，加密存储）' AFTER `transport_config`;
+
+ALTER TABLE `api_environment`
+    ADD COLUMN `transport_config` TEXT NULL COMMENT '传输层配置JSON' AFTER `status`,
+    ADD COLUMN `transport_secret` TEXT NULL COMMENT '传输层敏感信息（客户端私钥、代理密码，加密存储）' AFTER `transport_config`;
+
+-- 插入跳过TLS证书校验的按钮权限，仅授予超级管理员和管理员
+SET @interface_manage_id = (SELECT id FROM `permission_info` WHERE code = 'interface:manage');
+INSERT INTO `permission_info` (`name`, `code`, `type`, `parent_id`, `path`, `icon`, `sort`, `status`, `create_time`, `update_time`) VALUES
+('跳过证书校验', 'interface:transport:insecure', 'button', @interface_manage_id, NULL, NULL, 7, 'active', UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000);
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 1, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code = 'interface:transport:insecure';
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 2, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code = 'interface:transport:insecure';
+
+-- 接口重试策略
+ALTER TABLE `api_interface`
+    ADD COLUMN `retry_policy` TEXT NULL COMMENT '重试策略JSON' AFTER `assertions`;
+
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `attempts` TEXT NULL COMMENT '重试时每次请求的结果JSON' AFTER `redirect_chain`;
+
+-- 执行记录保存参数取值来源
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `param_sources` TEXT NULL COMMENT '各参数取值的来源JSON（USER-用户传入，DEFAULT-默认值，LOCKED-固定值）' AFTER `attempts`;
+
+-- 接口定义版本表
+CREATE TABLE IF NOT EXISTS `api_interface_version` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `interface_id` BIGINT NOT NULL COMMENT '接口ID',
+    `version` INT NOT NULL COMMENT '版本号',
+    `action` VARCHAR(20) NOT NULL COMMENT '操作：BASELINE-基线，CREATE-新建，UPDATE-编辑，COPY-复制，RESTORE-恢复',
+    `restored_from` INT NULL COMMENT '恢复历史版本时对应的版本号',
+    `editor_id` BIGINT NULL COMMENT '保存人ID，基线版本为空',
+    `editor_name` VARCHAR(50) NULL COMMENT '保存人姓名',
+    `snapshot` LONGTEXT NOT NULL COMMENT '接口定义快照JSON，传输层密钥为密文',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_interface_version` (`interface_id`, `version`),
+    KEY `idx_editor_id` (`editor_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口定义版本表';
+
+-- 接口当前版本号与执行时的接口版本号
+ALTER TABLE `api_interface`
+    ADD COLUMN `version` INT NULL COMMENT '当前版本号' AFTER `transport_secret`;
+
+ALTER TABLE `api_interface_execution_record`
+    ADD COLUMN `interface_version` INT NULL COMMENT '执行时的接口版本号' AFTER `interface_id`;
+
+-- 接口分组表
+CREATE TABLE IF NOT EXISTS `api_interface_group` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '分组名称',
+    `parent_id` BIGINT NULL COMMENT '上级分组ID',
+    `description` VARCHAR(255) NULL COMMENT '分组描述',
+    `sort` INT NOT NULL DEFAULT 0 COMMENT '排序',
+    `owner_role_id` BIGINT NULL COMMENT '所属团队（角色ID），未设置时继承上级分组的团队',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    KEY `idx_parent_id` (`parent_id`),
+    KEY `idx_owner_role_id` (`owner_role_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口分组表';
+
+-- 接口标签表
+CREATE TABLE IF NOT EXISTS `api_interface_tag` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `name` VARCHAR(50) NOT NULL COMMENT '标签名称',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口标签表';
+
+-- 接口标签关联表
+CREATE TABLE IF NOT EXISTS `api_interface_tag_relation` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `interface_id` BIGINT NOT NULL COMMENT '接口ID',
+    `tag_id` BIGINT NOT NULL COMMENT '标签ID',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_interface_tag` (`interface_id`, `tag_id`),
+    KEY `idx_tag_id` (`tag_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口标签关联表';
+
+-- 接口所属分组
+ALTER TABLE `api_interface`
+    ADD COLUMN `group_id` BIGINT NULL COMMENT '所属分组ID，为空表示未分组' AFTER `environment_id`,
+    ADD KEY `idx_group_id` (`group_id`);
+
+-- 接口授权表
+CREATE TABLE IF NOT EXISTS `api_interface_acl` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `interface_id` BIGINT NOT NULL COMMENT '接口ID',
+    `subject_type` VARCHAR(20) NOT NULL COMMENT '授权对象类型：USER-用户，ROLE-角色',
+    `subject_id` BIGINT NOT NULL COMMENT '授权对象ID（用户ID或角色ID）',
+    `permission` VARCHAR(20) NOT NULL COMMENT '权限：VIEW-查看，EXECUTE-执行，EDIT-编辑，OWNER-负责人',
+    `create_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '创建时间（毫秒时间戳）',
+    `update_time` BIGINT NOT NULL DEFAULT (FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000)) COMMENT '更新时间（毫秒时间戳）',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_interface_subject` (`interface_id`, `subject_type`, `subject_id`),
+    KEY `idx_subject` (`subject_type`, `subject_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='接口授权表';
+
+-- 定时计划以创建人身份执行
+ALTER TABLE `api_schedule`
+    ADD COLUMN `creator_id` BIGINT NOT NULL DEFAULT 0 COMMENT '创建人ID，计划触发时以创建人身份执行，为0的历史计划需要重新保存' AFTER `last_error`;
+
+-- 插入管理执行环境的按钮权限，仅授予超级管理员和管理员
+SET @interface_manage_id = (SELECT id FROM `permission_info` WHERE code = 'interface:manage');
+INSERT INTO `permission_info` (`name`, `code`, `type`, `parent_id`, `path`, `icon`, `sort`, `status`, `create_time`, `update_time`) VALUES
+('执行环境管理', 'interface:environment:manage', 'button', @interface_manage_id, NULL, NULL, 10, 'active', UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000);
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 1, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code = 'interface:environment:manage';
+
+INSERT INTO `role_permission` (`role_id`, `permission_id`, `create_time`, `update_time`) 
+SELECT 2, id, UNIX_TIMESTAMP() * 1000, UNIX_TIMESTAMP() * 1000 FROM `permission_info` WHERE status = 'active' AND code = 'interface:environment:manage';